@@ -1,3 +1,3 @@
 package calendar
 
-//go:generate protoc --go_out=../../pkg/api/ --go-grpc_out=../../pkg/api calendar.proto
+//go:generate protoc -I . --go_out=../../pkg/api/ --go-grpc_out=../../pkg/api --grpc-gateway_out=../../pkg/api --openapiv2_out=../../pkg/api/calendarpb calendar.proto
@@ -0,0 +1,3 @@
+package queue
+
+//go:generate protoc --go_out=../../pkg/api/ notification.proto
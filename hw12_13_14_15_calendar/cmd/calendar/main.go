@@ -10,17 +10,29 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/calendar"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/feed"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/health"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
+	memoryqueue "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue/memory"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/scheduler"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/sender"
 	internalgrpc "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/grpc"
 	internalhttp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/http"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
 	memorystorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/memory"
 	sqlstorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/sql"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/tracing"
 	"golang.org/x/exp/slog"
 )
 
+// changeBufferSize is the number of changes buffered for a watcher before
+// it is dropped as too slow.
+const changeBufferSize = 100
+
 var configFile string
 
 func init() {
@@ -49,10 +61,50 @@ func main() {
 		os.Exit(1)
 	}
 
-	calendar := calendar.New(storage)
+	authenticator, err := auth.NewJWT(&config.Auth)
+	if err != nil {
+		log.Error("Init authenticator", "error", err)
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Init(&config.Tracing)
+	if err != nil {
+		log.Error("Init tracing", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error("Shutdown tracing", "error", err)
+		}
+	}()
+
+	// The scheduler uses the storage itself rather than its wrappers.
+	schedulerStorage := storage
+
+	checker := initHealth(storage)
+	changes := initChangeFeed(storage)
+	metrics, storage := initMetrics(storage)
+	calendar := calendar.New(tracing.NewStorage(storage), changes)
+
+	serverGRPC := internalgrpc.NewServer(log, calendar, authenticator, metrics, checker, &config.ServerGRPC)
+
+	gateway, err := serverGRPC.Gateway(context.Background())
+	if err != nil {
+		log.Error("Init gateway", "error", err)
+		os.Exit(1) //nolint:gocritic
+	}
+
+	legacyGateway, err := serverGRPC.Gateway(context.Background(), internalhttp.LegacyGatewayOptions()...)
+	if err != nil {
+		log.Error("Init legacy gateway", "error", err)
+		os.Exit(1)
+	}
 
-	serverHTTP := internalhttp.NewServer(log, calendar, &config.ServerHTTP)
-	serverGRPC := internalgrpc.NewServer(log, calendar, &config.ServerGRPC)
+	serverHTTP := internalhttp.NewServer(log, calendar, authenticator, metrics, checker, gateway, legacyGateway,
+		&config.ServerHTTP)
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
@@ -61,11 +113,11 @@ func main() {
 	go func() {
 		<-ctx.Done()
 
-		if closeDBConn != nil {
-			if err := closeDBConn(); err != nil {
-				log.Error("Close connection to database", "error", err)
-			}
-		}
+		// Readiness fails from now on. The servers keep serving until the
+		// probes notice it and no new traffic is routed to them.
+		checker.Shutdown()
+		log.Info("Draining before shutdown", "delay", config.ServerHTTP.ShutdownDelay)
+		time.Sleep(config.ServerHTTP.ShutdownDelay)
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 		defer cancel()
@@ -75,13 +127,21 @@ func main() {
 		}
 
 		serverGRPC.Stop()
+
+		if closeDBConn != nil {
+			if err := closeDBConn(); err != nil {
+				log.Error("Close connection to database", "error", err)
+			}
+		}
 	}()
 
 	log.Info("Calendar is running...",
 		slog.String("http/server", fmt.Sprintf("%s:%d", config.ServerHTTP.Host, config.ServerHTTP.Port)),
-		slog.String("grpc/server", fmt.Sprintf("%s:%d", config.ServerGRPC.Host, config.ServerGRPC.Port)))
+		slog.String("grpc/server", fmt.Sprintf("%s:%d", config.ServerGRPC.Host, config.ServerGRPC.Port)),
+		slog.String("tracing", config.Tracing.Exporter))
 
 	wg := &sync.WaitGroup{}
+	runScheduler(ctx, log, schedulerStorage, &config, wg)
 	wg.Add(2)
 
 	go func() {
@@ -103,6 +163,71 @@ func main() {
 	wg.Wait()
 }
 
+// runScheduler runs the scheduler and the sender inside the calendar if the
+// storage is in memory, as a scheduler process can not see the events of the
+// calendar then. The notifications go through the in-memory queue and the
+// queue settings other than its size are ignored.
+func runScheduler(ctx context.Context, log logger.ILogger, db calendar.Storage, cfg *config.Config, wg *sync.WaitGroup) { //nolint:lll
+	schedulerStorage, ok := db.(scheduler.Storage)
+	if !ok || cfg.Storage.Type != storage.InMemory {
+		return
+	}
+
+	queue := memoryqueue.New(cfg.Queue.Size)
+	scheduler := scheduler.New(log, schedulerStorage, queue, &cfg.Scheduler)
+	sender := sender.New(log, queue, sender.NewSinks(log, &cfg.Sender), &cfg.Sender)
+
+	log.Info("Scheduler is running in process...",
+		slog.Duration("interval", cfg.Scheduler.Interval),
+		slog.Duration("events_max_age", cfg.Scheduler.EventsMaxAge),
+		slog.Duration("trash_retention", cfg.Scheduler.TrashRetention))
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		scheduler.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		if err := sender.Run(ctx); err != nil {
+			log.Error("Run sender", "error", err)
+		}
+	}()
+}
+
+// initHealth creates the readiness checker, it pings the database of the SQL
+// storage.
+func initHealth(storage calendar.Storage) *health.Checker {
+	if pinger, ok := storage.(health.Pinger); ok {
+		return health.New(pinger)
+	}
+	return health.New()
+}
+
+type changePublisherSetter interface {
+	SetChangePublisher(publisher storage.ChangePublisher)
+}
+
+// initChangeFeed creates the feed the storage publishes the changes of events
+// to.
+func initChangeFeed(db calendar.Storage) *feed.Feed {
+	changes := feed.New(changeBufferSize)
+	if setter, ok := db.(changePublisherSetter); ok {
+		setter.SetChangePublisher(changes)
+	}
+	return changes
+}
+
+// initMetrics wraps the storage to record the latency of its operations and
+// exposes the number of events of the in-memory storage.
+func initMetrics(storage calendar.Storage) (*metrics.Metrics, calendar.Storage) {
+	m := metrics.New()
+	if counter, ok := storage.(metrics.EventCounter); ok {
+		m.RegisterEventCounter(counter)
+	}
+	return m, metrics.NewStorage(storage, m)
+}
+
 type CloseConnFn func() error
 
 func initStorage(storageType string, config *config.DatabaseConfig) (calendar.Storage, CloseConnFn, error) {
@@ -1 +1,156 @@
 package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	amqpqueue "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue/amqp"
+	memoryqueue "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue/memory"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/scheduler"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/sender"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	sqlstorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/sql"
+	"golang.org/x/exp/slog"
+)
+
+var configFile string
+
+func init() {
+	flag.StringVar(&configFile, "config", "../../configs/config.toml", "Path to configuration file")
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.Arg(0) == "version" {
+		printVersion()
+		return
+	}
+
+	config, err := config.NewConfig(configFile)
+	if err != nil {
+		fmt.Printf("failed to read configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	log := logger.New(config.Logger.Level)
+
+	storage, closeDBConn, err := initStorage(config.Storage.Type, &config.Database)
+	if err != nil {
+		log.Error("Init storage", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if closeDBConn != nil {
+			if err := closeDBConn(); err != nil {
+				log.Error("Close connection to database", "error", err)
+			}
+		}
+	}()
+
+	publisher, closeQueue, err := initPublisher(&config.Queue)
+	if err != nil {
+		log.Error("Init queue", "error", err)
+		os.Exit(1) //nolint:gocritic
+	}
+	defer func() {
+		if closeQueue != nil {
+			if err := closeQueue(); err != nil {
+				log.Error("Close connection to queue", "error", err)
+			}
+		}
+	}()
+
+	scheduler := scheduler.New(log, storage, publisher, &config.Scheduler)
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer cancel()
+
+	log.Info("Scheduler is running...",
+		slog.Duration("interval", config.Scheduler.Interval),
+		slog.Duration("events_max_age", config.Scheduler.EventsMaxAge),
+		slog.Duration("trash_retention", config.Scheduler.TrashRetention),
+		slog.String("queue", config.Queue.Type))
+
+	wg := &sync.WaitGroup{}
+
+	// The in-memory queue is not shared between processes, so notifications
+	// are delivered by a sender running inside the scheduler.
+	if memQueue, ok := publisher.(*memoryqueue.Queue); ok {
+		sender := sender.New(log, memQueue, sender.NewSinks(log, &config.Sender), &config.Sender)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sender.Run(ctx); err != nil {
+				log.Error("Run sender", "error", err)
+				cancel()
+			}
+		}()
+	}
+
+	scheduler.Run(ctx)
+	wg.Wait()
+}
+
+func initPublisher(config *config.QueueConfig) (queue.Publisher, CloseConnFn, error) {
+	codec, err := queue.NewCodec(config.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch config.Type {
+	case queue.InMemory:
+		return memoryqueue.New(config.Size), nil, nil
+
+	case queue.AMQP:
+		conn, err := amqpqueue.Dial(&config.AMQP)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		publisher, err := amqpqueue.New(conn.Channel(), codec, &config.AMQP)
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		return publisher, conn.Close, nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid queue type")
+	}
+}
+
+type CloseConnFn func() error
+
+// errInMemoryStorage is returned for the in-memory storage: the events of the
+// calendar are not shared with other processes, so the calendar runs the
+// scheduler itself with that storage.
+var errInMemoryStorage = errors.New("in-memory storage is not supported, the calendar runs the scheduler with it")
+
+func initStorage(storageType string, config *config.DatabaseConfig) (scheduler.Storage, CloseConnFn, error) {
+	switch storageType {
+	case storage.InMemory:
+		return nil, nil, errInMemoryStorage
+
+	case storage.SQL:
+		dbConn, err := sqlstorage.NewConnection(config)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlstorage.New(dbConn), func() error { return dbConn.Close() }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid storage type")
+	}
+}
@@ -1 +1,94 @@
 package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	amqpqueue "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue/amqp"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/sender"
+	"golang.org/x/exp/slog"
+)
+
+var configFile string
+
+func init() {
+	flag.StringVar(&configFile, "config", "../../configs/config.toml", "Path to configuration file")
+}
+
+func main() {
+	flag.Parse()
+
+	if flag.Arg(0) == "version" {
+		printVersion()
+		return
+	}
+
+	config, err := config.NewConfig(configFile)
+	if err != nil {
+		fmt.Printf("failed to read configuration file: %v\n", err)
+		os.Exit(1)
+	}
+
+	log := logger.New(config.Logger.Level)
+
+	consumer, closeQueue, err := initConsumer(&config.Queue)
+	if err != nil {
+		log.Error("Init queue", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := closeQueue(); err != nil {
+			log.Error("Close connection to queue", "error", err)
+		}
+	}()
+
+	sender := sender.New(log, consumer, sender.NewSinks(log, &config.Sender), &config.Sender)
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer cancel()
+
+	log.Info("Sender is running...", slog.Any("sinks", config.Sender.Sinks))
+
+	if err := sender.Run(ctx); err != nil {
+		log.Error("Run sender", "error", err)
+	}
+}
+
+type CloseConnFn func() error
+
+func initConsumer(config *config.QueueConfig) (queue.Consumer, CloseConnFn, error) {
+	codec, err := queue.NewCodec(config.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch config.Type {
+	case queue.InMemory:
+		return nil, nil, errors.New("in-memory queue is served by the scheduler process")
+
+	case queue.AMQP:
+		conn, err := amqpqueue.Dial(&config.AMQP)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		consumer, err := amqpqueue.New(conn.Channel(), codec, &config.AMQP)
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+		return consumer, conn.Close, nil
+
+	default:
+		return nil, nil, fmt.Errorf("invalid queue type")
+	}
+}
@@ -0,0 +1,31 @@
+// Package auth verifies access tokens and carries the authenticated user
+// through the request context.
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+type userIDKey struct{}
+
+// WithUserID returns a copy of ctx that carries the authenticated user.
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserID returns the authenticated user stored in ctx by WithUserID.
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(int64)
+	return userID, ok
+}
+
+// BearerToken extracts the token from an Authorization header value.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
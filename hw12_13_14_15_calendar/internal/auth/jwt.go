@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// JWT authenticates HS256 and RS256 tokens. The user ID is read from the
+// subject claim.
+type JWT struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	parser    *jwt.Parser
+}
+
+func NewJWT(cfg *config.AuthConfig) (*JWT, error) {
+	var (
+		authenticator JWT
+		methods       []string
+	)
+
+	if cfg.Secret != "" {
+		authenticator.secret = []byte(cfg.Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read public key: %w", err)
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("parse public key: %w", err)
+		}
+		authenticator.publicKey = publicKey
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("no verification keys configured")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	authenticator.parser = jwt.NewParser(options...)
+
+	return &authenticator, nil
+}
+
+func (a *JWT) Authenticate(_ context.Context, token string) (int64, error) {
+	var claims jwt.RegisteredClaims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.key); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, fmt.Errorf("%w: invalid subject %q", ErrInvalidToken, claims.Subject)
+	}
+	return userID, nil
+}
+
+func (a *JWT) key(token *jwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return a.secret, nil
+	case *jwt.SigningMethodRSA:
+		return a.publicKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+}
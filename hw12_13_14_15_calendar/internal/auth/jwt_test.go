@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "secret"
+
+func TestJWTAuthenticate(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator, err := NewJWT(&config.AuthConfig{
+		Secret:        secret,
+		PublicKeyFile: writePublicKey(t, &privateKey.PublicKey),
+		Issuer:        "calendar",
+	})
+	require.NoError(t, err)
+
+	validClaims := func() jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Subject:   "42",
+			Issuer:    "calendar",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}
+	}
+
+	cases := []struct {
+		name    string
+		method  jwt.SigningMethod
+		key     any
+		claims  func() jwt.RegisteredClaims
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:   "HS256",
+			method: jwt.SigningMethodHS256,
+			key:    []byte(secret),
+			claims: validClaims,
+			want:   42,
+		},
+		{
+			name:   "RS256",
+			method: jwt.SigningMethodRS256,
+			key:    privateKey,
+			claims: validClaims,
+			want:   42,
+		},
+		{
+			name:    "wrong secret",
+			method:  jwt.SigningMethodHS256,
+			key:     []byte("other"),
+			claims:  validClaims,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported method",
+			method:  jwt.SigningMethodHS512,
+			key:     []byte(secret),
+			claims:  validClaims,
+			wantErr: true,
+		},
+		{
+			name:   "expired",
+			method: jwt.SigningMethodHS256,
+			key:    []byte(secret),
+			claims: func() jwt.RegisteredClaims {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return claims
+			},
+			wantErr: true,
+		},
+		{
+			name:   "no expiration",
+			method: jwt.SigningMethodHS256,
+			key:    []byte(secret),
+			claims: func() jwt.RegisteredClaims {
+				claims := validClaims()
+				claims.ExpiresAt = nil
+				return claims
+			},
+			wantErr: true,
+		},
+		{
+			name:   "wrong issuer",
+			method: jwt.SigningMethodHS256,
+			key:    []byte(secret),
+			claims: func() jwt.RegisteredClaims {
+				claims := validClaims()
+				claims.Issuer = "other"
+				return claims
+			},
+			wantErr: true,
+		},
+		{
+			name:   "invalid subject",
+			method: jwt.SigningMethodHS256,
+			key:    []byte(secret),
+			claims: func() jwt.RegisteredClaims {
+				claims := validClaims()
+				claims.Subject = "user"
+				return claims
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(tc.method, tc.claims()).SignedString(tc.key)
+			require.NoError(t, err)
+
+			userID, err := authenticator.Authenticate(context.Background(), token)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, userID)
+		})
+	}
+}
+
+func TestJWTRejectsRS256WithoutPublicKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator, err := NewJWT(&config.AuthConfig{Secret: secret})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Subject:   "42",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(privateKey)
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(context.Background(), token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestUserID(t *testing.T) {
+	_, ok := UserID(context.Background())
+	assert.False(t, ok)
+
+	userID, ok := UserID(WithUserID(context.Background(), 7))
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), userID)
+}
+
+func writePublicKey(t *testing.T, key *rsa.PublicKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "public.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestBearerToken(t *testing.T) {
+	cases := []struct {
+		header string
+		token  string
+		ok     bool
+	}{
+		{header: "Bearer abc", token: "abc", ok: true},
+		{header: "bearer  abc ", token: "abc", ok: true},
+		{header: "Basic abc"},
+		{header: "Bearer "},
+		{header: "abc"},
+		{header: ""},
+	}
+
+	for _, tc := range cases {
+		token, ok := BearerToken(tc.header)
+		assert.Equal(t, tc.ok, ok, tc.header)
+		assert.Equal(t, tc.token, token, tc.header)
+	}
+}
@@ -0,0 +1,23 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// InviteAttendees invites the users to the event. Only users that may change
+// the event can invite attendees.
+func (c *Calendar) InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) error {
+	return c.db.InviteAttendees(ctx, userID, eventID, attendeeIDs)
+}
+
+// RespondToInvitation saves the response of the user to an invitation. Events
+// accepted by the user are returned together with the events of the user.
+func (c *Calendar) RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) error { //nolint:lll
+	return c.db.RespondToInvitation(ctx, userID, eventID, status)
+}
+
+func (c *Calendar) GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) ([]models.Invitation, error) { //nolint:lll
+	return c.db.GetInvitations(ctx, userID, status)
+}
@@ -2,59 +2,169 @@ package calendar
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/feed"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
 )
 
 type Storage interface {
 	CreateEvent(context.Context, *models.Event) error
-	UpdateEvent(context.Context, *models.Event) error
-	DeleteEvent(context.Context, string) error
-	GetEventByDay(context.Context, int64, time.Time) ([]models.Event, error)
-	GetEventByWeek(context.Context, int64, time.Time) ([]models.Event, error)
-	GetEventByMonth(context.Context, int64, time.Time) ([]models.Event, error)
+	CreateEvents(context.Context, []*models.Event) ([]string, error)
+	UpdateEvent(ctx context.Context, userID int64, event *models.Event) error
+	DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) error
+	GetDeletedEvents(ctx context.Context, userID int64) ([]models.Event, error)
+	RestoreEvent(ctx context.Context, userID int64, eventID string) error
+	GetEventHistory(ctx context.Context, userID int64, eventID string) ([]models.HistoryEntry, error)
+	GetEvent(ctx context.Context, userID int64, eventID string) (*models.Event, error)
+	GetEventByDay(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)
+	GetEventByWeek(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)
+	GetEventByMonth(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)
+	GetEventsInRange(ctx context.Context, userID int64, from, to time.Time) ([]models.Event, error)
+	GetEventsByUser(ctx context.Context, userID int64) ([]models.Event, error)
+
+	CreateCalendar(ctx context.Context, calendar *models.Calendar) error
+	GetCalendar(ctx context.Context, userID int64, calendarID string) (*models.Calendar, error)
+	GetCalendars(ctx context.Context, userID int64) ([]models.Calendar, error)
+	UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) error
+	DeleteCalendar(ctx context.Context, userID int64, calendarID string) error
+	GetACL(ctx context.Context, userID int64, calendarID string) ([]models.ACLEntry, error)
+	SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) error
+	DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) error
+
+	InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) error
+	RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) error
+	GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) ([]models.Invitation, error)
 }
 
 type Calendar struct {
-	db Storage
+	db      Storage
+	changes *feed.Feed
 }
 
-func New(storage Storage) *Calendar {
+// New creates the calendar. The changes are the feed the storage publishes
+// the changes of events to.
+func New(storage Storage, changes *feed.Feed) *Calendar {
 	return &Calendar{
-		db: storage,
+		db:      storage,
+		changes: changes,
 	}
 }
 
 func (c *Calendar) CreateEvent(ctx context.Context, event *models.Event) (string, error) {
 	event.ID = generateEventID()
+	if event.Timezone == "" {
+		event.Timezone = defaultTimezone
+	}
 	if err := c.db.CreateEvent(ctx, event); err != nil {
 		return "", err
 	}
 	return event.ID, nil
 }
 
+// WatchEvents subscribes the user to the changes of the events the user can
+// read. The subscription must be closed when it is not needed anymore.
+func (c *Calendar) WatchEvents(userID int64) *feed.Subscription {
+	return c.changes.Subscribe(userID)
+}
+
 func generateEventID() string {
 	return uuid.New().String()
 }
 
-func (c *Calendar) UpdateEvent(ctx context.Context, event *models.Event) error {
-	return c.db.UpdateEvent(ctx, event)
+// UpdateEvent updates the event on behalf of the user. A personal event may be
+// changed by its creator, an event of a calendar by the writers and the owner
+// of the calendar. A non-zero version of the event must match the stored one;
+// on success the event holds the new version.
+func (c *Calendar) UpdateEvent(ctx context.Context, userID int64, event *models.Event) error {
+	return c.db.UpdateEvent(ctx, userID, event)
+}
+
+// DeleteEvent moves the event to the trash on behalf of the user. A personal
+// event may be deleted by its creator, an event of a calendar by the writers
+// and the owner of the calendar. A non-zero version must match the version of
+// the event.
+func (c *Calendar) DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) error {
+	return c.db.DeleteEvent(ctx, userID, eventID, version)
+}
+
+// GetDeletedEvents returns the trashed events the user may restore, the most
+// recently deleted first.
+func (c *Calendar) GetDeletedEvents(ctx context.Context, userID int64) ([]models.Event, error) {
+	return c.db.GetDeletedEvents(ctx, userID)
+}
+
+// RestoreEvent moves the event back from the trash on behalf of the user. The
+// restored event must not overlap other events of its owner.
+func (c *Calendar) RestoreEvent(ctx context.Context, userID int64, eventID string) error {
+	return c.db.RestoreEvent(ctx, userID, eventID)
+}
+
+// GetEventHistory returns the changes of the event from the oldest one.
+func (c *Calendar) GetEventHistory(ctx context.Context, userID int64, eventID string) ([]models.HistoryEntry, error) {
+	return c.db.GetEventHistory(ctx, userID, eventID)
 }
 
-func (c *Calendar) DeleteEvent(ctx context.Context, eventID string) error {
-	return c.db.DeleteEvent(ctx, eventID)
+// GetEvent returns the event if the user can read it.
+func (c *Calendar) GetEvent(ctx context.Context, userID int64, eventID string) (*models.Event, error) {
+	return c.db.GetEvent(ctx, userID, eventID)
 }
 
-func (c *Calendar) GetEventByDay(ctx context.Context, userID int64, day time.Time) ([]models.Event, error) {
-	return c.db.GetEventByDay(ctx, userID, day)
+func (c *Calendar) GetEventByDay(ctx context.Context, userID int64, day time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	return c.db.GetEventByDay(ctx, userID, day, filter)
 }
 
-func (c *Calendar) GetEventByWeek(ctx context.Context, userID int64, week time.Time) ([]models.Event, error) {
-	return c.db.GetEventByWeek(ctx, userID, week)
+func (c *Calendar) GetEventByWeek(ctx context.Context, userID int64, week time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	return c.db.GetEventByWeek(ctx, userID, week, filter)
 }
 
-func (c *Calendar) GetEventByMonth(ctx context.Context, userID int64, month time.Time) ([]models.Event, error) {
-	return c.db.GetEventByMonth(ctx, userID, month)
+func (c *Calendar) GetEventByMonth(ctx context.Context, userID int64, month time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	return c.db.GetEventByMonth(ctx, userID, month, filter)
+}
+
+func (c *Calendar) GetEventsInRange(ctx context.Context, userID int64, from, to time.Time) ([]models.Event, error) {
+	return c.db.GetEventsInRange(ctx, userID, from, to)
+}
+
+func (c *Calendar) GetEventsByUser(ctx context.Context, userID int64) ([]models.Event, error) {
+	return c.db.GetEventsByUser(ctx, userID)
+}
+
+// ImportEvents creates the events for the user in one step, so none of them
+// is created if one fails, and returns the IDs of the created events. The ID of
+// an event is derived from its iCalendar UID: the events imported before are
+// skipped.
+func (c *Calendar) ImportEvents(ctx context.Context, userID int64, events []models.Event, allowOverlap bool) ([]string, error) { //nolint:lll
+	batch := make([]*models.Event, len(events))
+	for i := range events {
+		events[i].ID = importedEventID(userID, events[i].ID)
+		events[i].UserID = userID
+		events[i].AllowOverlap = allowOverlap
+		if events[i].Timezone == "" {
+			events[i].Timezone = defaultTimezone
+		}
+		batch[i] = &events[i]
+	}
+
+	ids, err := c.db.CreateEvents(ctx, batch)
+	if err != nil {
+		return nil, fmt.Errorf("import %w", err)
+	}
+	return ids, nil
+}
+
+// importNamespace is the namespace of the IDs derived from iCalendar UIDs.
+var importNamespace = uuid.MustParse("3f0c2b1e-8a4d-5c6e-9f70-1b2c3d4e5f60")
+
+// importedEventID derives the ID of an imported event from the user and the
+// UID, so importing the same event again yields the same ID. An event without
+// a UID gets a new ID.
+func importedEventID(userID int64, uid string) string {
+	if uid == "" {
+		return generateEventID()
+	}
+	return uuid.NewSHA1(importNamespace, []byte(strconv.FormatInt(userID, 10)+"/"+uid)).String()
 }
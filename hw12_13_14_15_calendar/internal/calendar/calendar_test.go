@@ -0,0 +1,61 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportEvents(t *testing.T) {
+	day := time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)
+	newEvents := func(titles ...string) []models.Event {
+		events := make([]models.Event, len(titles))
+		for i, title := range titles {
+			start := day.Add(time.Duration(i) * time.Hour)
+			events[i] = models.Event{ID: title, Title: title, StartDate: start, EndDate: start.Add(time.Hour)}
+		}
+		return events
+	}
+
+	t.Run("same calendar imported twice", func(t *testing.T) {
+		app := New(memorystorage.New(), nil)
+
+		ids, err := app.ImportEvents(context.Background(), 1, newEvents("standup", "review"), false)
+		require.NoError(t, err)
+		require.Len(t, ids, 2)
+
+		again, err := app.ImportEvents(context.Background(), 1, newEvents("standup", "review", "retro"), false)
+		require.NoError(t, err)
+		require.Len(t, again, 1)
+		require.NotContains(t, ids, again[0])
+
+		other, err := app.ImportEvents(context.Background(), 2, newEvents("standup"), false)
+		require.NoError(t, err)
+		require.Len(t, other, 1)
+		require.NotContains(t, ids, other[0])
+
+		events, err := app.GetEventsByUser(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+	})
+
+	t.Run("nothing imported on conflict", func(t *testing.T) {
+		app := New(memorystorage.New(), nil)
+
+		events := newEvents("standup", "review")
+		events[1].StartDate = events[0].StartDate
+
+		ids, err := app.ImportEvents(context.Background(), 1, events, false)
+		require.ErrorAs(t, err, new(*storage.ConflictError))
+		require.Empty(t, ids)
+
+		stored, err := app.GetEventsByUser(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, stored)
+	})
+}
@@ -0,0 +1,52 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+const defaultTimezone = "UTC"
+
+// CreateCalendar creates a calendar owned by calendar.OwnerID and returns its
+// ID. The timezone defaults to UTC.
+func (c *Calendar) CreateCalendar(ctx context.Context, calendar *models.Calendar) (string, error) {
+	calendar.ID = uuid.New().String()
+	if calendar.Timezone == "" {
+		calendar.Timezone = defaultTimezone
+	}
+
+	if err := c.db.CreateCalendar(ctx, calendar); err != nil {
+		return "", err
+	}
+	return calendar.ID, nil
+}
+
+func (c *Calendar) GetCalendar(ctx context.Context, userID int64, calendarID string) (*models.Calendar, error) {
+	return c.db.GetCalendar(ctx, userID, calendarID)
+}
+
+func (c *Calendar) GetCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	return c.db.GetCalendars(ctx, userID)
+}
+
+func (c *Calendar) UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) error {
+	return c.db.UpdateCalendar(ctx, userID, calendar)
+}
+
+func (c *Calendar) DeleteCalendar(ctx context.Context, userID int64, calendarID string) error {
+	return c.db.DeleteCalendar(ctx, userID, calendarID)
+}
+
+func (c *Calendar) GetACL(ctx context.Context, userID int64, calendarID string) ([]models.ACLEntry, error) {
+	return c.db.GetACL(ctx, userID, calendarID)
+}
+
+func (c *Calendar) SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) error {
+	return c.db.SetACLEntry(ctx, userID, entry)
+}
+
+func (c *Calendar) DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) error {
+	return c.db.DeleteACLEntry(ctx, userID, calendarID, memberID)
+}
@@ -0,0 +1,153 @@
+package calendar
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// FreeBusy returns the busy intervals of every user in the [from, to) interval
+// and the slots of slotDuration in which all of them are free. Users are busy
+// during their own events and the invitations they accepted, but not during
+// the other events of the calendars shared with them. All-day events
+// do not make users busy. Free slots start
+// at the beginning of every free interval; the remainder that is shorter than
+// slotDuration is dropped. A non-positive slotDuration returns the free
+// intervals as they are. Repeated users are queried once. The servers check
+// the query with models.CheckFreeBusyLimits.
+func (c *Calendar) FreeBusy(ctx context.Context, userIDs []int64, from, to time.Time, slotDuration time.Duration) (*models.FreeBusy, error) { //nolint:lll
+	userIDs = uniqueIDs(userIDs)
+	freeBusy := &models.FreeBusy{Users: make([]models.UserBusy, 0, len(userIDs))}
+
+	var allBusy []models.Interval
+	for _, userID := range userIDs {
+		events, err := c.busyEvents(ctx, userID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		intervals := make([]models.Interval, 0, len(events))
+		for i := range events {
+			if events[i].IsAllDay() {
+				continue
+			}
+			intervals = append(intervals, clip(events[i].StartDate, events[i].EndDate, from, to))
+		}
+
+		busy := mergeIntervals(intervals)
+		freeBusy.Users = append(freeBusy.Users, models.UserBusy{UserID: userID, Busy: busy})
+		allBusy = append(allBusy, busy...)
+	}
+
+	freeBusy.FreeSlots = splitIntervals(freeIntervals(mergeIntervals(allBusy), from, to), slotDuration)
+	return freeBusy, nil
+}
+
+// busyEvents returns the events of the user and the events the user accepted
+// in the [from, to) interval.
+func (c *Calendar) busyEvents(ctx context.Context, userID int64, from, to time.Time) ([]models.Event, error) {
+	events, err := c.db.GetEventsInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := c.db.GetInvitations(ctx, userID, models.StatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+	accepted := make(map[string]struct{}, len(invitations))
+	for i := range invitations {
+		accepted[invitations[i].ID] = struct{}{}
+	}
+
+	busy := events[:0]
+	for i := range events {
+		if _, ok := accepted[events[i].ID]; ok || events[i].UserID == userID {
+			busy = append(busy, events[i])
+		}
+	}
+	return busy, nil
+}
+
+// uniqueIDs drops the repeated IDs, keeping the order of the first ones.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+func clip(start, end, from, to time.Time) models.Interval {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return models.Interval{Start: start, End: end}
+}
+
+// mergeIntervals sorts the intervals and joins the ones that overlap or touch.
+func mergeIntervals(intervals []models.Interval) []models.Interval {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	merged := []models.Interval{intervals[0]}
+	for _, interval := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if interval.Start.After(last.End) {
+			merged = append(merged, interval)
+			continue
+		}
+		if interval.End.After(last.End) {
+			last.End = interval.End
+		}
+	}
+	return merged
+}
+
+// freeIntervals returns the gaps between the merged busy intervals.
+func freeIntervals(busy []models.Interval, from, to time.Time) []models.Interval {
+	var free []models.Interval
+
+	start := from
+	for _, interval := range busy {
+		if interval.Start.After(start) {
+			free = append(free, models.Interval{Start: start, End: interval.Start})
+		}
+		if interval.End.After(start) {
+			start = interval.End
+		}
+	}
+	if to.After(start) {
+		free = append(free, models.Interval{Start: start, End: to})
+	}
+	return free
+}
+
+func splitIntervals(intervals []models.Interval, slotDuration time.Duration) []models.Interval {
+	if slotDuration <= 0 {
+		return intervals
+	}
+
+	var slots []models.Interval
+	for _, interval := range intervals {
+		for start := interval.Start; !start.Add(slotDuration).After(interval.End); start = start.Add(slotDuration) {
+			slots = append(slots, models.Interval{Start: start, End: start.Add(slotDuration)})
+		}
+	}
+	return slots
+}
@@ -0,0 +1,107 @@
+package calendar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	memorystorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeBusy(t *testing.T) {
+	day := time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	allDay := true
+	app := New(memorystorage.New(), nil)
+	events := []*models.Event{
+		{Title: "night shift", UserID: 1, StartDate: at(-2, 0), EndDate: at(9, 0)},
+		{Title: "standup", UserID: 1, StartDate: at(10, 0), EndDate: at(10, 30)},
+		{Title: "review", UserID: 1, StartDate: at(10, 30), EndDate: at(11, 0)},
+		{Title: "lunch", UserID: 2, StartDate: at(10, 45), EndDate: at(12, 0)},
+		{Title: "other user", UserID: 3, StartDate: at(13, 0), EndDate: at(14, 0)},
+		{Title: "holiday", UserID: 2, StartDate: day, EndDate: at(24, 0), AllDay: &allDay},
+	}
+	for _, event := range events {
+		_, err := app.CreateEvent(context.Background(), event)
+		require.NoError(t, err)
+	}
+
+	got, err := app.FreeBusy(context.Background(), []int64{1, 2}, at(8, 0), at(13, 0), time.Hour)
+	require.NoError(t, err)
+
+	want := &models.FreeBusy{
+		Users: []models.UserBusy{
+			{UserID: 1, Busy: []models.Interval{
+				{Start: at(8, 0), End: at(9, 0)},
+				{Start: at(10, 0), End: at(11, 0)},
+			}},
+			{UserID: 2, Busy: []models.Interval{
+				{Start: at(10, 45), End: at(12, 0)},
+			}},
+		},
+		FreeSlots: []models.Interval{
+			{Start: at(9, 0), End: at(10, 0)},
+			{Start: at(12, 0), End: at(13, 0)},
+		},
+	}
+	require.Equal(t, want, got)
+
+	got, err = app.FreeBusy(context.Background(), []int64{1, 2, 1}, at(8, 0), at(13, 0), time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = app.FreeBusy(context.Background(), []int64{1, 2}, at(8, 0), at(13, 0), 0)
+	require.NoError(t, err)
+	require.Equal(t, []models.Interval{
+		{Start: at(9, 0), End: at(10, 0)},
+		{Start: at(12, 0), End: at(13, 0)},
+	}, got.FreeSlots)
+}
+
+func TestFreeBusySharedCalendars(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time {
+		return day.Add(time.Duration(hour) * time.Hour)
+	}
+
+	app := New(memorystorage.New(), nil)
+	calendar := &models.Calendar{OwnerID: 1, Name: "team"}
+	_, err := app.CreateCalendar(ctx, calendar)
+	require.NoError(t, err)
+	require.NoError(t, app.SetACLEntry(ctx, 1,
+		models.ACLEntry{CalendarID: calendar.ID, UserID: 2, Role: models.RoleReader}))
+
+	events := []*models.Event{
+		{Title: "shared", UserID: 1, CalendarID: &calendar.ID, StartDate: at(9), EndDate: at(10)},
+		{Title: "accepted", UserID: 1, CalendarID: &calendar.ID, StartDate: at(11), EndDate: at(12)},
+		{Title: "declined", UserID: 1, StartDate: at(13), EndDate: at(14)},
+	}
+	for _, event := range events {
+		_, err := app.CreateEvent(ctx, event)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.InviteAttendees(ctx, 1, events[1].ID, []int64{2}))
+	require.NoError(t, app.RespondToInvitation(ctx, 2, events[1].ID, models.StatusAccepted))
+	require.NoError(t, app.InviteAttendees(ctx, 1, events[2].ID, []int64{2}))
+	require.NoError(t, app.RespondToInvitation(ctx, 2, events[2].ID, models.StatusDeclined))
+
+	got, err := app.FreeBusy(ctx, []int64{2}, at(8), at(15), 0)
+	require.NoError(t, err)
+
+	want := &models.FreeBusy{
+		Users: []models.UserBusy{
+			{UserID: 2, Busy: []models.Interval{{Start: at(11), End: at(12)}}},
+		},
+		FreeSlots: []models.Interval{
+			{Start: at(8), End: at(11)},
+			{Start: at(12), End: at(15)},
+		},
+	}
+	require.Equal(t, want, got)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MinSecretLength is the shortest HS256 secret: RFC 7518 requires a key at
+// least as long as the hash output.
+const MinSecretLength = 32
+
+// placeholderSecrets are the example secrets that must not reach production.
+var placeholderSecrets = []string{"changeme", "secret"}
+
+// AuthConfig holds the keys used to verify access tokens. HS256 tokens are
+// checked with Secret, RS256 tokens with the PEM public key in PublicKeyFile.
+type AuthConfig struct {
+	Secret        string        `toml:"secret"`
+	PublicKeyFile string        `toml:"public_key_file"`
+	Issuer        string        `toml:"issuer"`
+	Audience      string        `toml:"audience"`
+	Leeway        time.Duration `toml:"leeway"`
+}
+
+func (ac AuthConfig) validate() error {
+	if emptyString(ac.Secret) && emptyString(ac.PublicKeyFile) {
+		return errors.New("invalid secret or public_key_file field")
+	}
+	if !emptyString(ac.Secret) {
+		if err := validateSecret(ac.Secret); err != nil {
+			return err
+		}
+	}
+	if ac.Leeway < 0 {
+		return errors.New("invalid leeway field")
+	}
+	return nil
+}
+
+func validateSecret(secret string) error {
+	for _, placeholder := range placeholderSecrets {
+		if strings.EqualFold(secret, placeholder) {
+			return errors.New("invalid secret field: placeholder value")
+		}
+	}
+	if len(secret) < MinSecretLength {
+		return fmt.Errorf("invalid secret field: must be at least %d bytes", MinSecretLength)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Auth(t *testing.T) {
+	config := AuthConfig{
+		Secret: "test-secret-for-calendar-config-0123",
+		Leeway: 30 * time.Second,
+	}
+
+	tests := []struct {
+		description string
+		config      AuthConfig
+		changeFn    func(AuthConfig) AuthConfig
+		wantErr     bool
+	}{
+		{
+			description: "valid config",
+			config:      config,
+			changeFn:    func(ac AuthConfig) AuthConfig { return ac },
+			wantErr:     false,
+		},
+		{
+			description: "public key only",
+			config:      config,
+			changeFn: func(ac AuthConfig) AuthConfig {
+				ac.Secret = ""
+				ac.PublicKeyFile = "./keys/public.pem"
+				return ac
+			},
+			wantErr: false,
+		},
+		{
+			description: "no keys",
+			config:      config,
+			changeFn: func(ac AuthConfig) AuthConfig {
+				ac.Secret = ""
+				return ac
+			},
+			wantErr: true,
+		},
+		{
+			description: "placeholder secret",
+			config:      config,
+			changeFn: func(ac AuthConfig) AuthConfig {
+				ac.Secret = "ChangeMe"
+				return ac
+			},
+			wantErr: true,
+		},
+		{
+			description: "short secret",
+			config:      config,
+			changeFn: func(ac AuthConfig) AuthConfig {
+				ac.Secret = "0123456789"
+				return ac
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid leeway",
+			config:      config,
+			changeFn: func(ac AuthConfig) AuthConfig {
+				ac.Leeway = -time.Second
+				return ac
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			config := tt.changeFn(tt.config)
+			err := config.validate()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -12,10 +12,15 @@ type Config struct {
 	ServerHTTP ServerHTTPConfig `toml:"server_http"`
 	ServerGRPC ServerGRPCConfig `toml:"server_grpc"`
 	Database   DatabaseConfig   `toml:"database"`
+	Scheduler  SchedulerConfig  `toml:"scheduler"`
+	Sender     SenderConfig     `toml:"sender"`
+	Queue      QueueConfig      `toml:"queue"`
+	Auth       AuthConfig       `toml:"auth"`
+	Tracing    TracingConfig    `toml:"tracing"`
 }
 
 func NewConfig(path string) (Config, error) {
-	var config Config
+	config := defaultConfig()
 
 	_, err := toml.DecodeFile(path, &config)
 	if err != nil {
@@ -29,6 +34,27 @@ func NewConfig(path string) (Config, error) {
 	return config, nil
 }
 
+// defaultConfig holds the values of the fields missing in the file.
+func defaultConfig() Config {
+	return Config{
+		ServerHTTP: ServerHTTPConfig{
+			ShutdownDelay: DefaultShutdownDelay,
+			Watch: WatchConfig{
+				Duration:  DefaultWatchDuration,
+				Heartbeat: DefaultWatchHeartbeat,
+			},
+		},
+		Scheduler: SchedulerConfig{
+			TrashRetention: DefaultTrashRetention,
+		},
+		Sender: SenderConfig{
+			MaxRedeliveries:        DefaultMaxRedeliveries,
+			RedeliveryTTL:          DefaultRedeliveryTTL,
+			MaxTrackedRedeliveries: DefaultMaxTrackedRedeliveries,
+		},
+	}
+}
+
 func (c *Config) validate() error {
 	if err := c.Logger.validate(); err != nil {
 		return fmt.Errorf("invalid logger definition: %w", err)
@@ -45,6 +71,21 @@ func (c *Config) validate() error {
 	if err := c.Database.validate(); err != nil {
 		return fmt.Errorf("invalid database definition: %w", err)
 	}
+	if err := c.Scheduler.validate(); err != nil {
+		return fmt.Errorf("invalid scheduler definition: %w", err)
+	}
+	if err := c.Sender.validate(); err != nil {
+		return fmt.Errorf("invalid sender definition: %w", err)
+	}
+	if err := c.Queue.validate(); err != nil {
+		return fmt.Errorf("invalid queue definition: %w", err)
+	}
+	if err := c.Auth.validate(); err != nil {
+		return fmt.Errorf("invalid auth definition: %w", err)
+	}
+	if err := c.Tracing.validate(); err != nil {
+		return fmt.Errorf("invalid tracing definition: %w", err)
+	}
 
 	return nil
 }
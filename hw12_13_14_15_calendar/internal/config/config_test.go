@@ -20,10 +20,15 @@ func TestNewConfig(t *testing.T) {
 			path:        "./testdata/valid_config.toml",
 			want: Config{
 				ServerHTTP: ServerHTTPConfig{
-					Host:        "127.0.0.1",
-					Port:        8080,
-					Timeout:     10 * time.Second,
-					IdleTimeout: 30 * time.Second,
+					Host:          "127.0.0.1",
+					Port:          8080,
+					Timeout:       10 * time.Second,
+					IdleTimeout:   30 * time.Second,
+					ShutdownDelay: DefaultShutdownDelay,
+					Watch: WatchConfig{
+						Duration:  DefaultWatchDuration,
+						Heartbeat: DefaultWatchHeartbeat,
+					},
 				},
 				ServerGRPC: ServerGRPCConfig{
 					Host:              "127.0.0.1",
@@ -46,6 +51,54 @@ func TestNewConfig(t *testing.T) {
 				Logger: LoggerConfig{
 					Level: slog.LevelInfo,
 				},
+				Scheduler: SchedulerConfig{
+					Interval:             time.Minute,
+					EventsMaxAge:         8760 * time.Hour,
+					NotificationLookback: 24 * time.Hour,
+					OutboxBatchSize:      100,
+					TrashRetention:       DefaultTrashRetention,
+				},
+				Sender: SenderConfig{
+					Sinks:                  []string{"log", "file"},
+					MaxRetries:             5,
+					RetryDelay:             time.Second,
+					MaxRetryDelay:          30 * time.Second,
+					MaxRedeliveries:        DefaultMaxRedeliveries,
+					RedeliveryTTL:          DefaultRedeliveryTTL,
+					MaxTrackedRedeliveries: DefaultMaxTrackedRedeliveries,
+					Webhook: WebhookConfig{
+						Timeout: 5 * time.Second,
+					},
+					File: FileConfig{
+						Path: "./logs/notifications.log",
+					},
+				},
+				Queue: QueueConfig{
+					Type:     "amqp",
+					Encoding: "json",
+					Size:     1024,
+					AMQP: AMQPConfig{
+						Host:       "127.0.0.1",
+						Port:       5672,
+						Username:   "guest",
+						Password:   "guest",
+						Exchange:   "calendar",
+						Queue:      "notifications",
+						RoutingKey: "notification",
+						Prefetch:   10,
+					},
+				},
+				Auth: AuthConfig{
+					Secret: "test-secret-for-calendar-config-0123",
+					Issuer: "calendar",
+					Leeway: 30 * time.Second,
+				},
+				Tracing: TracingConfig{
+					Exporter:    "file",
+					Path:        "./logs/traces.json",
+					ServiceName: "calendar",
+					SampleRatio: 1,
+				},
 			},
 			wantErr: false,
 		},
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+)
+
+type QueueConfig struct {
+	Type     string     `toml:"type"`
+	Encoding string     `toml:"encoding"`
+	Size     int        `toml:"size"`
+	AMQP     AMQPConfig `toml:"amqp"`
+}
+
+type AMQPConfig struct {
+	Host       string `toml:"host"`
+	Port       int    `toml:"port"`
+	Username   string `toml:"username"`
+	Password   string `toml:"password"`
+	Exchange   string `toml:"exchange"`
+	Queue      string `toml:"queue"`
+	RoutingKey string `toml:"routing_key"`
+	Prefetch   int    `toml:"prefetch"`
+}
+
+func (qc QueueConfig) validate() error {
+	switch qc.Encoding {
+	case "json", "protobuf":
+	default:
+		return errors.New("invalid encoding field")
+	}
+
+	switch qc.Type {
+	case "in-memory":
+		if qc.Size <= 0 {
+			return errors.New("invalid size field")
+		}
+		return nil
+	case "amqp":
+		return qc.AMQP.validate()
+	}
+	return errors.New("invalid type field")
+}
+
+func (ac AMQPConfig) validate() error {
+	if emptyString(ac.Host) {
+		return errors.New("invalid amqp.host field")
+	}
+	if ac.Port <= 0 || ac.Port > 65535 {
+		return errors.New("invalid amqp.port field")
+	}
+	if emptyString(ac.Username) {
+		return errors.New("invalid amqp.username field")
+	}
+	if emptyString(ac.Password) {
+		return errors.New("invalid amqp.password field")
+	}
+	if emptyString(ac.Exchange) {
+		return errors.New("invalid amqp.exchange field")
+	}
+	if emptyString(ac.Queue) {
+		return errors.New("invalid amqp.queue field")
+	}
+	if ac.Prefetch < 0 {
+		return errors.New("invalid amqp.prefetch field")
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Queue(t *testing.T) {
+	config := QueueConfig{
+		Type:     "amqp",
+		Encoding: "json",
+		AMQP: AMQPConfig{
+			Host:       "127.0.0.1",
+			Port:       5672,
+			Username:   "guest",
+			Password:   "guest",
+			Exchange:   "calendar",
+			Queue:      "notifications",
+			RoutingKey: "notification",
+			Prefetch:   10,
+		},
+	}
+
+	tests := []struct {
+		description string
+		config      QueueConfig
+		changeFn    func(QueueConfig) QueueConfig
+		wantErr     bool
+	}{
+		{
+			description: "valid config",
+			config:      config,
+			changeFn:    func(qc QueueConfig) QueueConfig { return qc },
+			wantErr:     false,
+		},
+		{
+			description: "valid in-memory config",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.Type = "in-memory"
+				qc.Size = 100
+				qc.AMQP = AMQPConfig{}
+				return qc
+			},
+			wantErr: false,
+		},
+		{
+			description: "invalid type",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.Type = "kafka"
+				return qc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid encoding",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.Encoding = "xml"
+				return qc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid in-memory size",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.Type = "in-memory"
+				qc.Size = 0
+				return qc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid amqp host",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.AMQP.Host = ""
+				return qc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid amqp port",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.AMQP.Port = 0
+				return qc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid amqp exchange",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.AMQP.Exchange = ""
+				return qc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid amqp queue",
+			config:      config,
+			changeFn: func(qc QueueConfig) QueueConfig {
+				qc.AMQP.Queue = ""
+				return qc
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			config := tt.changeFn(tt.config)
+			err := config.validate()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultTrashRetention is used if trash_retention is not set.
+const DefaultTrashRetention = 720 * time.Hour
+
+type SchedulerConfig struct {
+	Interval             time.Duration `toml:"interval"`
+	EventsMaxAge         time.Duration `toml:"events_max_age"`
+	NotificationLookback time.Duration `toml:"notification_lookback"`
+	OutboxBatchSize      int           `toml:"outbox_batch_size"`
+	TrashRetention       time.Duration `toml:"trash_retention"`
+}
+
+func (sc SchedulerConfig) validate() error {
+	if sc.Interval <= 0 {
+		return errors.New("invalid interval field")
+	}
+	if sc.EventsMaxAge <= 0 {
+		return errors.New("invalid events_max_age field")
+	}
+	if sc.NotificationLookback < sc.Interval {
+		return errors.New("invalid notification_lookback field")
+	}
+	if sc.OutboxBatchSize <= 0 {
+		return errors.New("invalid outbox_batch_size field")
+	}
+	if sc.TrashRetention <= 0 {
+		return errors.New("invalid trash_retention field")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Scheduler(t *testing.T) {
+	config := SchedulerConfig{
+		Interval:             time.Minute,
+		EventsMaxAge:         8760 * time.Hour,
+		NotificationLookback: 24 * time.Hour,
+		OutboxBatchSize:      100,
+		TrashRetention:       720 * time.Hour,
+	}
+
+	tests := []struct {
+		description string
+		config      SchedulerConfig
+		changeFn    func(SchedulerConfig) SchedulerConfig
+		wantErr     bool
+	}{
+		{
+			description: "valid config",
+			config:      config,
+			changeFn:    func(sc SchedulerConfig) SchedulerConfig { return sc },
+			wantErr:     false,
+		},
+		{
+			description: "invalid interval",
+			config:      config,
+			changeFn: func(sc SchedulerConfig) SchedulerConfig {
+				sc.Interval = 0
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid events max age",
+			config:      config,
+			changeFn: func(sc SchedulerConfig) SchedulerConfig {
+				sc.EventsMaxAge = -time.Hour
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "notification lookback shorter than interval",
+			config:      config,
+			changeFn: func(sc SchedulerConfig) SchedulerConfig {
+				sc.NotificationLookback = time.Second
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid outbox batch size",
+			config:      config,
+			changeFn: func(sc SchedulerConfig) SchedulerConfig {
+				sc.OutboxBatchSize = 0
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid trash retention",
+			config:      config,
+			changeFn: func(sc SchedulerConfig) SchedulerConfig {
+				sc.TrashRetention = 0
+				return sc
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			config := tt.changeFn(tt.config)
+			err := config.validate()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
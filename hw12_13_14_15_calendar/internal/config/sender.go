@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+	SinkFile    = "file"
+)
+
+const (
+	// DefaultMaxRedeliveries is used if max_redeliveries is not set.
+	DefaultMaxRedeliveries = 10
+	// DefaultRedeliveryTTL is used if redelivery_ttl is not set.
+	DefaultRedeliveryTTL = time.Hour
+	// DefaultMaxTrackedRedeliveries is used if max_tracked_redeliveries is not
+	// set.
+	DefaultMaxTrackedRedeliveries = 10000
+)
+
+type SenderConfig struct {
+	Sinks         []string      `toml:"sinks"`
+	MaxRetries    int           `toml:"max_retries"`
+	RetryDelay    time.Duration `toml:"retry_delay"`
+	MaxRetryDelay time.Duration `toml:"max_retry_delay"`
+	// MaxRedeliveries is how many times a notification is requeued after its
+	// retries are exhausted before it is dead-lettered.
+	MaxRedeliveries int `toml:"max_redeliveries"`
+	// RedeliveryTTL is how long the sinks that are done with a requeued
+	// notification are remembered. A redelivery after it, or after a restart,
+	// is sent to every sink again.
+	RedeliveryTTL time.Duration `toml:"redelivery_ttl"`
+	// MaxTrackedRedeliveries is how many requeued notifications are
+	// remembered at most. The oldest one is forgotten above it.
+	MaxTrackedRedeliveries int           `toml:"max_tracked_redeliveries"`
+	Webhook                WebhookConfig `toml:"webhook"`
+	File                   FileConfig    `toml:"file"`
+}
+
+type WebhookConfig struct {
+	URL     string        `toml:"url"`
+	Timeout time.Duration `toml:"timeout"`
+}
+
+type FileConfig struct {
+	Path string `toml:"path"`
+}
+
+func (sc SenderConfig) validate() error {
+	if len(sc.Sinks) == 0 {
+		return errors.New("invalid sinks field")
+	}
+	for _, sink := range sc.Sinks {
+		switch sink {
+		case SinkLog:
+		case SinkWebhook:
+			if emptyString(sc.Webhook.URL) {
+				return errors.New("invalid webhook.url field")
+			}
+		case SinkFile:
+			if emptyString(sc.File.Path) {
+				return errors.New("invalid file.path field")
+			}
+		default:
+			return fmt.Errorf("invalid sink %q", sink)
+		}
+	}
+
+	if sc.MaxRetries < 0 {
+		return errors.New("invalid max_retries field")
+	}
+	if sc.RetryDelay <= 0 {
+		return errors.New("invalid retry_delay field")
+	}
+	if sc.MaxRetryDelay < sc.RetryDelay {
+		return errors.New("invalid max_retry_delay field")
+	}
+	if sc.MaxRedeliveries < 0 {
+		return errors.New("invalid max_redeliveries field")
+	}
+	if sc.RedeliveryTTL <= 0 {
+		return errors.New("invalid redelivery_ttl field")
+	}
+	if sc.MaxTrackedRedeliveries <= 0 {
+		return errors.New("invalid max_tracked_redeliveries field")
+	}
+	return nil
+}
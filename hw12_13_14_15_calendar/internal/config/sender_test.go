@@ -0,0 +1,145 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Sender(t *testing.T) {
+	config := SenderConfig{
+		Sinks:                  []string{SinkLog},
+		MaxRetries:             5,
+		RetryDelay:             time.Second,
+		MaxRetryDelay:          30 * time.Second,
+		RedeliveryTTL:          time.Hour,
+		MaxTrackedRedeliveries: 100,
+	}
+
+	tests := []struct {
+		description string
+		config      SenderConfig
+		changeFn    func(SenderConfig) SenderConfig
+		wantErr     bool
+	}{
+		{
+			description: "valid config",
+			config:      config,
+			changeFn:    func(sc SenderConfig) SenderConfig { return sc },
+			wantErr:     false,
+		},
+		{
+			description: "empty sinks",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.Sinks = nil
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "unknown sink",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.Sinks = []string{"email"}
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "webhook sink without url",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.Sinks = []string{SinkWebhook}
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "file sink without path",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.Sinks = []string{SinkFile}
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid max retries",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.MaxRetries = -1
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid retry delay",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.RetryDelay = 0
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid max retry delay",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.MaxRetryDelay = time.Millisecond
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "no redeliveries",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.MaxRedeliveries = 0
+				return sc
+			},
+			wantErr: false,
+		},
+		{
+			description: "invalid max redeliveries",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.MaxRedeliveries = -1
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid redelivery ttl",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.RedeliveryTTL = 0
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid max tracked redeliveries",
+			config:      config,
+			changeFn: func(sc SenderConfig) SenderConfig {
+				sc.MaxTrackedRedeliveries = 0
+				return sc
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			config := tt.changeFn(tt.config)
+			err := config.validate()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,11 +5,34 @@ import (
 	"time"
 )
 
+const (
+	// DefaultShutdownDelay is used if shutdown_delay is not set.
+	DefaultShutdownDelay = 5 * time.Second
+	// DefaultWatchDuration is used if watch.duration is not set.
+	DefaultWatchDuration = 10 * time.Minute
+	// DefaultWatchHeartbeat is used if watch.heartbeat is not set.
+	DefaultWatchHeartbeat = 15 * time.Second
+)
+
 type ServerHTTPConfig struct {
 	Host        string        `toml:"host"`
 	Port        int           `toml:"port"`
 	Timeout     time.Duration `toml:"timeout"`
 	IdleTimeout time.Duration `toml:"idle_timeout"`
+	// ShutdownDelay is how long /readyz reports the shutdown before the
+	// servers stop, so the probes take the instance out of rotation first.
+	ShutdownDelay time.Duration `toml:"shutdown_delay"`
+	// Watch configures the event stream, which is not limited by Timeout.
+	Watch WatchConfig `toml:"watch"`
+}
+
+type WatchConfig struct {
+	// Duration limits the duration of an event stream, after it the client
+	// reconnects. It is not limited if zero.
+	Duration time.Duration `toml:"duration"`
+	// Heartbeat is the interval of the comments that keep an idle event
+	// stream open through proxies.
+	Heartbeat time.Duration `toml:"heartbeat"`
 }
 
 func (sc ServerHTTPConfig) validate() error {
@@ -19,6 +42,15 @@ func (sc ServerHTTPConfig) validate() error {
 	if sc.Port <= 0 || sc.Port > 65535 {
 		return errors.New("invalid port field")
 	}
+	if sc.ShutdownDelay < 0 {
+		return errors.New("invalid shutdown_delay field")
+	}
+	if sc.Watch.Duration < 0 {
+		return errors.New("invalid watch.duration field")
+	}
+	if sc.Watch.Heartbeat <= 0 {
+		return errors.New("invalid watch.heartbeat field")
+	}
 
 	return nil
 }
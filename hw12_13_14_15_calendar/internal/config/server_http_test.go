@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -10,6 +11,10 @@ func TestValidate_ServerHTTP(t *testing.T) {
 	config := ServerHTTPConfig{
 		Host: "127.0.0.1",
 		Port: 8080,
+		Watch: WatchConfig{
+			Duration:  time.Minute,
+			Heartbeat: time.Second,
+		},
 	}
 
 	tests := []struct {
@@ -42,6 +47,51 @@ func TestValidate_ServerHTTP(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			description: "no shutdown delay",
+			config:      config,
+			changeFn: func(sc ServerHTTPConfig) ServerHTTPConfig {
+				sc.ShutdownDelay = 0
+				return sc
+			},
+			wantErr: false,
+		},
+		{
+			description: "invalid shutdown delay",
+			config:      config,
+			changeFn: func(sc ServerHTTPConfig) ServerHTTPConfig {
+				sc.ShutdownDelay = -time.Second
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "unlimited watch duration",
+			config:      config,
+			changeFn: func(sc ServerHTTPConfig) ServerHTTPConfig {
+				sc.Watch.Duration = 0
+				return sc
+			},
+			wantErr: false,
+		},
+		{
+			description: "invalid watch duration",
+			config:      config,
+			changeFn: func(sc ServerHTTPConfig) ServerHTTPConfig {
+				sc.Watch.Duration = -time.Second
+				return sc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid watch heartbeat",
+			config:      config,
+			changeFn: func(sc ServerHTTPConfig) ServerHTTPConfig {
+				sc.Watch.Heartbeat = 0
+				return sc
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
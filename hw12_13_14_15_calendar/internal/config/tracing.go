@@ -0,0 +1,39 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+type TracingConfig struct {
+	Exporter    string  `toml:"exporter"`
+	Path        string  `toml:"path"`
+	ServiceName string  `toml:"service_name"`
+	SampleRatio float64 `toml:"sample_ratio"`
+}
+
+func (tc TracingConfig) validate() error {
+	switch tc.Exporter {
+	case TracingExporterNone, TracingExporterStdout:
+	case TracingExporterFile:
+		if emptyString(tc.Path) {
+			return errors.New("invalid path field")
+		}
+	default:
+		return fmt.Errorf("invalid exporter %q", tc.Exporter)
+	}
+
+	if emptyString(tc.ServiceName) {
+		return errors.New("invalid service_name field")
+	}
+	if tc.SampleRatio < 0 || tc.SampleRatio > 1 {
+		return errors.New("invalid sample_ratio field")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate_Tracing(t *testing.T) {
+	config := TracingConfig{
+		Exporter:    TracingExporterStdout,
+		ServiceName: "calendar",
+		SampleRatio: 1,
+	}
+
+	tests := []struct {
+		description string
+		config      TracingConfig
+		changeFn    func(TracingConfig) TracingConfig
+		wantErr     bool
+	}{
+		{
+			description: "valid config",
+			config:      config,
+			changeFn:    func(tc TracingConfig) TracingConfig { return tc },
+			wantErr:     false,
+		},
+		{
+			description: "disabled tracing",
+			config:      config,
+			changeFn: func(tc TracingConfig) TracingConfig {
+				tc.Exporter = TracingExporterNone
+				return tc
+			},
+			wantErr: false,
+		},
+		{
+			description: "unknown exporter",
+			config:      config,
+			changeFn: func(tc TracingConfig) TracingConfig {
+				tc.Exporter = "jaeger"
+				return tc
+			},
+			wantErr: true,
+		},
+		{
+			description: "file exporter without path",
+			config:      config,
+			changeFn: func(tc TracingConfig) TracingConfig {
+				tc.Exporter = TracingExporterFile
+				return tc
+			},
+			wantErr: true,
+		},
+		{
+			description: "empty service name",
+			config:      config,
+			changeFn: func(tc TracingConfig) TracingConfig {
+				tc.ServiceName = ""
+				return tc
+			},
+			wantErr: true,
+		},
+		{
+			description: "invalid sample ratio",
+			config:      config,
+			changeFn: func(tc TracingConfig) TracingConfig {
+				tc.SampleRatio = 1.5
+				return tc
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			config := tt.changeFn(tt.config)
+			err := config.validate()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package feed
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// ErrSlowSubscriber closes a subscription that did not keep up with the feed.
+var ErrSlowSubscriber = errors.New("subscriber is too slow, changes were dropped")
+
+// Feed delivers the changes of events to the subscribed users in process.
+// Publish never blocks: a subscriber whose buffer is full is closed with
+// ErrSlowSubscriber, so it can reload the events and subscribe again.
+type Feed struct {
+	mu   sync.Mutex
+	subs map[int64]map[*Subscription]struct{}
+	size int
+}
+
+func New(size int) *Feed {
+	return &Feed{
+		subs: make(map[int64]map[*Subscription]struct{}),
+		size: size,
+	}
+}
+
+// Subscription receives the changes of the events the user can read.
+type Subscription struct {
+	feed    *Feed
+	userID  int64
+	changes chan models.EventChange
+	err     error
+}
+
+// Subscribe starts delivering the changes for the user. The subscription must
+// be closed when it is not needed anymore.
+func (f *Feed) Subscribe(userID int64) *Subscription {
+	sub := &Subscription{
+		feed:    f,
+		userID:  userID,
+		changes: make(chan models.EventChange, f.size),
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.subs[userID]; !ok {
+		f.subs[userID] = make(map[*Subscription]struct{})
+	}
+	f.subs[userID][sub] = struct{}{}
+	return sub
+}
+
+// Publish delivers the change to the subscriptions of its users.
+func (f *Feed) Publish(change models.EventChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, userID := range change.UserIDs {
+		for sub := range f.subs[userID] {
+			select {
+			case sub.changes <- change:
+			default:
+				f.remove(sub, ErrSlowSubscriber)
+			}
+		}
+	}
+}
+
+// remove closes the subscription. It must be called with the lock held.
+func (f *Feed) remove(sub *Subscription, err error) {
+	subs, ok := f.subs[sub.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(f.subs, sub.userID)
+	}
+	sub.err = err
+	close(sub.changes)
+}
+
+// Changes returns the channel of changes. It is closed when the subscription
+// is closed.
+func (s *Subscription) Changes() <-chan models.EventChange {
+	return s.changes
+}
+
+// Err returns ErrSlowSubscriber if the feed closed the subscription. It must
+// be called after the channel of changes is closed.
+func (s *Subscription) Err() error {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+
+	return s.err
+}
+
+// Close stops the delivery of changes.
+func (s *Subscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+
+	s.feed.remove(s, nil)
+}
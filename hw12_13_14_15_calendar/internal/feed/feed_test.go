@@ -0,0 +1,69 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func change(eventID string, userIDs ...int64) models.EventChange {
+	return models.EventChange{
+		Type:    models.ChangeCreated,
+		Event:   models.Event{ID: eventID},
+		UserIDs: userIDs,
+	}
+}
+
+func TestFeed(t *testing.T) {
+	t.Run("changes are delivered to their users", func(t *testing.T) {
+		feed := New(10)
+
+		sub1 := feed.Subscribe(1)
+		defer sub1.Close()
+		sub2 := feed.Subscribe(2)
+		defer sub2.Close()
+
+		feed.Publish(change("event-1", 1))
+		feed.Publish(change("event-2", 1, 2))
+
+		require.Equal(t, "event-1", (<-sub1.Changes()).Event.ID)
+		require.Equal(t, "event-2", (<-sub1.Changes()).Event.ID)
+		require.Equal(t, "event-2", (<-sub2.Changes()).Event.ID)
+		require.Empty(t, sub2.Changes())
+	})
+
+	t.Run("slow subscriber is closed", func(t *testing.T) {
+		feed := New(1)
+
+		slow := feed.Subscribe(1)
+		fast := feed.Subscribe(1)
+		defer fast.Close()
+
+		feed.Publish(change("event-1", 1))
+		require.Equal(t, "event-1", (<-fast.Changes()).Event.ID)
+
+		// The buffer of slow is full, publishing does not block.
+		feed.Publish(change("event-2", 1))
+		require.Equal(t, "event-2", (<-fast.Changes()).Event.ID)
+
+		require.Equal(t, "event-1", (<-slow.Changes()).Event.ID)
+		_, ok := <-slow.Changes()
+		require.False(t, ok)
+		require.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+
+		slow.Close()
+	})
+
+	t.Run("closed subscription", func(t *testing.T) {
+		feed := New(1)
+
+		sub := feed.Subscribe(1)
+		sub.Close()
+		feed.Publish(change("event-1", 1))
+
+		_, ok := <-sub.Changes()
+		require.False(t, ok)
+		require.NoError(t, sub.Err())
+	})
+}
@@ -0,0 +1,44 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var ErrShuttingDown = errors.New("service is shutting down")
+
+// Pinger checks the connection to a dependency.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Checker reports whether the service is ready to handle requests.
+type Checker struct {
+	pingers      []Pinger
+	shuttingDown atomic.Bool
+}
+
+func New(pingers ...Pinger) *Checker {
+	return &Checker{pingers: pingers}
+}
+
+// Ready returns an error if the service is shutting down or one of its
+// dependencies is unreachable.
+func (c *Checker) Ready(ctx context.Context) error {
+	if c.shuttingDown.Load() {
+		return ErrShuttingDown
+	}
+	for _, pinger := range c.pingers {
+		if err := pinger.Ping(ctx); err != nil {
+			return fmt.Errorf("ping dependency: %w", err)
+		}
+	}
+	return nil
+}
+
+// Shutdown makes the service not ready for the rest of its life.
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
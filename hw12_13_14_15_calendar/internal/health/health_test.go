@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestChecker(t *testing.T) {
+	errPing := errors.New("connection refused")
+
+	cases := []struct {
+		name     string
+		pingers  []Pinger
+		shutdown bool
+		err      error
+	}{
+		{
+			name: "ready without dependencies",
+		},
+		{
+			name:    "ready",
+			pingers: []Pinger{pingerFunc(func(context.Context) error { return nil })},
+		},
+		{
+			name:    "dependency is unreachable",
+			pingers: []Pinger{pingerFunc(func(context.Context) error { return errPing })},
+			err:     errPing,
+		},
+		{
+			name:     "shutting down",
+			pingers:  []Pinger{pingerFunc(func(context.Context) error { return nil })},
+			shutdown: true,
+			err:      ErrShuttingDown,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := New(tc.pingers...)
+			if tc.shutdown {
+				checker.Shutdown()
+			}
+
+			err := checker.Ready(context.Background())
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
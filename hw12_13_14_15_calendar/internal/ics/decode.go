@@ -0,0 +1,361 @@
+package ics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/rrule"
+)
+
+const (
+	dateFormat          = "20060102"
+	localDateTimeFormat = "20060102T150405"
+)
+
+var ErrInvalidCalendar = errors.New("invalid calendar")
+
+var durationUnits = map[byte]time.Duration{
+	'W': 7 * 24 * time.Hour,
+	'D': 24 * time.Hour,
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Decode reads the VEVENT components of a VCALENDAR object. The ID of a
+// decoded event holds its UID, the UserID is left empty.
+func Decode(r io.Reader) ([]models.Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events     []models.Event
+		event      *models.Event
+		alarm      *property
+		hasEnd     bool
+		duration   *time.Duration
+		components []string
+	)
+
+	for i, line := range lines {
+		prop, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, i+1, err)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(prop.value))
+			if components[len(components)-1] == "VEVENT" {
+				event, alarm, hasEnd, duration = &models.Event{}, nil, false, nil
+			}
+			continue
+
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(prop.value) {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalidCalendar, i+1, prop.value)
+			}
+			components = components[:len(components)-1]
+
+			if strings.EqualFold(prop.value, "VEVENT") {
+				if err := finishEvent(event, alarm, hasEnd, duration); err != nil {
+					return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, i+1, err)
+				}
+				events = append(events, *event)
+				event = nil
+			}
+			continue
+		}
+
+		if event == nil || len(components) == 0 {
+			continue
+		}
+
+		switch components[len(components)-1] {
+		case "VEVENT":
+			err = setEventProperty(event, prop, &hasEnd, &duration)
+		case "VALARM":
+			if prop.name == "TRIGGER" && alarm == nil {
+				alarm = &prop
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCalendar, i+1, err)
+		}
+	}
+
+	if len(components) != 0 {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrInvalidCalendar, components[len(components)-1])
+	}
+	return events, nil
+}
+
+func setEventProperty(event *models.Event, prop property, hasEnd *bool, duration **time.Duration) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		event.ID = prop.value
+
+	case "SUMMARY":
+		event.Title = unescapeText(prop.value)
+
+	case "DESCRIPTION":
+		description := unescapeText(prop.value)
+		event.Description = &description
+
+	case "DTSTART":
+		event.StartDate, err = parseTime(prop)
+		if err == nil && !strings.HasSuffix(prop.value, "Z") {
+			event.Timezone = prop.params["TZID"]
+		}
+		if err == nil && isDate(prop) {
+			allDay := true
+			event.AllDay = &allDay
+		}
+
+	case "DTEND":
+		event.EndDate, err = parseTime(prop)
+		*hasEnd = true
+
+	case "DURATION":
+		var d time.Duration
+		d, err = parseDuration(prop.value)
+		*duration = &d
+
+	case "RRULE":
+		if _, err = rrule.Parse(prop.value); err == nil {
+			value := prop.value
+			event.RecurrenceRule = &value
+		}
+
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var date time.Time
+			date, err = parseTime(property{name: prop.name, params: prop.params, value: value})
+			if err != nil {
+				break
+			}
+			event.ExceptionDates = append(event.ExceptionDates, date)
+		}
+	}
+	return err
+}
+
+func finishEvent(event *models.Event, alarm *property, hasEnd bool, duration *time.Duration) error {
+	if event.StartDate.IsZero() {
+		return errors.New("DTSTART is missing")
+	}
+
+	switch {
+	case hasEnd:
+	case duration != nil:
+		event.EndDate = event.StartDate.Add(*duration)
+	default:
+		event.EndDate = event.StartDate
+	}
+
+	if event.EndDate.Before(event.StartDate) {
+		return errors.New("DTEND is before DTSTART")
+	}
+	if err := models.CheckEventDuration(event.StartDate, event.EndDate); err != nil {
+		return err
+	}
+
+	if alarm != nil {
+		notificationTime, err := parseTrigger(*alarm, event)
+		if err != nil {
+			return err
+		}
+		event.NotificationTime = notificationTime
+	}
+	return nil
+}
+
+// parseTrigger returns how long before the start of the event the alarm
+// fires. Alarms that fire after the start of the event are ignored.
+func parseTrigger(prop property, event *models.Event) (*time.Duration, error) {
+	var notificationTime time.Duration
+	if strings.EqualFold(prop.params["VALUE"], "DATE-TIME") {
+		t, err := parseTime(property{name: prop.name, value: prop.value})
+		if err != nil {
+			return nil, err
+		}
+		notificationTime = event.StartDate.Sub(t)
+	} else {
+		d, err := parseDuration(prop.value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(prop.params["RELATED"], "END") {
+			d += event.EndDate.Sub(event.StartDate)
+		}
+		notificationTime = -d
+	}
+
+	if notificationTime < 0 {
+		return nil, nil
+	}
+	return &notificationTime, nil
+}
+
+// unfold reads the content lines, joining the folded ones.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// parseLine splits a content line into the name, the parameters and the value.
+func parseLine(line string) (property, error) {
+	prop := property{params: make(map[string]string)}
+
+	var quoted bool
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("missing value in %q", line)
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(parts[0])
+	prop.value = line[colon+1:]
+
+	for _, param := range parts[1:] {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return prop, fmt.Errorf("invalid parameter %q", param)
+		}
+		prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// isDate reports whether the property holds a date without a time.
+func isDate(prop property) bool {
+	return strings.EqualFold(prop.params["VALUE"], "DATE") || len(prop.value) == len(dateFormat)
+}
+
+func parseTime(prop property) (time.Time, error) {
+	value := prop.value
+	if isDate(prop) {
+		return time.ParseInLocation(dateFormat, value, time.UTC)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(dateTimeFormat, value)
+	}
+
+	loc := time.UTC
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+
+	t, err := time.ParseInLocation(localDateTimeFormat, value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// parseDuration parses an RFC 5545 duration value such as -PT15M or P1DT2H.
+func parseDuration(value string) (time.Duration, error) {
+	s := value
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign = -1
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) == 1 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	var inTime bool
+	for s != "" {
+		if s[0] == 'T' {
+			inTime = true
+			s = s[1:]
+			continue
+		}
+
+		end := strings.IndexAny(s, "WDHMS")
+		if end <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		n, err := strconv.Atoi(s[:end])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		unit := durationUnits[s[end]]
+		if inTime != (unit < 24*time.Hour) {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		d += time.Duration(n) * unit
+		s = s[end+1:]
+	}
+	return sign * d, nil
+}
+
+func unescapeText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+// Package ics encodes and decodes events in the iCalendar format (RFC 5545).
+// Only VEVENT components are supported with SUMMARY, DESCRIPTION, DTSTART,
+// DTEND, RRULE, EXDATE and a VALARM whose TRIGGER sets the notification time.
+// Events with DATE values of DTSTART are all-day events. Timed events in a time
+// zone other than UTC are written in local time with the IANA name of the
+// zone as TZID, so that recurrences keep their local time across DST.
+package ics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+const (
+	ContentType = "text/calendar"
+
+	prodID         = "-//go-practice//calendar//EN"
+	dateTimeFormat = "20060102T150405Z"
+	maxLineLength  = 75
+)
+
+// Encode writes the events as a VCALENDAR object.
+func Encode(w io.Writer, events []models.Event) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	stamp := time.Now()
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", prodID)
+	e.line("CALSCALE", "GREGORIAN")
+	for i := range events {
+		e.event(&events[i], stamp)
+	}
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) event(event *models.Event, stamp time.Time) {
+	e.line("BEGIN", "VEVENT")
+	e.line("UID", escapeText(event.ID))
+	e.line("DTSTAMP", formatTime(stamp))
+	format := timeFormatter(event)
+	e.line("DTSTART"+format.params, format.value(event.StartDate))
+	e.line("DTEND"+format.params, format.value(event.EndDate))
+	e.line("SUMMARY", escapeText(event.Title))
+	if event.Description != nil {
+		e.line("DESCRIPTION", escapeText(*event.Description))
+	}
+	if event.IsRecurring() {
+		e.line("RRULE", strings.TrimPrefix(*event.RecurrenceRule, "RRULE:"))
+	}
+	if len(event.ExceptionDates) > 0 {
+		dates := make([]string, len(event.ExceptionDates))
+		for i := range event.ExceptionDates {
+			dates[i] = format.value(event.ExceptionDates[i])
+		}
+		e.line("EXDATE"+format.params, strings.Join(dates, ","))
+	}
+	if event.NotificationTime != nil {
+		e.line("BEGIN", "VALARM")
+		e.line("ACTION", "DISPLAY")
+		e.line("DESCRIPTION", escapeText(event.Title))
+		e.line("TRIGGER", formatDuration(-*event.NotificationTime))
+		e.line("END", "VALARM")
+	}
+	e.line("END", "VEVENT")
+}
+
+// line writes a content line folded to 75 octets.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+	for len(line) > maxLineLength {
+		cut := maxLineLength
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(line[:cut] + "\r\n"); e.err != nil {
+			return
+		}
+		line = " " + line[cut:]
+	}
+	_, e.err = e.w.WriteString(line + "\r\n")
+}
+
+// formatter writes the DTSTART, DTEND and EXDATE values of an event.
+type formatter struct {
+	params string
+	value  func(time.Time) string
+}
+
+// timeFormatter returns the formatter of DATE values for all-day events, of
+// local DATE-TIME values with TZID for events in a time zone other than UTC,
+// and of UTC DATE-TIME values otherwise.
+func timeFormatter(event *models.Event) formatter {
+	loc := location(event.Timezone)
+	switch {
+	case event.IsAllDay():
+		return formatter{
+			params: ";VALUE=DATE",
+			value:  func(t time.Time) string { return formatDate(t, loc) },
+		}
+	case loc != time.UTC:
+		return formatter{
+			params: ";TZID=" + loc.String(),
+			value:  func(t time.Time) string { return t.In(loc).Format(localDateTimeFormat) },
+		}
+	default:
+		return formatter{value: formatTime}
+	}
+}
+
+// location returns the time zone, UTC if it is unknown or not set.
+func location(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// formatDate formats the date of t in the time zone.
+func formatDate(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(dateFormat)
+}
+
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// formatDuration formats the duration as an RFC 5545 duration value.
+func formatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+
+	const day = 24 * time.Hour
+	days := d / day
+	d -= days * day
+	if days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		if d == 0 {
+			return b.String()
+		}
+	}
+
+	b.WriteByte('T')
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dH", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dM", minutes)
+	}
+	if seconds > 0 || hours == 0 && minutes == 0 {
+		fmt.Fprintf(&b, "%dS", seconds)
+	}
+	return b.String()
+}
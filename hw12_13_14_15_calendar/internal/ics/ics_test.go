@@ -0,0 +1,198 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	description := "line one\nline two; with, separators \\ and a long tail that has to be folded"
+	rule := "FREQ=WEEKLY;BYDAY=MO,WE"
+	notificationTime := 90 * time.Minute
+	allDay := true
+
+	events := []models.Event{
+		{
+			ID:               "id-1",
+			Title:            "Встреча",
+			Description:      &description,
+			StartDate:        time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+			EndDate:          time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+			NotificationTime: &notificationTime,
+			RecurrenceRule:   &rule,
+			ExceptionDates:   models.Dates{time.Date(2023, 8, 21, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			ID:        "id-2",
+			Title:     "simple",
+			StartDate: time.Date(2023, 8, 17, 9, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2023, 8, 17, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			ID:        "id-3",
+			Title:     "all day",
+			StartDate: time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2023, 8, 20, 0, 0, 0, 0, time.UTC),
+			AllDay:    &allDay,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLength)
+	}
+	require.Contains(t, buf.String(), "TRIGGER:-PT1H30M\r\n")
+	require.Contains(t, buf.String(), "DTEND;VALUE=DATE:20230820\r\n")
+
+	got, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, events, got)
+}
+
+func TestEncodeDecodeTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	rule := "FREQ=WEEKLY"
+	allDay := true
+
+	// The weekly event starts in summer time and has occurrences in winter
+	// time, a week after the end of DST.
+	events := []models.Event{
+		{
+			ID:             "id-1",
+			Title:          "weekly",
+			StartDate:      time.Date(2023, 10, 16, 10, 0, 0, 0, berlin).UTC(),
+			EndDate:        time.Date(2023, 10, 16, 11, 0, 0, 0, berlin).UTC(),
+			RecurrenceRule: &rule,
+			ExceptionDates: models.Dates{time.Date(2023, 11, 6, 10, 0, 0, 0, berlin).UTC()},
+			Timezone:       "Europe/Berlin",
+		},
+		{
+			ID:             "id-2",
+			Title:          "all day",
+			StartDate:      time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC),
+			EndDate:        time.Date(2023, 10, 17, 0, 0, 0, 0, time.UTC),
+			RecurrenceRule: &rule,
+			ExceptionDates: models.Dates{time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)},
+			AllDay:         &allDay,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+
+	require.Contains(t, buf.String(), "DTSTART;TZID=Europe/Berlin:20231016T100000\r\n")
+	require.Contains(t, buf.String(), "DTEND;TZID=Europe/Berlin:20231016T110000\r\n")
+	require.Contains(t, buf.String(), "EXDATE;TZID=Europe/Berlin:20231106T100000\r\n")
+	require.Contains(t, buf.String(), "EXDATE;VALUE=DATE:20231106\r\n")
+
+	got, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Equal(t, events, got)
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("time zones, durations and alarms", func(t *testing.T) {
+		input := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Moscow",
+			"BEGIN:STANDARD",
+			"DTSTART:19700101T000000",
+			"END:STANDARD",
+			"END:VTIMEZONE",
+			"BEGIN:VEVENT",
+			"SUMMARY:local",
+			"DTSTART;TZID=Europe/Moscow:20230816T120000",
+			"DURATION:PT45M",
+			"BEGIN:VALARM",
+			"TRIGGER;RELATED=START:-P1D",
+			"END:VALARM",
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"SUMMARY:all day",
+			"DTSTART;VALUE=DATE:20230817",
+			"DTEND;VALUE=DATE:20230818",
+			"BEGIN:VALARM",
+			"TRIGGER;VALUE=DATE-TIME:20230816T230000Z",
+			"END:VALARM",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\n")
+
+		got, err := Decode(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+
+		require.Equal(t, time.Date(2023, 8, 16, 9, 0, 0, 0, time.UTC), got[0].StartDate)
+		require.Equal(t, time.Date(2023, 8, 16, 9, 45, 0, 0, time.UTC), got[0].EndDate)
+		require.Equal(t, 24*time.Hour, *got[0].NotificationTime)
+		require.Equal(t, "Europe/Moscow", got[0].Timezone)
+
+		require.Equal(t, time.Date(2023, 8, 17, 0, 0, 0, 0, time.UTC), got[1].StartDate)
+		require.Equal(t, time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC), got[1].EndDate)
+		require.Equal(t, time.Hour, *got[1].NotificationTime)
+		require.True(t, got[1].IsAllDay())
+		require.False(t, got[0].IsAllDay())
+	})
+
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "missing DTSTART",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:test\nEND:VEVENT\nEND:VCALENDAR",
+		},
+		{
+			name:  "unsupported RRULE",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20230816T120000Z\nRRULE:FREQ=HOURLY\nEND:VEVENT\nEND:VCALENDAR",
+		},
+		{
+			name:  "too long event",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20230816T120000Z\nDTEND:21230816T120000Z\nEND:VEVENT\nEND:VCALENDAR",
+		},
+		{
+			name:  "not closed",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20230816T120000Z\n",
+		},
+		{
+			name:  "invalid duration",
+			input: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20230816T120000Z\nDURATION:PT1D\nEND:VEVENT\nEND:VCALENDAR",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tc.input))
+			require.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		-15 * time.Minute:           "-PT15M",
+		-(24*time.Hour + time.Hour): "-P1DT1H",
+		-48 * time.Hour:             "-P2D",
+		0:                           "PT0S",
+		90 * time.Second:            "PT1M30S",
+	}
+
+	for d, want := range cases {
+		require.Equal(t, want, formatDuration(d))
+
+		got, err := parseDuration(want)
+		require.NoError(t, err)
+		require.Equal(t, d, got)
+	}
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+const namespace = "calendar"
+
+// Metrics holds the Prometheus collectors of the calendar service.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	grpcInFlight prometheus.Gauge
+
+	storageDuration *prometheus.HistogramVec
+}
+
+// EventCounter reports the number of stored events.
+type EventCounter interface {
+	CountEvents() (active, deleted int)
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of handled HTTP requests.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being handled.",
+		}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Number of handled gRPC requests.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of gRPC requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		grpcInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_in_flight",
+			Help:      "Number of gRPC requests being handled.",
+		}),
+		storageDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of storage operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "status"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.httpInFlight,
+		m.grpcRequests,
+		m.grpcDuration,
+		m.grpcInFlight,
+		m.storageDuration,
+	)
+	return m
+}
+
+// Handler serves the collected metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterEventCounter exposes the number of active and deleted events of
+// the counter.
+func (m *Metrics) RegisterEventCounter(counter EventCounter) {
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "storage",
+			Name:        "events",
+			Help:        "Number of stored events.",
+			ConstLabels: prometheus.Labels{"state": "active"},
+		}, func() float64 {
+			active, _ := counter.CountEvents()
+			return float64(active)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace:   namespace,
+			Subsystem:   "storage",
+			Name:        "events",
+			Help:        "Number of stored events.",
+			ConstLabels: prometheus.Labels{"state": "deleted"},
+		}, func() float64 {
+			_, deleted := counter.CountEvents()
+			return float64(deleted)
+		}),
+	)
+}
+
+// StartHTTPRequest counts an HTTP request in flight. The returned function
+// records the request once it is handled.
+func (m *Metrics) StartHTTPRequest() func(method, route string, code int) {
+	start := time.Now()
+	m.httpInFlight.Inc()
+
+	return func(method, route string, code int) {
+		m.httpInFlight.Dec()
+		m.httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+		m.httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// StartGRPCRequest counts a gRPC request in flight. The returned function
+// records the request once it is handled.
+func (m *Metrics) StartGRPCRequest(method string) func(code codes.Code) {
+	start := time.Now()
+	m.grpcInFlight.Inc()
+
+	return func(code codes.Code) {
+		m.grpcInFlight.Dec()
+		m.grpcRequests.WithLabelValues(method, code.String()).Inc()
+		m.grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveStorageOperation records the latency of a storage operation.
+func (m *Metrics) ObserveStorageOperation(operation string, start time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	m.storageDuration.WithLabelValues(operation, status).Observe(time.Since(start).Seconds())
+}
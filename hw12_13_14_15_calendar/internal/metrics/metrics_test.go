@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestStorage(t *testing.T) {
+	m := New()
+	db := memorystorage.New()
+	m.RegisterEventCounter(db)
+	s := NewStorage(db, m)
+
+	ctx := context.Background()
+	require.NoError(t, s.CreateEvent(ctx, &models.Event{ID: "1", UserID: 1}))
+	require.NoError(t, s.CreateEvent(ctx, &models.Event{ID: "2", UserID: 1}))
+	require.NoError(t, s.DeleteEvent(ctx, 1, "2", 0))
+	_, err := s.GetEvent(ctx, 1, "3")
+	require.ErrorIs(t, err, storage.ErrEventNotExist)
+
+	cases := []struct {
+		operation string
+		status    string
+		count     int
+	}{
+		{operation: "CreateEvent", status: "ok", count: 2},
+		{operation: "DeleteEvent", status: "ok", count: 1},
+		{operation: "GetEvent", status: "error", count: 1},
+		{operation: "GetEvent", status: "ok", count: 0},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.operation+"/"+tc.status, func(t *testing.T) {
+			histogram := m.storageDuration.WithLabelValues(tc.operation, tc.status).(prometheus.Histogram)
+			metric := &dto.Metric{}
+			require.NoError(t, histogram.Write(metric))
+			require.Equal(t, uint64(tc.count), metric.GetHistogram().GetSampleCount())
+		})
+	}
+
+	expected := `
+# HELP calendar_storage_events Number of stored events.
+# TYPE calendar_storage_events gauge
+calendar_storage_events{state="active"} 1
+calendar_storage_events{state="deleted"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(m.registry, strings.NewReader(expected), "calendar_storage_events"))
+}
+
+func TestStartGRPCRequest(t *testing.T) {
+	m := New()
+
+	done := m.StartGRPCRequest("/calendar.Calendar/GetEvent")
+	require.Equal(t, 1.0, testutil.ToFloat64(m.grpcInFlight))
+
+	done(codes.NotFound)
+	require.Equal(t, 0.0, testutil.ToFloat64(m.grpcInFlight))
+	require.Equal(t, 1.0, testutil.ToFloat64(
+		m.grpcRequests.WithLabelValues("/calendar.Calendar/GetEvent", codes.NotFound.String())))
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/calendar"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// Storage records the latency of every operation of the wrapped storage.
+type Storage struct {
+	next    calendar.Storage
+	metrics *Metrics
+}
+
+var _ calendar.Storage = (*Storage)(nil)
+
+func NewStorage(next calendar.Storage, metrics *Metrics) *Storage {
+	return &Storage{
+		next:    next,
+		metrics: metrics,
+	}
+}
+
+func (s *Storage) observe(operation string, start time.Time, err *error) {
+	s.metrics.ObserveStorageOperation(operation, start, *err)
+}
+
+func (s *Storage) CreateEvent(ctx context.Context, event *models.Event) (err error) {
+	defer s.observe("CreateEvent", time.Now(), &err)
+	return s.next.CreateEvent(ctx, event)
+}
+
+func (s *Storage) CreateEvents(ctx context.Context, events []*models.Event) (_ []string, err error) {
+	defer s.observe("CreateEvents", time.Now(), &err)
+	return s.next.CreateEvents(ctx, events)
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, userID int64, event *models.Event) (err error) {
+	defer s.observe("UpdateEvent", time.Now(), &err)
+	return s.next.UpdateEvent(ctx, userID, event)
+}
+
+func (s *Storage) DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) (err error) {
+	defer s.observe("DeleteEvent", time.Now(), &err)
+	return s.next.DeleteEvent(ctx, userID, eventID, version)
+}
+
+func (s *Storage) GetDeletedEvents(ctx context.Context, userID int64) (_ []models.Event, err error) {
+	defer s.observe("GetDeletedEvents", time.Now(), &err)
+	return s.next.GetDeletedEvents(ctx, userID)
+}
+
+func (s *Storage) RestoreEvent(ctx context.Context, userID int64, eventID string) (err error) {
+	defer s.observe("RestoreEvent", time.Now(), &err)
+	return s.next.RestoreEvent(ctx, userID, eventID)
+}
+
+func (s *Storage) GetEventHistory(ctx context.Context, userID int64, eventID string) (_ []models.HistoryEntry, err error) { //nolint:lll
+	defer s.observe("GetEventHistory", time.Now(), &err)
+	return s.next.GetEventHistory(ctx, userID, eventID)
+}
+
+func (s *Storage) GetEvent(ctx context.Context, userID int64, eventID string) (_ *models.Event, err error) {
+	defer s.observe("GetEvent", time.Now(), &err)
+	return s.next.GetEvent(ctx, userID, eventID)
+}
+
+func (s *Storage) GetEventByDay(ctx context.Context, userID int64, day time.Time, filter models.EventFilter) (_ []models.Event, _ string, err error) { //nolint:lll
+	defer s.observe("GetEventByDay", time.Now(), &err)
+	return s.next.GetEventByDay(ctx, userID, day, filter)
+}
+
+func (s *Storage) GetEventByWeek(ctx context.Context, userID int64, week time.Time, filter models.EventFilter) (_ []models.Event, _ string, err error) { //nolint:lll
+	defer s.observe("GetEventByWeek", time.Now(), &err)
+	return s.next.GetEventByWeek(ctx, userID, week, filter)
+}
+
+func (s *Storage) GetEventByMonth(ctx context.Context, userID int64, month time.Time, filter models.EventFilter) (_ []models.Event, _ string, err error) { //nolint:lll
+	defer s.observe("GetEventByMonth", time.Now(), &err)
+	return s.next.GetEventByMonth(ctx, userID, month, filter)
+}
+
+func (s *Storage) GetEventsInRange(ctx context.Context, userID int64, from, to time.Time) (_ []models.Event, err error) { //nolint:lll
+	defer s.observe("GetEventsInRange", time.Now(), &err)
+	return s.next.GetEventsInRange(ctx, userID, from, to)
+}
+
+func (s *Storage) GetEventsByUser(ctx context.Context, userID int64) (_ []models.Event, err error) {
+	defer s.observe("GetEventsByUser", time.Now(), &err)
+	return s.next.GetEventsByUser(ctx, userID)
+}
+
+func (s *Storage) CreateCalendar(ctx context.Context, calendar *models.Calendar) (err error) {
+	defer s.observe("CreateCalendar", time.Now(), &err)
+	return s.next.CreateCalendar(ctx, calendar)
+}
+
+func (s *Storage) GetCalendar(ctx context.Context, userID int64, calendarID string) (_ *models.Calendar, err error) {
+	defer s.observe("GetCalendar", time.Now(), &err)
+	return s.next.GetCalendar(ctx, userID, calendarID)
+}
+
+func (s *Storage) GetCalendars(ctx context.Context, userID int64) (_ []models.Calendar, err error) {
+	defer s.observe("GetCalendars", time.Now(), &err)
+	return s.next.GetCalendars(ctx, userID)
+}
+
+func (s *Storage) UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) (err error) {
+	defer s.observe("UpdateCalendar", time.Now(), &err)
+	return s.next.UpdateCalendar(ctx, userID, calendar)
+}
+
+func (s *Storage) DeleteCalendar(ctx context.Context, userID int64, calendarID string) (err error) {
+	defer s.observe("DeleteCalendar", time.Now(), &err)
+	return s.next.DeleteCalendar(ctx, userID, calendarID)
+}
+
+func (s *Storage) GetACL(ctx context.Context, userID int64, calendarID string) (_ []models.ACLEntry, err error) {
+	defer s.observe("GetACL", time.Now(), &err)
+	return s.next.GetACL(ctx, userID, calendarID)
+}
+
+func (s *Storage) SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) (err error) {
+	defer s.observe("SetACLEntry", time.Now(), &err)
+	return s.next.SetACLEntry(ctx, userID, entry)
+}
+
+func (s *Storage) DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) (err error) {
+	defer s.observe("DeleteACLEntry", time.Now(), &err)
+	return s.next.DeleteACLEntry(ctx, userID, calendarID, memberID)
+}
+
+func (s *Storage) InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) (err error) {
+	defer s.observe("InviteAttendees", time.Now(), &err)
+	return s.next.InviteAttendees(ctx, userID, eventID, attendeeIDs)
+}
+
+func (s *Storage) RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) (err error) { //nolint:lll
+	defer s.observe("RespondToInvitation", time.Now(), &err)
+	return s.next.RespondToInvitation(ctx, userID, eventID, status)
+}
+
+func (s *Storage) GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) (_ []models.Invitation, err error) { //nolint:lll
+	defer s.observe("GetInvitations", time.Now(), &err)
+	return s.next.GetInvitations(ctx, userID, status)
+}
@@ -0,0 +1,34 @@
+package models
+
+// AttendeeStatus is the response of an attendee to an invitation.
+type AttendeeStatus string
+
+const (
+	StatusNeedsAction AttendeeStatus = "needs-action"
+	StatusAccepted    AttendeeStatus = "accepted"
+	StatusDeclined    AttendeeStatus = "declined"
+	StatusTentative   AttendeeStatus = "tentative"
+)
+
+func (s AttendeeStatus) Valid() bool {
+	switch s {
+	case StatusNeedsAction, StatusAccepted, StatusDeclined, StatusTentative:
+		return true
+	default:
+		return false
+	}
+}
+
+// Attendee is a user invited to an event.
+type Attendee struct {
+	EventID string         `db:"event_id"`
+	UserID  int64          `db:"user_id"`
+	Status  AttendeeStatus `db:"status"`
+}
+
+// Invitation is an event the user is invited to together with the response
+// of the user.
+type Invitation struct {
+	Event
+	Status AttendeeStatus `db:"status"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// Calendar groups events that are shared with other users through its ACL.
+type Calendar struct {
+	ID       string `db:"id"`
+	OwnerID  int64  `db:"owner_id"`
+	Name     string `db:"name"`
+	Timezone string `db:"timezone"`
+
+	// Role is the role of the user the calendar was loaded for. It is not
+	// stored in the calendar itself.
+	Role Role `db:"role"`
+}
+
+// Role is the access level of a user to a calendar. Every role includes the
+// permissions of the lower ones.
+type Role string
+
+const (
+	// RoleReader can read the events of the calendar.
+	RoleReader Role = "reader"
+	// RoleWriter can also create, update and delete the events.
+	RoleWriter Role = "writer"
+	// RoleOwner can also change and delete the calendar and manage its ACL.
+	RoleOwner Role = "owner"
+)
+
+var roleRanks = map[Role]int{
+	RoleReader: 1,
+	RoleWriter: 2,
+	RoleOwner:  3,
+}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Includes reports whether the role grants the permissions of the other role.
+func (r Role) Includes(other Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[other]
+}
+
+// ACLEntry grants a user a role on a calendar.
+type ACLEntry struct {
+	CalendarID string `db:"calendar_id"`
+	UserID     int64  `db:"user_id"`
+	Role       Role   `db:"role"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ChangeType is the kind of change pushed to the watchers of events.
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// EventChange is a change of an event published to the change feed. A
+// restored event is published as created.
+type EventChange struct {
+	Type ChangeType
+	// Event is the event after the change, a deleted event is the event in
+	// the trash.
+	Event Event
+	// UserIDs are the users who can read the event and receive the change.
+	UserIDs   []int64
+	ChangedAt time.Time
+}
@@ -1,19 +1,131 @@
 package models
 
 import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
+// MaxEventDuration is the longest span of an event. A multi-day event is
+// indexed by every day, week and month it touches, so the span is bounded.
+const MaxEventDuration = 366 * 24 * time.Hour
+
+// ErrEventTooLong is returned for events that last longer than
+// MaxEventDuration.
+var ErrEventTooLong = errors.New("event must last at most 366 days")
+
+// CheckEventDuration returns ErrEventTooLong if the event from start to end is
+// longer than MaxEventDuration.
+func CheckEventDuration(start, end time.Time) error {
+	if end.Sub(start) > MaxEventDuration {
+		return ErrEventTooLong
+	}
+	return nil
+}
+
+// errLocalTimezone is returned for the Local time zone, which is the zone of
+// the server rather than an IANA one.
+var errLocalTimezone = errors.New("time zone Local is not an IANA name")
+
+// LoadTimezone returns the IANA time zone of the name, UTC if it is empty. The
+// Local time zone is rejected, so results do not depend on the server.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, errLocalTimezone
+	}
+	return time.LoadLocation(name)
+}
+
 type Event struct {
 	ID               string         `db:"id"`
 	Title            string         `db:"title"`
 	Description      *string        `db:"description"`
 	UserID           int64          `db:"user_id"`
+	CalendarID       *string        `db:"calendar_id"`
 	StartDate        time.Time      `db:"start_date"`
 	EndDate          time.Time      `db:"end_date"`
 	NotificationTime *time.Duration `db:"notification_time"`
+	RecurrenceRule   *string        `db:"recurrence_rule"`
+	ExceptionDates   Dates          `db:"exception_dates"`
+	Timezone         string         `db:"timezone"`
+	// AllDay events last whole days in their time zone. The end date of an
+	// all-day event is exclusive, as DTEND of an iCalendar all-day event.
+	AllDay *bool `db:"all_day"`
+	// Version starts at 1 and is incremented by every update. A non-zero
+	// Version of an update must match the stored one.
+	Version int64 `db:"version"`
+	// DeletedAt is set when the event is moved to the trash. Trashed events
+	// are hidden from every query until they are restored or purged.
+	DeletedAt *time.Time `db:"deleted_at"`
+
+	// AllowOverlap lets a tentative event be saved even if it overlaps other
+	// events of the user. It is a request option and is not stored.
+	AllowOverlap bool `db:"-"`
+
+	// Day, Week and Month are the buckets of the event start, LastDay, LastWeek
+	// and LastMonth are the buckets of its end. A multi-day event is listed in
+	// every bucket between them.
+	Day       time.Time `db:"day"`
+	Week      time.Time `db:"week"`
+	Month     time.Time `db:"month"`
+	LastDay   time.Time `db:"last_day"`
+	LastWeek  time.Time `db:"last_week"`
+	LastMonth time.Time `db:"last_month"`
+}
+
+func (e *Event) IsRecurring() bool {
+	return e.RecurrenceRule != nil && *e.RecurrenceRule != ""
+}
+
+func (e *Event) IsAllDay() bool {
+	return e.AllDay != nil && *e.AllDay
+}
+
+// Dates is a list of dates stored as a comma separated string.
+type Dates []time.Time
+
+func (d Dates) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	values := make([]string, len(d))
+	for i := range d {
+		values[i] = d[i].UTC().Format(time.RFC3339Nano)
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (d *Dates) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+		*d = nil
+		return nil
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for dates", src)
+	}
+
+	if value == "" {
+		*d = Dates{}
+		return nil
+	}
 
-	Day   time.Time `db:"day"`
-	Week  time.Time `db:"week"`
-	Month time.Time `db:"month"`
+	parts := strings.Split(value, ",")
+	dates := make(Dates, len(parts))
+	for i := range parts {
+		t, err := time.Parse(time.RFC3339Nano, parts[i])
+		if err != nil {
+			return err
+		}
+		dates[i] = t
+	}
+	*d = dates
+	return nil
 }
@@ -0,0 +1,18 @@
+package models
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// EventFilter narrows down and paginates event lists. A zero PageSize
+// returns all matching events.
+type EventFilter struct {
+	// Title selects events whose title contains the value, ignoring case.
+	Title string
+	// WithNotification selects events that have a notification time.
+	WithNotification bool
+
+	PageSize  int
+	PageToken string
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MaxFreeBusyUsers is the number of users one free/busy query may check.
+	MaxFreeBusyUsers = 50
+	// MaxFreeBusyRange is the longest interval of a free/busy query.
+	MaxFreeBusyRange = 92 * 24 * time.Hour
+	// MinSlotDuration is the shortest free slot.
+	MinSlotDuration = time.Minute
+	// MaxFreeSlots is the number of slots the interval of a free/busy query
+	// may be split into.
+	MaxFreeSlots = 10000
+)
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+type UserBusy struct {
+	UserID int64
+	Busy   []Interval
+}
+
+type FreeBusy struct {
+	Users     []UserBusy
+	FreeSlots []Interval
+}
+
+// CheckFreeBusyLimits reports whether a free/busy query stays within the
+// limits, so it can not make the server split a huge interval into tiny slots.
+// A zero slotDuration is not split.
+func CheckFreeBusyLimits(users int, from, to time.Time, slotDuration time.Duration) error {
+	if users > MaxFreeBusyUsers {
+		return fmt.Errorf("at most %d users can be queried", MaxFreeBusyUsers)
+	}
+	if to.Sub(from) > MaxFreeBusyRange {
+		return fmt.Errorf("interval must be at most %s", MaxFreeBusyRange)
+	}
+	if slotDuration == 0 {
+		return nil
+	}
+	if slotDuration < MinSlotDuration {
+		return fmt.Errorf("slot duration must be at least %s", MinSlotDuration)
+	}
+	if to.Sub(from)/slotDuration > MaxFreeSlots {
+		return errors.New("interval holds too many slots")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// HistoryAction is the kind of change recorded in the history of an event.
+type HistoryAction string
+
+const (
+	ActionCreate  HistoryAction = "create"
+	ActionUpdate  HistoryAction = "update"
+	ActionDelete  HistoryAction = "delete"
+	ActionRestore HistoryAction = "restore"
+	// ActionPurge is recorded when the event is deleted permanently: purged
+	// from the trash, cleaned up after it ended or deleted with its calendar.
+	ActionPurge HistoryAction = "purge"
+)
+
+// HistoryEntry is a change of an event. Before is nil for a created event;
+// the After snapshot of a deleted event is the event in the trash, After of a
+// purged event is nil.
+type HistoryEntry struct {
+	ID      int64         `db:"id"`
+	EventID string        `db:"event_id"`
+	Action  HistoryAction `db:"action"`
+	// ActorID is the user who made the change, 0 for the cleanup made by the
+	// scheduler.
+	ActorID int64 `db:"actor_id"`
+	// RequestID is the ID of the API request that made the change.
+	RequestID string    `db:"request_id"`
+	Before    *Event    `db:"-"`
+	After     *Event    `db:"-"`
+	ChangedAt time.Time `db:"changed_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+type Notification struct {
+	EventID string    `json:"eventId" db:"event_id"`
+	Title   string    `json:"title" db:"title"`
+	Date    time.Time `json:"date" db:"date"`
+	UserID  int64     `json:"userId" db:"user_id"`
+}
@@ -0,0 +1,163 @@
+package amqpqueue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	urlFormat    = "amqp://%s:%s@%s:%d/"
+	exchangeKind = "direct"
+	consumerName = "calendar_sender"
+)
+
+// Channel is the subset of *amqp.Channel used by the queue.
+type Channel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) //nolint:lll
+}
+
+type Queue struct {
+	ch         Channel
+	codec      queue.Codec
+	exchange   string
+	queue      string
+	routingKey string
+	prefetch   int
+}
+
+// New declares the exchange and the queue and binds them together.
+func New(ch Channel, codec queue.Codec, cfg *config.AMQPConfig) (*Queue, error) {
+	q := &Queue{
+		ch:         ch,
+		codec:      codec,
+		exchange:   cfg.Exchange,
+		queue:      cfg.Queue,
+		routingKey: cfg.RoutingKey,
+		prefetch:   cfg.Prefetch,
+	}
+
+	if err := q.declare(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *Queue) declare() error {
+	if err := q.ch.ExchangeDeclare(q.exchange, exchangeKind, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange: %w", err)
+	}
+	if _, err := q.ch.QueueDeclare(q.queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare queue: %w", err)
+	}
+	if err := q.ch.QueueBind(q.queue, q.routingKey, q.exchange, false, nil); err != nil {
+		return fmt.Errorf("bind queue: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) Publish(ctx context.Context, notification models.Notification) error {
+	body, err := q.codec.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	return q.ch.PublishWithContext(ctx, q.exchange, q.routingKey, false, false, amqp.Publishing{
+		ContentType:  q.codec.ContentType(),
+		DeliveryMode: amqp.Persistent,
+		MessageId:    notification.EventID,
+		Body:         body,
+	})
+}
+
+func (q *Queue) Consume(ctx context.Context) (<-chan queue.Delivery, error) {
+	if q.prefetch > 0 {
+		if err := q.ch.Qos(q.prefetch, 0, false); err != nil {
+			return nil, fmt.Errorf("set qos: %w", err)
+		}
+	}
+
+	messages, err := q.ch.Consume(q.queue, consumerName, false, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("consume queue: %w", err)
+	}
+
+	deliveries := make(chan queue.Delivery)
+
+	go func() {
+		defer close(deliveries)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				notification, err := q.codec.Unmarshal(msg.Body)
+				if err != nil {
+					// A message that can not be decoded will never be delivered, so drop it.
+					_ = msg.Reject(false)
+					continue
+				}
+
+				delivery := queue.NewDelivery(notification,
+					func() error { return msg.Ack(false) },
+					func(requeue bool) error { return msg.Nack(false, requeue) })
+
+				select {
+				case <-ctx.Done():
+					_ = msg.Nack(false, true)
+					return
+				case deliveries <- delivery:
+				}
+			}
+		}
+	}()
+
+	return deliveries, nil
+}
+
+type Connection struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// Dial opens a connection and a channel to the broker.
+func Dial(cfg *config.AMQPConfig) (*Connection, error) {
+	conn, err := amqp.Dial(fmt.Sprintf(urlFormat, cfg.Username, cfg.Password, cfg.Host, cfg.Port))
+	if err != nil {
+		return nil, fmt.Errorf("connect to broker: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("open channel: %w", err)
+	}
+
+	return &Connection{conn: conn, ch: ch}, nil
+}
+
+func (c *Connection) Channel() *amqp.Channel {
+	return c.ch
+}
+
+func (c *Connection) Close() error {
+	if err := c.ch.Close(); err != nil {
+		c.conn.Close()
+		return err
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,180 @@
+package amqpqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChannel routes published messages straight to its consumers.
+type fakeChannel struct {
+	mu        sync.Mutex
+	declared  []string
+	published []amqp.Publishing
+	messages  chan amqp.Delivery
+	acks      []uint64
+	nacks     []uint64
+	rejects   []uint64
+	declErr   error
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{messages: make(chan amqp.Delivery, 10)}
+}
+
+func (c *fakeChannel) ExchangeDeclare(name, _ string, _, _, _, _ bool, _ amqp.Table) error {
+	c.declared = append(c.declared, "exchange:"+name)
+	return c.declErr
+}
+
+func (c *fakeChannel) QueueDeclare(name string, _, _, _, _ bool, _ amqp.Table) (amqp.Queue, error) {
+	c.declared = append(c.declared, "queue:"+name)
+	return amqp.Queue{Name: name}, nil
+}
+
+func (c *fakeChannel) QueueBind(name, key, exchange string, _ bool, _ amqp.Table) error {
+	c.declared = append(c.declared, "bind:"+exchange+"/"+key+"/"+name)
+	return nil
+}
+
+func (c *fakeChannel) Qos(_, _ int, _ bool) error {
+	return nil
+}
+
+func (c *fakeChannel) PublishWithContext(_ context.Context, _, _ string, _, _ bool, msg amqp.Publishing) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.published = append(c.published, msg)
+	c.messages <- amqp.Delivery{
+		Acknowledger: c,
+		DeliveryTag:  uint64(len(c.published)),
+		ContentType:  msg.ContentType,
+		Body:         msg.Body,
+	}
+	return nil
+}
+
+func (c *fakeChannel) Consume(_, _ string, _, _, _, _ bool, _ amqp.Table) (<-chan amqp.Delivery, error) {
+	return c.messages, nil
+}
+
+func (c *fakeChannel) Ack(tag uint64, _ bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.acks = append(c.acks, tag)
+	return nil
+}
+
+func (c *fakeChannel) Nack(tag uint64, _, _ bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nacks = append(c.nacks, tag)
+	return nil
+}
+
+func (c *fakeChannel) Reject(tag uint64, _ bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rejects = append(c.rejects, tag)
+	return nil
+}
+
+var testConfig = config.AMQPConfig{
+	Exchange:   "calendar",
+	Queue:      "notifications",
+	RoutingKey: "notification",
+	Prefetch:   1,
+}
+
+func TestNew(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ch := newFakeChannel()
+
+		_, err := New(ch, queue.JSONCodec{}, &testConfig)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"exchange:calendar",
+			"queue:notifications",
+			"bind:calendar/notification/notifications",
+		}, ch.declared)
+	})
+
+	t.Run("declare error", func(t *testing.T) {
+		ch := newFakeChannel()
+		ch.declErr = errors.New("unexpected error")
+
+		_, err := New(ch, queue.JSONCodec{}, &testConfig)
+		require.Error(t, err)
+	})
+}
+
+func TestPublishConsume(t *testing.T) {
+	notification := models.Notification{
+		EventID: "id-1",
+		Title:   "test",
+		Date:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		UserID:  1,
+	}
+
+	for _, codec := range []queue.Codec{queue.JSONCodec{}, queue.ProtoCodec{}} {
+		codec := codec
+
+		t.Run(codec.ContentType(), func(t *testing.T) {
+			ch := newFakeChannel()
+
+			q, err := New(ch, codec, &testConfig)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			require.NoError(t, q.Publish(ctx, notification))
+			require.NoError(t, q.Publish(ctx, notification))
+			require.Equal(t, codec.ContentType(), ch.published[0].ContentType)
+
+			deliveries, err := q.Consume(ctx)
+			require.NoError(t, err)
+
+			delivery := <-deliveries
+			require.Equal(t, notification, delivery.Notification)
+			require.NoError(t, delivery.Ack())
+
+			delivery = <-deliveries
+			require.NoError(t, delivery.Nack(true))
+
+			ch.mu.Lock()
+			defer ch.mu.Unlock()
+			require.Equal(t, []uint64{1}, ch.acks)
+			require.Equal(t, []uint64{2}, ch.nacks)
+		})
+	}
+}
+
+func TestConsumeInvalidMessage(t *testing.T) {
+	ch := newFakeChannel()
+
+	q, err := New(ch, queue.JSONCodec{}, &testConfig)
+	require.NoError(t, err)
+
+	ch.messages <- amqp.Delivery{Acknowledger: ch, DeliveryTag: 1, Body: []byte("invalid")}
+	close(ch.messages)
+
+	deliveries, err := q.Consume(context.Background())
+	require.NoError(t, err)
+
+	_, ok := <-deliveries
+	require.False(t, ok)
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	require.Equal(t, []uint64{1}, ch.rejects)
+}
@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/queuepb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+// Codec converts notifications to and from their wire representation.
+type Codec interface {
+	ContentType() string
+	Marshal(models.Notification) ([]byte, error)
+	Unmarshal([]byte) (models.Notification, error)
+}
+
+func NewCodec(encoding string) (Codec, error) {
+	switch encoding {
+	case EncodingJSON:
+		return JSONCodec{}, nil
+	case EncodingProtobuf:
+		return ProtoCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+func (JSONCodec) Marshal(notification models.Notification) ([]byte, error) {
+	return json.Marshal(notification)
+}
+
+func (JSONCodec) Unmarshal(data []byte) (models.Notification, error) {
+	var notification models.Notification
+	err := json.Unmarshal(data, &notification)
+	return notification, err
+}
+
+type ProtoCodec struct{}
+
+func (ProtoCodec) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (ProtoCodec) Marshal(notification models.Notification) ([]byte, error) {
+	return proto.Marshal(&queuepb.Notification{
+		EventId: notification.EventID,
+		Title:   notification.Title,
+		Date:    timestamppb.New(notification.Date),
+		UserId:  notification.UserID,
+	})
+}
+
+func (ProtoCodec) Unmarshal(data []byte) (models.Notification, error) {
+	var pb queuepb.Notification
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return models.Notification{}, err
+	}
+
+	return models.Notification{
+		EventID: pb.GetEventId(),
+		Title:   pb.GetTitle(),
+		Date:    pb.GetDate().AsTime(),
+		UserID:  pb.GetUserId(),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec(t *testing.T) {
+	notification := models.Notification{
+		EventID: "id-1",
+		Title:   "test",
+		Date:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		UserID:  1,
+	}
+
+	for _, encoding := range []string{EncodingJSON, EncodingProtobuf} {
+		encoding := encoding
+
+		t.Run(encoding, func(t *testing.T) {
+			codec, err := NewCodec(encoding)
+			require.NoError(t, err)
+
+			data, err := codec.Marshal(notification)
+			require.NoError(t, err)
+
+			got, err := codec.Unmarshal(data)
+			require.NoError(t, err)
+			require.Equal(t, notification, got)
+		})
+	}
+
+	t.Run("unknown encoding", func(t *testing.T) {
+		_, err := NewCodec("xml")
+		require.Error(t, err)
+	})
+}
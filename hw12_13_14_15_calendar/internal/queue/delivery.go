@@ -0,0 +1,39 @@
+package queue
+
+import (
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+type AckFn func() error
+
+type NackFn func(requeue bool) error
+
+// Delivery is a notification received from a queue that must be acknowledged
+// after it has been processed.
+type Delivery struct {
+	Notification models.Notification
+	ack          AckFn
+	nack         NackFn
+}
+
+func NewDelivery(notification models.Notification, ack AckFn, nack NackFn) Delivery {
+	return Delivery{
+		Notification: notification,
+		ack:          ack,
+		nack:         nack,
+	}
+}
+
+func (d Delivery) Ack() error {
+	if d.ack == nil {
+		return nil
+	}
+	return d.ack()
+}
+
+func (d Delivery) Nack(requeue bool) error {
+	if d.nack == nil {
+		return nil
+	}
+	return d.nack(requeue)
+}
@@ -0,0 +1,74 @@
+package memoryqueue
+
+import (
+	"context"
+	"errors"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+)
+
+var ErrQueueFull = errors.New("queue is full")
+
+// Queue is an in-process broker of notifications. It implements both
+// queue.Publisher and queue.Consumer.
+type Queue struct {
+	messages chan models.Notification
+}
+
+func New(size int) *Queue {
+	return &Queue{
+		messages: make(chan models.Notification, size),
+	}
+}
+
+func (q *Queue) Publish(ctx context.Context, notification models.Notification) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case q.messages <- notification:
+		return nil
+	}
+}
+
+func (q *Queue) Consume(ctx context.Context) (<-chan queue.Delivery, error) {
+	deliveries := make(chan queue.Delivery)
+
+	go func() {
+		defer close(deliveries)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification := <-q.messages:
+				select {
+				case <-ctx.Done():
+					_ = q.requeue(notification)
+					return
+				case deliveries <- queue.NewDelivery(notification, nil, q.nackFn(notification)):
+				}
+			}
+		}
+	}()
+
+	return deliveries, nil
+}
+
+func (q *Queue) nackFn(notification models.Notification) queue.NackFn {
+	return func(requeue bool) error {
+		if !requeue {
+			return nil
+		}
+		return q.requeue(notification)
+	}
+}
+
+func (q *Queue) requeue(notification models.Notification) error {
+	select {
+	case q.messages <- notification:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
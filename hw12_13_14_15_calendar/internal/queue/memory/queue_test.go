@@ -0,0 +1,67 @@
+package memoryqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	notification := models.Notification{
+		EventID: "id-1",
+		Title:   "test",
+		Date:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		UserID:  1,
+	}
+
+	t.Run("publish and consume", func(t *testing.T) {
+		q := New(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		require.NoError(t, q.Publish(ctx, notification))
+
+		deliveries, err := q.Consume(ctx)
+		require.NoError(t, err)
+
+		delivery := <-deliveries
+		require.Equal(t, notification, delivery.Notification)
+		require.NoError(t, delivery.Ack())
+
+		cancel()
+		_, ok := <-deliveries
+		require.False(t, ok)
+	})
+
+	t.Run("requeue on nack", func(t *testing.T) {
+		q := New(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		require.NoError(t, q.Publish(ctx, notification))
+
+		deliveries, err := q.Consume(ctx)
+		require.NoError(t, err)
+
+		delivery := <-deliveries
+		require.NoError(t, delivery.Nack(true))
+
+		delivery = <-deliveries
+		require.Equal(t, notification, delivery.Notification)
+	})
+
+	t.Run("publish to full queue", func(t *testing.T) {
+		q := New(1)
+		require.NoError(t, q.Publish(context.Background(), notification))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, q.Publish(ctx, notification), context.DeadlineExceeded)
+	})
+}
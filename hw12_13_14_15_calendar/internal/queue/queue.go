@@ -0,0 +1,20 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+const (
+	InMemory = "in-memory"
+	AMQP     = "amqp"
+)
+
+type Publisher interface {
+	Publish(context.Context, models.Notification) error
+}
+
+type Consumer interface {
+	Consume(context.Context) (<-chan Delivery, error)
+}
@@ -0,0 +1,376 @@
+// Package rrule implements a subset of RFC 5545 recurrence rules:
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum is a BYDAY value. A non-zero N selects the N-th weekday of the
+// month, counting from the end when negative.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+}
+
+const untilFormat = "20060102T150405Z"
+
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("rule is empty")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		if err := rule.set(strings.ToUpper(name), strings.ToUpper(val)); err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if rule.Count != 0 && !rule.Until.IsZero() {
+		return nil, errors.New("COUNT and UNTIL must not occur together")
+	}
+	return rule, nil
+}
+
+func (r *Rule) set(name, value string) error {
+	var err error
+
+	switch name {
+	case "FREQ":
+		switch Frequency(value) {
+		case Daily, Weekly, Monthly, Yearly:
+			r.Freq = Frequency(value)
+		default:
+			return fmt.Errorf("unsupported FREQ %q", value)
+		}
+	case "INTERVAL":
+		r.Interval, err = parsePositive(name, value)
+	case "COUNT":
+		r.Count, err = parsePositive(name, value)
+	case "UNTIL":
+		r.Until, err = parseUntil(value)
+	case "BYDAY":
+		r.ByDay, err = parseByDay(value)
+	case "BYMONTHDAY":
+		r.ByMonthDay, err = parseByMonthDay(value)
+	default:
+		return fmt.Errorf("unsupported rule part %q", name)
+	}
+	return err
+}
+
+func parsePositive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse(untilFormat, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseByDay(value string) ([]WeekdayNum, error) {
+	parts := strings.Split(value, ",")
+	days := make([]WeekdayNum, 0, len(parts))
+
+	for _, part := range parts {
+		if len(part) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", part)
+		}
+
+		weekday, ok := weekdays[part[len(part)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", part)
+		}
+
+		var n int
+		if prefix := part[:len(part)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %q", part)
+			}
+		}
+		days = append(days, WeekdayNum{Weekday: weekday, N: n})
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	parts := strings.Split(value, ",")
+	days := make([]int, 0, len(parts))
+
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n == 0 || n < -31 || n > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q", part)
+		}
+		days = append(days, n)
+	}
+	return days, nil
+}
+
+// Between returns the start times of occurrences in the [from, to) interval.
+// The first occurrence is always dtstart. Exception dates are skipped but still
+// count towards COUNT.
+func (r *Rule) Between(dtstart, from, to time.Time, exdates []time.Time) []time.Time {
+	var occurrences []time.Time
+
+	r.iterate(dtstart, to, func(t time.Time) {
+		if t.Before(from) || isException(t, exdates) {
+			return
+		}
+		occurrences = append(occurrences, t)
+	})
+	return occurrences
+}
+
+var maxTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// Last returns the start time of the last occurrence. It returns false when
+// the rule has neither COUNT nor UNTIL and recurs forever.
+func (r *Rule) Last(dtstart time.Time) (time.Time, bool) {
+	if r.Count == 0 && r.Until.IsZero() {
+		return time.Time{}, false
+	}
+
+	var last time.Time
+	r.iterate(dtstart, maxTime, func(t time.Time) { last = t })
+	return last, true
+}
+
+func isException(t time.Time, exdates []time.Time) bool {
+	for _, exdate := range exdates {
+		if t.Equal(exdate) {
+			return true
+		}
+	}
+	return false
+}
+
+// iterate calls fn for every occurrence that starts before the limit.
+func (r *Rule) iterate(dtstart, limit time.Time, fn func(time.Time)) {
+	var count int
+	emit := func(t time.Time) bool {
+		if !t.Before(limit) || (!r.Until.IsZero() && t.After(r.Until)) {
+			return false
+		}
+		if r.Count != 0 && count == r.Count {
+			return false
+		}
+		count++
+		fn(t)
+		return true
+	}
+
+	if !emit(dtstart) {
+		return
+	}
+
+	for period := 0; ; period++ {
+		periodStart := r.periodStart(dtstart, period)
+		if !periodStart.Before(limit) || (!r.Until.IsZero() && periodStart.After(r.Until)) {
+			return
+		}
+
+		for _, t := range r.candidates(dtstart, periodStart) {
+			if !t.After(dtstart) {
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+// periodStart returns the beginning of the n-th period counted from dtstart.
+func (r *Rule) periodStart(dtstart time.Time, n int) time.Time {
+	year, month, day := dtstart.Date()
+	step := n * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		return date(year, month, day+step, dtstart.Location())
+	case Weekly:
+		monday := day - (int(dtstart.Weekday())+6)%7
+		return date(year, month, monday+7*step, dtstart.Location())
+	case Monthly:
+		return date(year, month+time.Month(step), 1, dtstart.Location())
+	default:
+		return date(year+step, 1, 1, dtstart.Location())
+	}
+}
+
+// candidates returns the sorted occurrence times inside a period.
+func (r *Rule) candidates(dtstart, periodStart time.Time) []time.Time {
+	var days []time.Time
+
+	switch r.Freq {
+	case Daily:
+		if r.matchesDay(periodStart) {
+			days = append(days, periodStart)
+		}
+	case Weekly:
+		for i := 0; i < 7; i++ {
+			day := periodStart.AddDate(0, 0, i)
+			if r.matchesWeekday(day, dtstart) && r.matchesMonthDay(day) {
+				days = append(days, day)
+			}
+		}
+	case Monthly:
+		days = r.monthDays(periodStart, dtstart)
+	case Yearly:
+		days = r.monthDays(date(periodStart.Year(), dtstart.Month(), 1, dtstart.Location()), dtstart)
+	}
+
+	hour, minute, sec := dtstart.Clock()
+	result := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		result = append(result, time.Date(day.Year(), day.Month(), day.Day(),
+			hour, minute, sec, dtstart.Nanosecond(), dtstart.Location()))
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+// monthDays returns the days of the month that match the rule. Without BYDAY
+// and BYMONTHDAY the day of dtstart is used.
+func (r *Rule) monthDays(monthStart, dtstart time.Time) []time.Time {
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		day := date(monthStart.Year(), monthStart.Month(), dtstart.Day(), dtstart.Location())
+		if day.Month() != monthStart.Month() {
+			return nil
+		}
+		return []time.Time{day}
+	}
+
+	var days []time.Time
+	for day := monthStart; day.Month() == monthStart.Month(); day = day.AddDate(0, 0, 1) {
+		if r.matchesMonthDay(day) && r.matchesMonthWeekday(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r *Rule) matchesDay(day time.Time) bool {
+	if len(r.ByDay) != 0 {
+		found := false
+		for _, wd := range r.ByDay {
+			if wd.Weekday == day.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return r.matchesMonthDay(day)
+}
+
+func (r *Rule) matchesWeekday(day, dtstart time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return day.Weekday() == dtstart.Weekday()
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthWeekday(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	daysInMonth := date(day.Year(), day.Month()+1, 0, day.Location()).Day()
+	for _, wd := range r.ByDay {
+		if wd.Weekday != day.Weekday() {
+			continue
+		}
+
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (day.Day()-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (daysInMonth-day.Day())/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(day time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	daysInMonth := date(day.Year(), day.Month()+1, 0, day.Location()).Day()
+	for _, md := range r.ByMonthDay {
+		if md == day.Day() || (md < 0 && daysInMonth+md+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func date(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,194 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		description string
+		value       string
+		want        *Rule
+		wantErr     bool
+	}{
+		{
+			description: "weekly by day",
+			value:       "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			want: &Rule{
+				Freq:     Weekly,
+				Interval: 2,
+				ByDay:    []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}},
+			},
+		},
+		{
+			description: "monthly with prefix and until",
+			value:       "RRULE:FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20231231T000000Z",
+			want: &Rule{
+				Freq:     Monthly,
+				Interval: 1,
+				Until:    time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+				ByDay:    []WeekdayNum{{Weekday: time.Friday, N: -1}},
+			},
+		},
+		{
+			description: "by month day and count",
+			value:       "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=3",
+			want: &Rule{
+				Freq:       Monthly,
+				Interval:   1,
+				Count:      3,
+				ByMonthDay: []int{1, -1},
+			},
+		},
+		{description: "empty rule", value: "", wantErr: true},
+		{description: "missing freq", value: "INTERVAL=2", wantErr: true},
+		{description: "unsupported freq", value: "FREQ=HOURLY", wantErr: true},
+		{description: "unsupported part", value: "FREQ=DAILY;BYHOUR=10", wantErr: true},
+		{description: "invalid interval", value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{description: "invalid by day", value: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{description: "invalid by month day", value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{description: "count with until", value: "FREQ=DAILY;COUNT=2;UNTIL=20231231", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := Parse(tt.value)
+
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	// Wednesday.
+	dtstart := time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC)
+
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(2023, month, day, 10, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		description string
+		rule        string
+		from        time.Time
+		to          time.Time
+		exdates     []time.Time
+		want        []time.Time
+	}{
+		{
+			description: "daily with count",
+			rule:        "FREQ=DAILY;COUNT=3",
+			from:        at(8, 1),
+			to:          at(9, 1),
+			want:        []time.Time{at(8, 16), at(8, 17), at(8, 18)},
+		},
+		{
+			description: "daily with interval inside window",
+			rule:        "FREQ=DAILY;INTERVAL=2",
+			from:        time.Date(2023, 8, 20, 0, 0, 0, 0, time.UTC),
+			to:          time.Date(2023, 8, 25, 0, 0, 0, 0, time.UTC),
+			want:        []time.Time{at(8, 20), at(8, 22), at(8, 24)},
+		},
+		{
+			description: "weekly by day",
+			rule:        "FREQ=WEEKLY;BYDAY=MO,WE",
+			from:        at(8, 1),
+			to:          time.Date(2023, 8, 29, 0, 0, 0, 0, time.UTC),
+			want:        []time.Time{at(8, 16), at(8, 21), at(8, 23), at(8, 28)},
+		},
+		{
+			description: "biweekly",
+			rule:        "FREQ=WEEKLY;INTERVAL=2",
+			from:        at(8, 1),
+			to:          at(9, 15),
+			want:        []time.Time{at(8, 16), at(8, 30), at(9, 13)},
+		},
+		{
+			description: "weekly with until and exception",
+			rule:        "FREQ=WEEKLY;UNTIL=20230906T100000Z",
+			from:        at(8, 1),
+			to:          at(12, 1),
+			exdates:     []time.Time{at(8, 23)},
+			want:        []time.Time{at(8, 16), at(8, 30), at(9, 6)},
+		},
+		{
+			description: "monthly by month day",
+			rule:        "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			from:        at(1, 1),
+			to:          at(12, 31),
+			want:        []time.Time{at(8, 16), at(8, 31), at(9, 30)},
+		},
+		{
+			description: "monthly last friday",
+			rule:        "FREQ=MONTHLY;BYDAY=-1FR",
+			from:        at(9, 1),
+			to:          at(11, 1),
+			want:        []time.Time{at(9, 29), at(10, 27)},
+		},
+		{
+			description: "yearly",
+			rule:        "FREQ=YEARLY;COUNT=2",
+			from:        at(1, 1),
+			to:          time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:        []time.Time{at(8, 16), time.Date(2024, 8, 16, 10, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			got := rule.Between(dtstart, tt.from, tt.to, tt.exdates)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLast(t *testing.T) {
+	dtstart := time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description string
+		rule        string
+		want        time.Time
+		wantOK      bool
+	}{
+		{
+			description: "count",
+			rule:        "FREQ=WEEKLY;COUNT=3",
+			want:        time.Date(2023, 8, 30, 10, 0, 0, 0, time.UTC),
+			wantOK:      true,
+		},
+		{
+			description: "until",
+			rule:        "FREQ=DAILY;UNTIL=20230820T000000Z",
+			want:        time.Date(2023, 8, 19, 10, 0, 0, 0, time.UTC),
+			wantOK:      true,
+		},
+		{
+			description: "infinite",
+			rule:        "FREQ=DAILY",
+			wantOK:      false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			require.NoError(t, err)
+
+			got, ok := rule.Last(dtstart)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
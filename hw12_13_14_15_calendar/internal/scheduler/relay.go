@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+)
+
+type OutboxStorage interface {
+	ProcessOutbox(ctx context.Context, limit int, fn func(context.Context, models.Notification) error) (int, error)
+}
+
+// Relay moves notifications from the outbox to the queue.
+type Relay struct {
+	db        OutboxStorage
+	publisher queue.Publisher
+	batchSize int
+}
+
+func NewRelay(db OutboxStorage, publisher queue.Publisher, batchSize int) *Relay {
+	return &Relay{
+		db:        db,
+		publisher: publisher,
+		batchSize: batchSize,
+	}
+}
+
+// Drain publishes unsent notifications batch by batch until the outbox is empty.
+func (r *Relay) Drain(ctx context.Context) error {
+	for {
+		processed, err := r.db.ProcessOutbox(ctx, r.batchSize, r.publisher.Publish)
+		if err != nil {
+			return fmt.Errorf("relay notifications: %w", err)
+		}
+		if processed < r.batchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	"golang.org/x/exp/slog"
+)
+
+type Storage interface {
+	OutboxStorage
+	EnqueueNotifications(ctx context.Context, from, to time.Time) (int64, error)
+	DeleteEventsBefore(context.Context, time.Time) (int64, error)
+	PurgeDeletedEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+type Scheduler struct {
+	db    Storage
+	relay *Relay
+	log   logger.ILogger
+
+	interval             time.Duration
+	eventsMaxAge         time.Duration
+	notificationLookback time.Duration
+	trashRetention       time.Duration
+}
+
+func New(log logger.ILogger, db Storage, publisher queue.Publisher, cfg *config.SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		db:                   db,
+		relay:                NewRelay(db, publisher, cfg.OutboxBatchSize),
+		log:                  log,
+		interval:             cfg.Interval,
+		eventsMaxAge:         cfg.EventsMaxAge,
+		notificationLookback: cfg.NotificationLookback,
+		trashRetention:       cfg.TrashRetention,
+	}
+}
+
+// Run processes events every interval until the context is canceled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.tick(ctx, time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(ctx, now)
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	if err := s.enqueue(ctx, now); err != nil {
+		s.log.Error("Enqueue notifications", "error", err)
+	}
+	if err := s.relay.Drain(ctx); err != nil {
+		s.log.Error("Send notifications", "error", err)
+	}
+	if err := s.cleanup(ctx, now); err != nil {
+		s.log.Error("Delete old events", "error", err)
+	}
+}
+
+// enqueue looks back further than one interval, so notifications missed while
+// the scheduler was down are still sent. The outbox drops the ones that were
+// already enqueued.
+func (s *Scheduler) enqueue(ctx context.Context, now time.Time) error {
+	enqueued, err := s.db.EnqueueNotifications(ctx, now.Add(-s.notificationLookback), now)
+	if err != nil {
+		return fmt.Errorf("enqueue notifications: %w", err)
+	}
+
+	s.log.Debug("Notifications enqueued", slog.Int64("count", enqueued))
+	return nil
+}
+
+// cleanup deletes the old events and purges the events that have been in the
+// trash longer than the retention period.
+func (s *Scheduler) cleanup(ctx context.Context, now time.Time) error {
+	deleted, err := s.db.DeleteEventsBefore(ctx, now.Add(-s.eventsMaxAge))
+	if err != nil {
+		return err
+	}
+	s.log.Debug("Old events deleted", slog.Int64("count", deleted))
+
+	purged, err := s.db.PurgeDeletedEvents(ctx, now.Add(-s.trashRetention))
+	if err != nil {
+		return fmt.Errorf("purge deleted events: %w", err)
+	}
+	s.log.Debug("Deleted events purged", slog.Int64("count", purged))
+	return nil
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	memorystorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+type publisherStub struct {
+	notifications []models.Notification
+	err           error
+}
+
+func (p *publisherStub) Publish(_ context.Context, notification models.Notification) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.notifications = append(p.notifications, notification)
+	return nil
+}
+
+func newEvent(id string, start time.Time, notificationTime *time.Duration) *models.Event {
+	return &models.Event{
+		ID:               id,
+		Title:            "title " + id,
+		UserID:           1,
+		StartDate:        start,
+		EndDate:          start.Add(time.Hour),
+		NotificationTime: notificationTime,
+		AllowOverlap:     true,
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+var testConfig = &config.SchedulerConfig{
+	Interval:             time.Minute,
+	EventsMaxAge:         8760 * time.Hour,
+	NotificationLookback: time.Hour,
+	OutboxBatchSize:      1,
+	TrashRetention:       720 * time.Hour,
+}
+
+func TestTick(t *testing.T) {
+	now := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	newStorage := func(t *testing.T) *memorystorage.Storage {
+		t.Helper()
+
+		db := memorystorage.New()
+		events := []*models.Event{
+			newEvent("1", now.Add(30*time.Minute), durationPtr(30*time.Minute)),
+			newEvent("2", now.Add(time.Hour), durationPtr(30*time.Minute)),
+			newEvent("3", now.Add(30*time.Second), nil),
+			newEvent("4", now.Add(-10*time.Minute), durationPtr(20*time.Minute)),
+			newEvent("5", now.Add(-3*time.Hour), durationPtr(20*time.Minute)),
+		}
+		for _, event := range events {
+			require.NoError(t, db.CreateEvent(context.Background(), event))
+		}
+		return db
+	}
+
+	want := []models.Notification{
+		{EventID: "4", Title: "title 4", Date: now.Add(-10 * time.Minute), UserID: 1},
+		{EventID: "1", Title: "title 1", Date: now.Add(30 * time.Minute), UserID: 1},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		publisher := &publisherStub{}
+		s := New(logger.NewMock(), newStorage(t), publisher, testConfig)
+
+		s.tick(context.Background(), now)
+		s.tick(context.Background(), now.Add(time.Minute))
+
+		require.ElementsMatch(t, want, publisher.notifications)
+	})
+
+	t.Run("publish error", func(t *testing.T) {
+		db := newStorage(t)
+		publisher := &publisherStub{err: errors.New("unexpected error")}
+		s := New(logger.NewMock(), db, publisher, testConfig)
+
+		s.tick(context.Background(), now)
+		require.Empty(t, publisher.notifications)
+
+		publisher.err = nil
+		s.tick(context.Background(), now.Add(time.Minute))
+		require.ElementsMatch(t, want, publisher.notifications)
+	})
+}
+
+func TestCleanup(t *testing.T) {
+	now := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	db := memorystorage.New()
+	require.NoError(t, db.CreateEvent(context.Background(), newEvent("old", now.AddDate(-2, 0, 0), nil)))
+	require.NoError(t, db.CreateEvent(context.Background(), newEvent("new", now.AddDate(0, -1, 0), nil)))
+
+	s := New(logger.NewMock(), db, &publisherStub{}, testConfig)
+	require.NoError(t, s.cleanup(context.Background(), now))
+
+	events, _, err := db.GetEventByMonth(context.Background(), 1,
+		time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), models.EventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	events, _, err = db.GetEventByMonth(context.Background(), 1,
+		time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), models.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestPurge(t *testing.T) {
+	now := time.Now()
+
+	db := memorystorage.New()
+	require.NoError(t, db.CreateEvent(context.Background(), newEvent("deleted", now.AddDate(0, 1, 0), nil)))
+	require.NoError(t, db.DeleteEvent(context.Background(), 1, "deleted", 0))
+
+	s := New(logger.NewMock(), db, &publisherStub{}, testConfig)
+
+	require.NoError(t, s.cleanup(context.Background(), now))
+	events, err := db.GetDeletedEvents(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	require.NoError(t, s.cleanup(context.Background(), now.Add(testConfig.TrashRetention+time.Minute)))
+	events, err = db.GetDeletedEvents(context.Background(), 1)
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
@@ -0,0 +1,256 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	"golang.org/x/exp/slog"
+)
+
+// ErrPermanent marks a failure of a sink that a retry can not fix. Such a sink
+// is not retried.
+var ErrPermanent = errors.New("permanent failure")
+
+var errNotDelivered = errors.New("every sink failed permanently")
+
+type Sink interface {
+	Send(context.Context, models.Notification) error
+}
+
+type Sender struct {
+	consumer queue.Consumer
+	sinks    []Sink
+	log      logger.ILogger
+
+	maxRetries      int
+	retryDelay      time.Duration
+	maxRetryDelay   time.Duration
+	maxRedeliveries int
+	redeliveryTTL   time.Duration
+	maxTracked      int
+	now             func() time.Time
+
+	// redelivered holds the notifications requeued after a failure, so the
+	// sinks that are done with them are skipped on a redelivery. It is kept in
+	// memory only: the notifications not redelivered within redeliveryTTL are
+	// forgotten, at most maxTracked of them are kept, and a restart forgets
+	// all of them. Their next redelivery is sent to every sink again.
+	redelivered map[notificationKey]*deliveryState
+}
+
+type notificationKey struct {
+	eventID string
+	userID  int64
+	date    int64
+}
+
+type deliveryState struct {
+	// sent holds the indexes of the sinks that sent the notification.
+	sent map[int]struct{}
+	// rejected holds the indexes of the sinks that failed permanently.
+	rejected     map[int]struct{}
+	redeliveries int
+	requeuedAt   time.Time
+}
+
+func newDeliveryState() *deliveryState {
+	return &deliveryState{
+		sent:     make(map[int]struct{}),
+		rejected: make(map[int]struct{}),
+	}
+}
+
+// done reports whether the sink sent the notification or failed permanently.
+func (d *deliveryState) done(sink int) bool {
+	_, sent := d.sent[sink]
+	_, rejected := d.rejected[sink]
+	return sent || rejected
+}
+
+func New(log logger.ILogger, consumer queue.Consumer, sinks []Sink, cfg *config.SenderConfig) *Sender {
+	return &Sender{
+		consumer:        consumer,
+		sinks:           sinks,
+		log:             log,
+		maxRetries:      cfg.MaxRetries,
+		retryDelay:      cfg.RetryDelay,
+		maxRetryDelay:   cfg.MaxRetryDelay,
+		maxRedeliveries: cfg.MaxRedeliveries,
+		redeliveryTTL:   cfg.RedeliveryTTL,
+		maxTracked:      cfg.MaxTrackedRedeliveries,
+		now:             time.Now,
+		redelivered:     make(map[notificationKey]*deliveryState),
+	}
+}
+
+// Run delivers notifications from the consumer until the context is canceled
+// or the consumer is closed.
+func (s *Sender) Run(ctx context.Context) error {
+	deliveries, err := s.consumer.Consume(ctx)
+	if err != nil {
+		return fmt.Errorf("consume notifications: %w", err)
+	}
+
+	for delivery := range deliveries {
+		s.handle(ctx, delivery)
+	}
+	return nil
+}
+
+// handle sends the notification to the sinks that are not done with it yet.
+// A failed notification is requeued up to maxRedeliveries times and then
+// rejected without a requeue, so it goes to the dead letter exchange of the
+// queue if there is one. A notification that every sink rejected permanently
+// is rejected without a requeue at once.
+func (s *Sender) handle(ctx context.Context, delivery queue.Delivery) {
+	log := s.log.With(slog.String("event_id", delivery.Notification.EventID))
+
+	key := notificationKey{
+		eventID: delivery.Notification.EventID,
+		userID:  delivery.Notification.UserID,
+		date:    delivery.Notification.Date.UnixNano(),
+	}
+	state, ok := s.redelivered[key]
+	if !ok || s.expired(state) {
+		state = newDeliveryState()
+	}
+
+	err := s.send(ctx, delivery.Notification, state)
+	if err == nil && len(state.sent) == 0 {
+		delete(s.redelivered, key)
+		log.Error("Drop notification", "error", errNotDelivered)
+		if err := delivery.Nack(false); err != nil {
+			log.Error("Nack notification", "error", err)
+		}
+		return
+	}
+	if err == nil {
+		delete(s.redelivered, key)
+		if err := delivery.Ack(); err != nil {
+			log.Error("Ack notification", "error", err)
+		}
+		return
+	}
+
+	log.Error("Send notification", "error", err)
+
+	requeue := ctx.Err() != nil || state.redeliveries < s.maxRedeliveries
+	if requeue {
+		if ctx.Err() == nil {
+			state.redeliveries++
+		}
+		s.track(key, state)
+	} else {
+		delete(s.redelivered, key)
+		log.Error("Drop notification", "redeliveries", state.redeliveries)
+	}
+
+	if err := delivery.Nack(requeue); err != nil {
+		log.Error("Nack notification", "error", err)
+	}
+}
+
+// track remembers the state of the requeued notification. The expired states
+// are dropped when maxTracked states are kept, and then the oldest one if
+// there are still too many.
+func (s *Sender) track(key notificationKey, state *deliveryState) {
+	state.requeuedAt = s.now()
+	delete(s.redelivered, key)
+
+	if len(s.redelivered) >= s.maxTracked {
+		var (
+			oldestKey notificationKey
+			oldest    *deliveryState
+		)
+		for key, tracked := range s.redelivered {
+			if s.expired(tracked) {
+				delete(s.redelivered, key)
+				continue
+			}
+			if oldest == nil || tracked.requeuedAt.Before(oldest.requeuedAt) {
+				oldestKey, oldest = key, tracked
+			}
+		}
+		if len(s.redelivered) >= s.maxTracked {
+			delete(s.redelivered, oldestKey)
+		}
+	}
+
+	s.redelivered[key] = state
+}
+
+func (s *Sender) expired(state *deliveryState) bool {
+	return s.now().Sub(state.requeuedAt) >= s.redeliveryTTL
+}
+
+// send delivers the notification to every sink that is not done, retrying
+// failed sinks with exponential backoff. The sinks that sent the notification
+// or failed permanently are added to the state.
+func (s *Sender) send(ctx context.Context, notification models.Notification, state *deliveryState) error {
+	delay := s.retryDelay
+
+	for attempt := 0; ; attempt++ {
+		var lastErr error
+		failed := 0
+
+		for i, sink := range s.sinks {
+			if state.done(i) {
+				continue
+			}
+
+			err := sink.Send(ctx, notification)
+			switch {
+			case err == nil:
+				state.sent[i] = struct{}{}
+			case errors.Is(err, ErrPermanent):
+				s.log.Error("Sink failed permanently",
+					slog.String("event_id", notification.EventID),
+					slog.Any("error", err))
+				state.rejected[i] = struct{}{}
+			default:
+				lastErr = err
+				failed++
+			}
+		}
+
+		if failed == 0 {
+			return nil
+		}
+		if attempt == s.maxRetries {
+			return fmt.Errorf("%d of %d sinks failed: %w", failed, len(s.sinks), lastErr)
+		}
+
+		s.log.Warn("Retry notification",
+			slog.String("event_id", notification.EventID),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.Any("error", lastErr))
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+
+		delay *= 2
+		if delay > s.maxRetryDelay {
+			delay = s.maxRetryDelay
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,275 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue"
+	memoryqueue "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/queue/memory"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkStub struct {
+	mu            sync.Mutex
+	failures      int
+	calls         int
+	notifications []models.Notification
+}
+
+func (s *sinkStub) Send(_ context.Context, notification models.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("unexpected error")
+	}
+	s.notifications = append(s.notifications, notification)
+	return nil
+}
+
+func (s *sinkStub) sent() []models.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notifications
+}
+
+func TestRun(t *testing.T) {
+	cfg := &config.SenderConfig{
+		MaxRetries:    3,
+		RetryDelay:    time.Millisecond,
+		MaxRetryDelay: 2 * time.Millisecond,
+	}
+
+	notification := models.Notification{
+		EventID: "id-1",
+		Title:   "test",
+		Date:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		UserID:  1,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		q := memoryqueue.New(1)
+		stableSink := &sinkStub{}
+		flakySink := &sinkStub{failures: 2}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		s := New(logger.NewMock(), q, []Sink{stableSink, flakySink}, cfg)
+
+		done := make(chan error)
+		go func() { done <- s.Run(ctx) }()
+
+		require.NoError(t, q.Publish(ctx, notification))
+		require.Eventually(t, func() bool {
+			return len(flakySink.sent()) == 1
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		require.NoError(t, <-done)
+
+		require.Equal(t, []models.Notification{notification}, stableSink.sent())
+		require.Equal(t, []models.Notification{notification}, flakySink.sent())
+		require.Equal(t, 3, flakySink.calls)
+	})
+
+	t.Run("retries exceeded", func(t *testing.T) {
+		sink := &sinkStub{failures: 10}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{sink}, cfg)
+
+		err := s.send(context.Background(), notification, newDeliveryState())
+		require.Error(t, err)
+		require.Equal(t, cfg.MaxRetries+1, sink.calls)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		sink := &sinkStub{failures: 10}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{sink}, cfg)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := s.send(ctx, notification, newDeliveryState())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("permanent failure is not retried", func(t *testing.T) {
+		permanentSink := &permanentSinkStub{}
+		sink := &sinkStub{}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{permanentSink, sink}, cfg)
+
+		require.NoError(t, s.send(context.Background(), notification, newDeliveryState()))
+		require.Equal(t, 1, permanentSink.calls)
+		require.Equal(t, []models.Notification{notification}, sink.sent())
+	})
+}
+
+type permanentSinkStub struct {
+	calls int
+}
+
+func (s *permanentSinkStub) Send(context.Context, models.Notification) error {
+	s.calls++
+	return ErrPermanent
+}
+
+func TestHandle(t *testing.T) {
+	cfg := &config.SenderConfig{
+		MaxRetries:             0,
+		RetryDelay:             time.Millisecond,
+		MaxRetryDelay:          time.Millisecond,
+		MaxRedeliveries:        2,
+		RedeliveryTTL:          time.Minute,
+		MaxTrackedRedeliveries: 2,
+	}
+
+	notification := models.Notification{
+		EventID: "id-1",
+		Title:   "test",
+		Date:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		UserID:  1,
+	}
+
+	// deliver hands the notification to the sender and returns whether it was
+	// acked and, if not, whether it was requeued.
+	deliver := func(s *Sender) (acked, requeued bool) {
+		delivery := queue.NewDelivery(notification,
+			func() error {
+				acked = true
+				return nil
+			},
+			func(requeue bool) error {
+				requeued = requeue
+				return nil
+			})
+		s.handle(context.Background(), delivery)
+		return acked, requeued
+	}
+
+	t.Run("redelivery skips sinks that sent the notification", func(t *testing.T) {
+		stableSink := &sinkStub{}
+		flakySink := &sinkStub{failures: 1}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{stableSink, flakySink}, cfg)
+
+		acked, requeued := deliver(s)
+		require.False(t, acked)
+		require.True(t, requeued)
+
+		acked, _ = deliver(s)
+		require.True(t, acked)
+
+		require.Equal(t, []models.Notification{notification}, stableSink.sent())
+		require.Equal(t, []models.Notification{notification}, flakySink.sent())
+		require.Empty(t, s.redelivered)
+	})
+
+	t.Run("dropped if every sink fails permanently", func(t *testing.T) {
+		permanentSink := &permanentSinkStub{}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{permanentSink, &permanentSinkStub{}}, cfg)
+
+		nacked := false
+		delivery := queue.NewDelivery(notification,
+			func() error {
+				require.Fail(t, "notification is acked")
+				return nil
+			},
+			func(requeue bool) error {
+				nacked = true
+				require.False(t, requeue)
+				return nil
+			})
+		s.handle(context.Background(), delivery)
+
+		require.True(t, nacked)
+		require.Equal(t, 1, permanentSink.calls)
+		require.Empty(t, s.redelivered)
+	})
+
+	t.Run("dropped after max redeliveries", func(t *testing.T) {
+		sink := &sinkStub{failures: 10}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{sink}, cfg)
+
+		for i := 0; i < cfg.MaxRedeliveries; i++ {
+			_, requeued := deliver(s)
+			require.True(t, requeued)
+		}
+
+		acked, requeued := deliver(s)
+		require.False(t, acked)
+		require.False(t, requeued)
+		require.Equal(t, cfg.MaxRedeliveries+1, sink.calls)
+		require.Empty(t, s.redelivered)
+	})
+}
+
+func TestTrack(t *testing.T) {
+	cfg := &config.SenderConfig{
+		RetryDelay:             time.Millisecond,
+		MaxRetryDelay:          time.Millisecond,
+		RedeliveryTTL:          time.Minute,
+		MaxTrackedRedeliveries: 2,
+	}
+	start := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		elapsed []time.Duration
+		want    []string
+	}{
+		{
+			name:    "oldest is dropped above the limit",
+			elapsed: []time.Duration{0, time.Second, 2 * time.Second},
+			want:    []string{"id-1", "id-2"},
+		},
+		{
+			name:    "expired are dropped above the limit",
+			elapsed: []time.Duration{0, 5 * time.Second, 70 * time.Second},
+			want:    []string{"id-2"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(logger.NewMock(), memoryqueue.New(1), nil, cfg)
+
+			for i, elapsed := range tc.elapsed {
+				s.now = func() time.Time { return start.Add(elapsed) }
+				s.track(notificationKey{eventID: fmt.Sprintf("id-%d", i)}, newDeliveryState())
+			}
+
+			var got []string
+			for key := range s.redelivered {
+				got = append(got, key.eventID)
+			}
+			require.ElementsMatch(t, tc.want, got)
+		})
+	}
+
+	t.Run("expired state is not used", func(t *testing.T) {
+		sink := &sinkStub{}
+		s := New(logger.NewMock(), memoryqueue.New(1), []Sink{sink}, cfg)
+		s.now = func() time.Time { return start }
+
+		notification := models.Notification{EventID: "id-1", UserID: 1, Date: start}
+		key := notificationKey{eventID: notification.EventID, userID: notification.UserID, date: start.UnixNano()}
+		state := newDeliveryState()
+		state.sent[0] = struct{}{}
+		s.track(key, state)
+
+		s.now = func() time.Time { return start.Add(cfg.RedeliveryTTL) }
+		s.handle(context.Background(), queue.NewDelivery(notification, nil, nil))
+
+		require.Equal(t, []models.Notification{notification}, sink.sent())
+		require.Empty(t, s.redelivered)
+	})
+}
@@ -0,0 +1,132 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"golang.org/x/exp/slog"
+)
+
+// NewSinks creates the sinks enabled in the configuration.
+func NewSinks(log logger.ILogger, cfg *config.SenderConfig) []Sink {
+	sinks := make([]Sink, 0, len(cfg.Sinks))
+	for _, sink := range cfg.Sinks {
+		switch sink {
+		case config.SinkLog:
+			sinks = append(sinks, NewLogSink(log))
+		case config.SinkWebhook:
+			sinks = append(sinks, NewWebhookSink(cfg.Webhook.URL, cfg.Webhook.Timeout))
+		case config.SinkFile:
+			sinks = append(sinks, NewFileSink(cfg.File.Path))
+		}
+	}
+	return sinks
+}
+
+// LogSink writes notifications as structured log entries.
+type LogSink struct {
+	log logger.ILogger
+}
+
+func NewLogSink(log logger.ILogger) *LogSink {
+	return &LogSink{log: log}
+}
+
+func (s *LogSink) Send(_ context.Context, notification models.Notification) error {
+	s.log.Info("Notification",
+		slog.String("event_id", notification.EventID),
+		slog.String("title", notification.Title),
+		slog.Time("date", notification.Date),
+		slog.Int64("user_id", notification.UserID),
+	)
+	return nil
+}
+
+// WebhookSink posts notifications as JSON to the configured URL. A 4xx status
+// other than 408 and 429 is a permanent failure.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Send(ctx context.Context, notification models.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	default:
+		// The webhook rejects the notification, sending it again does not help.
+		return fmt.Errorf("webhook responded with status %d: %w", resp.StatusCode, ErrPermanent)
+	}
+}
+
+// FileSink appends notifications as JSON lines to a local file.
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Send(_ context.Context, notification models.Notification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write notification: %w", err)
+	}
+	return f.Close()
+}
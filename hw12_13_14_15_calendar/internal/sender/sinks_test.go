@@ -0,0 +1,78 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+var testNotification = models.Notification{
+	EventID: "id-1",
+	Title:   "test",
+	Date:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+	UserID:  1,
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var got models.Notification
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		err := NewWebhookSink(srv.URL, time.Second).Send(context.Background(), testNotification)
+		require.NoError(t, err)
+		require.Equal(t, testNotification, got)
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		err := NewWebhookSink(srv.URL, time.Second).Send(context.Background(), testNotification)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrPermanent)
+	})
+
+	t.Run("rejected notification", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		err := NewWebhookSink(srv.URL, time.Second).Send(context.Background(), testNotification)
+		require.ErrorIs(t, err, ErrPermanent)
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "notifications.log")
+	sink := NewFileSink(path)
+
+	require.NoError(t, sink.Send(context.Background(), testNotification))
+	require.NoError(t, sink.Send(context.Background(), testNotification))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var got models.Notification
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	require.Equal(t, testNotification, got)
+}
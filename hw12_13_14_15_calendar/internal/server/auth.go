@@ -0,0 +1,8 @@
+package server
+
+import "context"
+
+// Authenticator verifies an access token and returns the ID of its user.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (int64, error)
+}
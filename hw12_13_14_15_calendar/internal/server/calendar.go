@@ -4,15 +4,39 @@ import (
 	"context"
 	"time"
 
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/feed"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.33.0 --name=Calendar
 type Calendar interface {
 	CreateEvent(context.Context, *models.Event) (string, error)
-	UpdateEvent(context.Context, *models.Event) error
-	DeleteEvent(context.Context, string) error
-	GetEventByDay(context.Context, int64, time.Time) ([]models.Event, error)
-	GetEventByWeek(context.Context, int64, time.Time) ([]models.Event, error)
-	GetEventByMonth(context.Context, int64, time.Time) ([]models.Event, error)
+	UpdateEvent(ctx context.Context, userID int64, event *models.Event) error
+	DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) error
+	GetDeletedEvents(ctx context.Context, userID int64) ([]models.Event, error)
+	RestoreEvent(ctx context.Context, userID int64, eventID string) error
+	GetEventHistory(ctx context.Context, userID int64, eventID string) ([]models.HistoryEntry, error)
+	GetEvent(ctx context.Context, userID int64, eventID string) (*models.Event, error)
+	GetEventByDay(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)
+	GetEventByWeek(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)
+	GetEventByMonth(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)
+	GetEventsInRange(ctx context.Context, userID int64, from, to time.Time) ([]models.Event, error)
+	GetEventsByUser(ctx context.Context, userID int64) ([]models.Event, error)
+	ImportEvents(ctx context.Context, userID int64, events []models.Event, allowOverlap bool) ([]string, error)
+	FreeBusy(ctx context.Context, userIDs []int64, from, to time.Time, slotDuration time.Duration) (*models.FreeBusy, error)
+
+	CreateCalendar(ctx context.Context, calendar *models.Calendar) (string, error)
+	GetCalendar(ctx context.Context, userID int64, calendarID string) (*models.Calendar, error)
+	GetCalendars(ctx context.Context, userID int64) ([]models.Calendar, error)
+	UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) error
+	DeleteCalendar(ctx context.Context, userID int64, calendarID string) error
+	GetACL(ctx context.Context, userID int64, calendarID string) ([]models.ACLEntry, error)
+	SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) error
+	DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) error
+
+	InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) error
+	RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) error
+	GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) ([]models.Invitation, error)
+
+	WatchEvents(userID int64) *feed.Subscription
 }
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *Server) InviteAttendees(ctx context.Context, req *calendarpb.InviteAttendeesRequest) (*emptypb.Empty, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if err := validateInviteRequest(req); err != nil {
+		log.Error("Validate invite request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.app.InviteAttendees(ctx, userID, req.GetEventId(), req.GetUserIds()); err != nil {
+		log.Error("Invite attendees", "event_id", req.GetEventId(), "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func validateInviteRequest(req *calendarpb.InviteAttendeesRequest) error {
+	if len(req.GetEventId()) == 0 {
+		return errors.New("field eventId is empty")
+	}
+	if len(req.GetUserIds()) == 0 {
+		return errors.New("field userIds is empty")
+	}
+	return nil
+}
+
+func (s *Server) RespondToInvitation(ctx context.Context, req *calendarpb.RespondToInvitationRequest) (*emptypb.Empty, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetEventId()) == 0 {
+		err := errors.New("field eventId is empty")
+		log.Error("Validate response", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !models.AttendeeStatus(req.GetStatus()).Valid() {
+		err := fmt.Errorf("field status is invalid: %q", req.GetStatus())
+		log.Error("Validate response", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.app.RespondToInvitation(ctx, userID, req.GetEventId(), models.AttendeeStatus(req.GetStatus()))
+	if err != nil {
+		log.Error("Respond to invitation", "event_id", req.GetEventId(), "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListInvitations(ctx context.Context, req *calendarpb.ListInvitationsRequest) (*calendarpb.InvitationsResponse, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if req.GetStatus() != "" && !models.AttendeeStatus(req.GetStatus()).Valid() {
+		err := fmt.Errorf("field status is invalid: %q", req.GetStatus())
+		log.Error("Validate request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	invitations, err := s.app.GetInvitations(ctx, userID, models.AttendeeStatus(req.GetStatus()))
+	if err != nil {
+		log.Error("Can not get invitations", "user_id", userID, "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	pbInvitations := make([]*calendarpb.Invitation, len(invitations))
+	for i := range invitations {
+		pbInvitations[i] = &calendarpb.Invitation{
+			Event:  toProtoEvent(&invitations[i].Event),
+			Status: string(invitations[i].Status),
+		}
+	}
+	return &calendarpb.InvitationsResponse{Invitations: pbInvitations}, nil
+}
@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInviteAttendees(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.InviteAttendeesRequest
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name:    "success",
+			request: &calendarpb.InviteAttendeesRequest{EventId: "id-1", UserIds: []int64{2, 3}},
+		},
+		{
+			name:          "empty event id",
+			request:       &calendarpb.InviteAttendeesRequest{UserIds: []int64{2}},
+			validateError: errors.New("field eventId is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:          "empty user ids",
+			request:       &calendarpb.InviteAttendeesRequest{EventId: "id-1"},
+			validateError: errors.New("field userIds is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:      "event of another user",
+			request:   &calendarpb.InviteAttendeesRequest{EventId: "id-2", UserIds: []int64{2}},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil {
+				appMock.On("InviteAttendees", mock.Anything, int64(testUserID), tc.request.GetEventId(),
+					tc.request.GetUserIds()).
+					Return(tc.mockError).
+					Once()
+			}
+
+			_, err := client.InviteAttendees(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRespondToInvitation(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.RespondToInvitationRequest
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name:    "success",
+			request: &calendarpb.RespondToInvitationRequest{EventId: "id-1", Status: "accepted"},
+		},
+		{
+			name:          "invalid status",
+			request:       &calendarpb.RespondToInvitationRequest{EventId: "id-1", Status: "maybe"},
+			validateError: errors.New(`field status is invalid: "maybe"`),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:      "not invited",
+			request:   &calendarpb.RespondToInvitationRequest{EventId: "id-2", Status: "declined"},
+			mockError: storage.ErrInvitationNotExist,
+			code:      codes.NotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil {
+				appMock.On("RespondToInvitation", mock.Anything, int64(testUserID), tc.request.GetEventId(),
+					models.AttendeeStatus(tc.request.GetStatus())).
+					Return(tc.mockError).
+					Once()
+			}
+
+			_, err := client.RespondToInvitation(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListInvitations(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	event := models.Event{
+		ID:        "id-1",
+		Title:     "test",
+		UserID:    2,
+		StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+	}
+
+	appMock.On("GetInvitations", mock.Anything, int64(testUserID), models.StatusNeedsAction).
+		Return([]models.Invitation{{Event: event, Status: models.StatusNeedsAction}}, nil).
+		Once()
+
+	resp, err := client.ListInvitations(context.Background(),
+		&calendarpb.ListInvitationsRequest{Status: "needs-action"})
+	require.NoError(t, err)
+	require.Len(t, resp.GetInvitations(), 1)
+	require.Equal(t, "id-1", resp.GetInvitations()[0].GetEvent().GetId())
+	require.Equal(t, "needs-action", resp.GetInvitations()[0].GetStatus())
+
+	_, err = client.ListInvitations(context.Background(), &calendarpb.ListInvitationsRequest{Status: "maybe"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
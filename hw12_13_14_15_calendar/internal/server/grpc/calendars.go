@@ -0,0 +1,253 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *Server) CreateCalendar(ctx context.Context, req *calendarpb.CreateCalendarRequest) (*calendarpb.CreateCalendarResponse, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetName()) == 0 {
+		err := errors.New("field name is empty")
+		log.Error("Validate calendar", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateTimezone(req.GetTimezone()); err != nil {
+		log.Error("Validate calendar", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	calendarID, err := s.app.CreateCalendar(ctx, &models.Calendar{
+		OwnerID:  userID,
+		Name:     req.GetName(),
+		Timezone: req.GetTimezone(),
+	})
+	if err != nil {
+		log.Error("Create calendar", "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &calendarpb.CreateCalendarResponse{Id: calendarID}, nil
+}
+
+func (s *Server) GetCalendar(ctx context.Context, req *calendarpb.GetCalendarRequest) (*calendarpb.CalendarInfo, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetId()) == 0 {
+		err := errors.New("field id is empty")
+		log.Error("Validate request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	calendar, err := s.app.GetCalendar(ctx, userID, req.GetId())
+	if err != nil {
+		log.Error("Get calendar", "calendar_id", req.GetId(), "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return toProtoCalendar(calendar), nil
+}
+
+func (s *Server) ListCalendars(ctx context.Context, _ *emptypb.Empty) (*calendarpb.CalendarsResponse, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	calendars, err := s.app.GetCalendars(ctx, userID)
+	if err != nil {
+		log.Error("Can not get calendars", "user_id", userID, "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	pbCalendars := make([]*calendarpb.CalendarInfo, len(calendars))
+	for i := range calendars {
+		pbCalendars[i] = toProtoCalendar(&calendars[i])
+	}
+	return &calendarpb.CalendarsResponse{Calendars: pbCalendars}, nil
+}
+
+func (s *Server) UpdateCalendar(ctx context.Context, req *calendarpb.CalendarInfo) (*emptypb.Empty, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetId()) == 0 {
+		err := errors.New("field id is empty")
+		log.Error("Validate calendar", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateTimezone(req.GetTimezone()); err != nil {
+		log.Error("Validate calendar", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.app.UpdateCalendar(ctx, userID, &models.Calendar{
+		ID:       req.GetId(),
+		Name:     req.GetName(),
+		Timezone: req.GetTimezone(),
+	})
+	if err != nil {
+		log.Error("Update calendar", "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) DeleteCalendar(ctx context.Context, req *calendarpb.DeleteCalendarRequest) (*emptypb.Empty, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetId()) == 0 {
+		err := errors.New("field id is empty")
+		log.Error("Validate request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.app.DeleteCalendar(ctx, userID, req.GetId()); err != nil {
+		log.Error("Delete calendar", "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListACL(ctx context.Context, req *calendarpb.ListACLRequest) (*calendarpb.ACLResponse, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetCalendarId()) == 0 {
+		err := errors.New("field calendarId is empty")
+		log.Error("Validate request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	entries, err := s.app.GetACL(ctx, userID, req.GetCalendarId())
+	if err != nil {
+		log.Error("Can not get calendar ACL", "calendar_id", req.GetCalendarId(), "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	pbEntries := make([]*calendarpb.ACLEntry, len(entries))
+	for i := range entries {
+		pbEntries[i] = &calendarpb.ACLEntry{
+			CalendarId: entries[i].CalendarID,
+			UserId:     entries[i].UserID,
+			Role:       string(entries[i].Role),
+		}
+	}
+	return &calendarpb.ACLResponse{Entries: pbEntries}, nil
+}
+
+func (s *Server) SetACLEntry(ctx context.Context, req *calendarpb.ACLEntry) (*emptypb.Empty, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if err := validateACLEntry(req); err != nil {
+		log.Error("Validate ACL entry", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.app.SetACLEntry(ctx, userID, models.ACLEntry{
+		CalendarID: req.GetCalendarId(),
+		UserID:     req.GetUserId(),
+		Role:       models.Role(req.GetRole()),
+	})
+	if err != nil {
+		log.Error("Set ACL entry", "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) DeleteACLEntry(ctx context.Context, req *calendarpb.DeleteACLEntryRequest) (*emptypb.Empty, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetCalendarId()) == 0 {
+		err := errors.New("field calendarId is empty")
+		log.Error("Validate request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.app.DeleteACLEntry(ctx, userID, req.GetCalendarId(), req.GetUserId()); err != nil {
+		log.Error("Delete ACL entry", "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func validateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := models.LoadTimezone(timezone); err != nil {
+		return fmt.Errorf("field timezone is invalid: %w", err)
+	}
+	return nil
+}
+
+func validateACLEntry(entry *calendarpb.ACLEntry) error {
+	if len(entry.GetCalendarId()) == 0 {
+		return errors.New("field calendarId is empty")
+	}
+	if !models.Role(entry.GetRole()).Valid() {
+		return fmt.Errorf("field role is invalid: %q", entry.GetRole())
+	}
+	return nil
+}
+
+func toProtoCalendar(calendar *models.Calendar) *calendarpb.CalendarInfo {
+	return &calendarpb.CalendarInfo{
+		Id:       calendar.ID,
+		OwnerId:  calendar.OwnerID,
+		Name:     calendar.Name,
+		Timezone: calendar.Timezone,
+		Role:     string(calendar.Role),
+	}
+}
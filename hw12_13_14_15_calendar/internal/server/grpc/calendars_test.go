@@ -0,0 +1,194 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestCreateCalendar(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.CreateCalendarRequest
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name:    "success",
+			request: &calendarpb.CreateCalendarRequest{Name: "team", Timezone: "Europe/Moscow"},
+		},
+		{
+			name:          "empty name",
+			request:       &calendarpb.CreateCalendarRequest{Timezone: "UTC"},
+			validateError: errors.New("field name is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:          "invalid timezone",
+			request:       &calendarpb.CreateCalendarRequest{Name: "team", Timezone: "Mars/Olympus"},
+			validateError: errors.New("field timezone is invalid: unknown time zone Mars/Olympus"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:      "create calendar error",
+			request:   &calendarpb.CreateCalendarRequest{Name: "team"},
+			mockError: errors.New("unexpected error"),
+			code:      codes.Internal,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil {
+				appMock.On("CreateCalendar", mock.Anything, &models.Calendar{
+					OwnerID:  testUserID,
+					Name:     tc.request.GetName(),
+					Timezone: tc.request.GetTimezone(),
+				}).
+					Return("calendar-1", tc.mockError).
+					Once()
+			}
+
+			resp, err := client.CreateCalendar(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+				require.Equal(t, "calendar-1", resp.GetId())
+			}
+		})
+	}
+}
+
+func TestGetCalendars(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	calendar := models.Calendar{
+		ID:       "calendar-1",
+		OwnerID:  2,
+		Name:     "team",
+		Timezone: "UTC",
+		Role:     models.RoleReader,
+	}
+	wantCalendar := &calendarpb.CalendarInfo{
+		Id:       "calendar-1",
+		OwnerId:  2,
+		Name:     "team",
+		Timezone: "UTC",
+		Role:     "reader",
+	}
+
+	appMock.On("GetCalendars", mock.Anything, int64(testUserID)).
+		Return([]models.Calendar{calendar}, nil).
+		Once()
+
+	calendars, err := client.ListCalendars(context.Background(), &emptypb.Empty{})
+	require.NoError(t, err)
+	require.Len(t, calendars.GetCalendars(), 1)
+	require.True(t, proto.Equal(wantCalendar, calendars.GetCalendars()[0]))
+
+	appMock.On("GetCalendar", mock.Anything, int64(testUserID), "calendar-1").
+		Return(&calendar, nil).
+		Once()
+
+	resp, err := client.GetCalendar(context.Background(), &calendarpb.GetCalendarRequest{Id: "calendar-1"})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(wantCalendar, resp))
+
+	appMock.On("GetCalendar", mock.Anything, int64(testUserID), "calendar-2").
+		Return(nil, storage.ErrCalendarNotExist).
+		Once()
+
+	_, err = client.GetCalendar(context.Background(), &calendarpb.GetCalendarRequest{Id: "calendar-2"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestSetACLEntry(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.ACLEntry
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name:    "success",
+			request: &calendarpb.ACLEntry{CalendarId: "calendar-1", UserId: 2, Role: "writer"},
+		},
+		{
+			name:          "empty calendar id",
+			request:       &calendarpb.ACLEntry{UserId: 2, Role: "writer"},
+			validateError: errors.New("field calendarId is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:          "invalid role",
+			request:       &calendarpb.ACLEntry{CalendarId: "calendar-1", UserId: 2, Role: "admin"},
+			validateError: errors.New(`field role is invalid: "admin"`),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:      "not an owner",
+			request:   &calendarpb.ACLEntry{CalendarId: "calendar-1", UserId: 2, Role: "reader"},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name:      "not existing calendar",
+			request:   &calendarpb.ACLEntry{CalendarId: "calendar-2", UserId: 2, Role: "reader"},
+			mockError: storage.ErrCalendarNotExist,
+			code:      codes.NotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil {
+				appMock.On("SetACLEntry", mock.Anything, int64(testUserID), models.ACLEntry{
+					CalendarID: tc.request.GetCalendarId(),
+					UserID:     tc.request.GetUserId(),
+					Role:       models.Role(tc.request.GetRole()),
+				}).
+					Return(tc.mockError).
+					Once()
+			}
+
+			_, err := client.SetACLEntry(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
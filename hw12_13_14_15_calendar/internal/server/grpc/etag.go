@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// etagMetadata carries the entity tag of the event version in the response
+	// headers. The gateway returns it as the ETag header.
+	etagMetadata = "etag"
+	// ifMatchMetadata carries the entity tag of the version an update or a
+	// delete expects. The gateway passes the If-Match header in it.
+	ifMatchMetadata = "if-match"
+)
+
+// formatETag returns the entity tag of the event version.
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// parseIfMatch returns the event version required by the If-Match header, or
+// zero if the header is missing or matches any version.
+func parseIfMatch(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// matchesIfNoneMatch reports whether the If-None-Match header matches the
+// entity tag. Weak tags match their strong counterparts, as required for GET.
+func matchesIfNoneMatch(value, etag string) bool {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(value, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedVersion returns the event version the request expects. A zero
+// version of the request is replaced by the version of the if-match metadata.
+func expectedVersion(ctx context.Context, version int64) (int64, error) {
+	if version != 0 {
+		return version, nil
+	}
+
+	values := metadata.ValueFromIncomingContext(ctx, ifMatchMetadata)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	return parseIfMatch(values[0])
+}
+
+// setETag sends the entity tag of the event version in the response headers.
+func setETag(ctx context.Context, log logger.ILogger, version int64) {
+	if err := grpc.SetHeader(ctx, metadata.Pairs(etagMetadata, formatETag(version))); err != nil {
+		log.Error("Set ETag header", "error", err)
+	}
+}
+
+// notModified answers a GET whose If-None-Match header matches the ETag of the
+// response with 304 Not Modified and no body.
+func notModified(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch := r.Header.Get("If-None-Match")
+		if r.Method != http.MethodGet || ifNoneMatch == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&conditionalWriter{ResponseWriter: w, ifNoneMatch: ifNoneMatch}, r)
+	})
+}
+
+type conditionalWriter struct {
+	http.ResponseWriter
+	ifNoneMatch string
+	wroteHeader bool
+	notModified bool
+}
+
+func (w *conditionalWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	etag := w.Header().Get("ETag")
+	if code == http.StatusOK && etag != "" && matchesIfNoneMatch(w.ifNoneMatch, etag) {
+		w.notModified = true
+		w.Header().Del("Content-Type")
+		code = http.StatusNotModified
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *conditionalWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.notModified {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -1,14 +1,19 @@
 package grpc
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
-	"github.com/go-chi/chi/middleware"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/ics"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/rrule"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
-	"golang.org/x/exp/slog"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -17,22 +22,31 @@ import (
 )
 
 func (s *Server) CreateEvent(ctx context.Context, req *calendarpb.CreateEventRequest) (*calendarpb.CreateEventResponse, error) { //nolint:lll
-	log := s.log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
 
 	if err := validateCreateRequest(req); err != nil {
 		log.Error("Validate event", "error", err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	eventID, err := s.app.CreateEvent(ctx, toModelForCreate(req))
+	event := toModelForCreate(req, userID)
+	eventID, err := s.app.CreateEvent(ctx, event)
 	if err != nil {
 		log.Error("Create event", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(errorCode(err), err.Error())
 	}
+
+	setETag(ctx, log, event.Version)
 	return &calendarpb.CreateEventResponse{Id: eventID}, nil
 }
 
-func toModelForCreate(event *calendarpb.CreateEventRequest) *models.Event {
+func toModelForCreate(event *calendarpb.CreateEventRequest, userID int64) *models.Event {
 	var description *string
 	if event.GetDescription() != "" {
 		tmp := event.GetDescription()
@@ -45,34 +59,154 @@ func toModelForCreate(event *calendarpb.CreateEventRequest) *models.Event {
 		notTime = &tmp
 	}
 
+	var recurrenceRule *string
+	if event.GetRecurrenceRule() != "" {
+		tmp := event.GetRecurrenceRule()
+		recurrenceRule = &tmp
+	}
+
+	var calendarID *string
+	if event.GetCalendarId() != "" {
+		tmp := event.GetCalendarId()
+		calendarID = &tmp
+	}
+
+	var allDay *bool
+	if event.GetAllDay() {
+		tmp := true
+		allDay = &tmp
+	}
+
 	return &models.Event{
 		Title:            event.GetTitle(),
 		Description:      description,
-		UserID:           event.GetUserId(),
+		UserID:           userID,
+		CalendarID:       calendarID,
+		Timezone:         event.GetTimezone(),
 		StartDate:        event.GetStartDate().AsTime(),
 		EndDate:          event.GetEndDate().AsTime(),
 		NotificationTime: notTime,
+		RecurrenceRule:   recurrenceRule,
+		ExceptionDates:   toModelDates(event.GetExceptionDates()),
+		AllowOverlap:     event.GetAllowOverlap(),
+		AllDay:           allDay,
+	}
+}
+
+// requestUserID returns the user authenticated by UnaryAuthInterceptor.
+func requestUserID(ctx context.Context) (int64, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "user is not authenticated")
+	}
+	return userID, nil
+}
+
+func errorCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, storage.ErrDateBusy):
+		return codes.AlreadyExists
+	case errors.Is(err, storage.ErrForbidden):
+		return codes.PermissionDenied
+	case errors.Is(err, storage.ErrEventNotExist), errors.Is(err, storage.ErrCalendarNotExist),
+		errors.Is(err, storage.ErrInvitationNotExist):
+		return codes.NotFound
+	case errors.Is(err, storage.ErrInvalidPageToken), errors.Is(err, models.ErrEventTooLong):
+		return codes.InvalidArgument
+	case errors.Is(err, storage.ErrVersionMismatch):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+// toModelTime returns the zero time for a missing timestamp, which an update
+// leaves unchanged.
+func toModelTime(timestamp *timestamppb.Timestamp) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+	return timestamp.AsTime()
+}
+
+func toModelDates(dates []*timestamppb.Timestamp) models.Dates {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	modelDates := make(models.Dates, len(dates))
+	for i := range dates {
+		modelDates[i] = dates[i].AsTime()
+	}
+	return modelDates
+}
+
+func toProtoDates(dates models.Dates) []*timestamppb.Timestamp {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	pbDates := make([]*timestamppb.Timestamp, len(dates))
+	for i := range dates {
+		pbDates[i] = timestamppb.New(dates[i])
 	}
+	return pbDates
 }
 
 func validateCreateRequest(event *calendarpb.CreateEventRequest) error {
 	if len(event.GetTitle()) == 0 {
 		return errors.New("field title is empty")
 	}
-	if event.GetUserId() == 0 {
-		return errors.New("field userId is empty")
-	}
 	if event.GetStartDate() == nil {
 		return errors.New("field startDate is empty")
 	}
 	if event.GetEndDate() == nil {
 		return errors.New("field endDate is empty")
 	}
-	return nil
+	if err := models.CheckEventDuration(event.GetStartDate().AsTime(), event.GetEndDate().AsTime()); err != nil {
+		return err
+	}
+	if event.GetRecurrenceRule() != "" {
+		if _, err := rrule.Parse(event.GetRecurrenceRule()); err != nil {
+			return fmt.Errorf("field recurrenceRule is invalid: %w", err)
+		}
+	}
+	return validateTimezone(event.GetTimezone())
+}
+
+func (s *Server) GetEvent(ctx context.Context, req *calendarpb.GetEventRequest) (*calendarpb.Event, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetId()) == 0 {
+		err := errors.New("field id is empty")
+		log.Error("Validate event", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event, err := s.app.GetEvent(ctx, userID, req.GetId())
+	if err != nil {
+		log.Error("Can not get event", "event_id", req.GetId(), "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	setETag(ctx, log, event.Version)
+	return toProtoEvent(event), nil
 }
 
 func (s *Server) UpdateEvent(ctx context.Context, req *calendarpb.Event) (*emptypb.Empty, error) {
-	log := s.log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
 
 	if len(req.GetId()) == 0 {
 		err := errors.New("field id is empty")
@@ -80,10 +214,33 @@ func (s *Server) UpdateEvent(ctx context.Context, req *calendarpb.Event) (*empty
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	if err := s.app.UpdateEvent(ctx, toModelForUpdate(req)); err != nil {
+	if req.GetRecurrenceRule() != "" {
+		if _, err := rrule.Parse(req.GetRecurrenceRule()); err != nil {
+			err = fmt.Errorf("field recurrenceRule is invalid: %w", err)
+			log.Error("Validate event", "error", err)
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if err := validateTimezone(req.GetTimezone()); err != nil {
+		log.Error("Validate event", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		log.Error("Validate event", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	event := toModelForUpdate(req)
+	event.Version = version
+	if err := s.app.UpdateEvent(ctx, userID, event); err != nil {
 		log.Error("Update event", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(errorCode(err), err.Error())
 	}
+
+	setETag(ctx, log, event.Version)
 	return &emptypb.Empty{}, nil
 }
 
@@ -104,15 +261,26 @@ func toModelForUpdate(event *calendarpb.Event) *models.Event {
 		ID:               event.GetId(),
 		Title:            event.GetTitle(),
 		Description:      description,
-		UserID:           event.GetUserId(),
-		StartDate:        event.GetStartDate().AsTime(),
-		EndDate:          event.GetEndDate().AsTime(),
+		StartDate:        toModelTime(event.GetStartDate()),
+		EndDate:          toModelTime(event.GetEndDate()),
 		NotificationTime: notTime,
+		RecurrenceRule:   event.RecurrenceRule,
+		ExceptionDates:   toModelDates(event.GetExceptionDates()),
+		AllowOverlap:     event.GetAllowOverlap(),
+		Timezone:         event.GetTimezone(),
+		AllDay:           event.AllDay,
+		Version:          event.GetExpectedVersion(),
 	}
 }
 
 func (s *Server) DeleteEvent(ctx context.Context, req *calendarpb.DeleteEventRequest) (*emptypb.Empty, error) {
-	log := s.log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
 
 	if len(req.GetId()) == 0 {
 		err := errors.New("field id is empty")
@@ -120,106 +288,421 @@ func (s *Server) DeleteEvent(ctx context.Context, req *calendarpb.DeleteEventReq
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	if err := s.app.DeleteEvent(ctx, req.Id); err != nil {
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		log.Error("Validate event", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.app.DeleteEvent(ctx, userID, req.GetId(), version); err != nil {
 		log.Error("Delete event", "error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(errorCode(err), err.Error())
 	}
 	return &emptypb.Empty{}, nil
 }
 
+func (s *Server) ListDeletedEvents(ctx context.Context, _ *emptypb.Empty) (*calendarpb.EventsResponse, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	events, err := s.app.GetDeletedEvents(ctx, userID)
+	if err != nil {
+		log.Error("Can not get deleted events", "user_id", userID, "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return toProtoEvents(events), nil
+}
+
+func (s *Server) RestoreEvent(ctx context.Context, req *calendarpb.RestoreEventRequest) (*emptypb.Empty, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetId()) == 0 {
+		err := errors.New("field id is empty")
+		log.Error("Validate event", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.app.RestoreEvent(ctx, userID, req.GetId()); err != nil {
+		log.Error("Restore event", "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) GetEventHistory(ctx context.Context, req *calendarpb.GetEventHistoryRequest) (*calendarpb.EventHistoryResponse, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if len(req.GetId()) == 0 {
+		err := errors.New("field id is empty")
+		log.Error("Validate event", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	entries, err := s.app.GetEventHistory(ctx, userID, req.GetId())
+	if err != nil {
+		log.Error("Can not get event history", "event_id", req.GetId(), "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	pbEntries := make([]*calendarpb.HistoryEntry, len(entries))
+	for i := range entries {
+		pbEntries[i] = toProtoHistoryEntry(&entries[i])
+	}
+	return &calendarpb.EventHistoryResponse{Entries: pbEntries}, nil
+}
+
+func toProtoHistoryEntry(entry *models.HistoryEntry) *calendarpb.HistoryEntry {
+	pbEntry := &calendarpb.HistoryEntry{
+		Id:        entry.ID,
+		EventId:   entry.EventID,
+		Action:    string(entry.Action),
+		ActorId:   entry.ActorID,
+		RequestId: entry.RequestID,
+		ChangedAt: timestamppb.New(entry.ChangedAt),
+	}
+	if entry.Before != nil {
+		pbEntry.Before = toProtoEvent(entry.Before)
+	}
+	if entry.After != nil {
+		pbEntry.After = toProtoEvent(entry.After)
+	}
+	return pbEntry
+}
+
 func (s *Server) GetEventsByDay(ctx context.Context, req *calendarpb.EventsRequestByDate) (*calendarpb.EventsResponse, error) { //nolint:lll
-	log := s.log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
 
 	if err := validateRequestByDate(req); err != nil {
 		log.Error("Validate request by day", "error", err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	events, err := s.app.GetEventByDay(ctx, req.GetUserId(), req.GetStartDate().AsTime())
+	events, nextPageToken, err := s.app.GetEventByDay(ctx, userID, requestDate(req), toFilter(req))
 	if err != nil {
 		log.Error("Can not get events for the selected day",
-			"user_id", req.GetUserId(),
+			"user_id", userID,
 			"day", req.GetStartDate().AsTime(),
 			"error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(errorCode(err), err.Error())
 	}
 
-	return toProtoEvents(events), nil
+	resp := toProtoEvents(events)
+	resp.NextPageToken = nextPageToken
+	return resp, nil
 }
 
 func (s *Server) GetEventsByWeek(ctx context.Context, req *calendarpb.EventsRequestByDate) (*calendarpb.EventsResponse, error) { //nolint:lll
-	log := s.log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
 
 	if err := validateRequestByDate(req); err != nil {
 		log.Error("Validate request by day", "error", err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	events, err := s.app.GetEventByWeek(ctx, req.GetUserId(), req.GetStartDate().AsTime())
+	events, nextPageToken, err := s.app.GetEventByWeek(ctx, userID, requestDate(req), toFilter(req))
 	if err != nil {
 		log.Error("Can not get events for the selected week",
-			"user_id", req.GetUserId(),
+			"user_id", userID,
 			"week", req.GetStartDate().AsTime(),
 			"error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(errorCode(err), err.Error())
 	}
 
-	return toProtoEvents(events), nil
+	resp := toProtoEvents(events)
+	resp.NextPageToken = nextPageToken
+	return resp, nil
 }
 
 func (s *Server) GetEventsByMonth(ctx context.Context, req *calendarpb.EventsRequestByDate) (*calendarpb.EventsResponse, error) { //nolint:lll
-	log := s.log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
 
 	if err := validateRequestByDate(req); err != nil {
 		log.Error("Validate request by day", "error", err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	events, err := s.app.GetEventByMonth(ctx, req.GetUserId(), req.GetStartDate().AsTime())
+	events, nextPageToken, err := s.app.GetEventByMonth(ctx, userID, requestDate(req), toFilter(req))
 	if err != nil {
 		log.Error("Can not get events for the selected month",
-			"user_id", req.GetUserId(),
+			"user_id", userID,
 			"month", req.GetStartDate().AsTime(),
 			"error", err)
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	resp := toProtoEvents(events)
+	resp.NextPageToken = nextPageToken
+	return resp, nil
+}
+
+func (s *Server) GetEventsInRange(ctx context.Context, req *calendarpb.EventsRequestByRange) (*calendarpb.EventsResponse, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	if err := validateRequestByRange(req); err != nil {
+		log.Error("Validate request by range", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	events, err := s.app.GetEventsInRange(ctx, userID, req.GetFrom().AsTime(), req.GetTo().AsTime())
+	if err != nil {
+		log.Error("Can not get events for the selected range",
+			"user_id", userID,
+			"from", req.GetFrom().AsTime(),
+			"to", req.GetTo().AsTime(),
+			"error", err)
+		return nil, status.Error(errorCode(err), err.Error())
 	}
 
 	return toProtoEvents(events), nil
 }
 
-func validateRequestByDate(req *calendarpb.EventsRequestByDate) error {
-	if req.GetUserId() == 0 {
-		return errors.New("field userId is empty")
+func (s *Server) FreeBusy(ctx context.Context, req *calendarpb.FreeBusyRequest) (*calendarpb.FreeBusyResponse, error) {
+	log := server.RequestLogger(ctx, s.log)
+
+	if err := validateFreeBusyRequest(req); err != nil {
+		log.Error("Validate free/busy request", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
+
+	freeBusy, err := s.app.FreeBusy(ctx, req.GetUserIds(), req.GetFrom().AsTime(), req.GetTo().AsTime(),
+		req.GetSlotDuration().AsDuration())
+	if err != nil {
+		log.Error("Can not get free/busy intervals",
+			"user_ids", req.GetUserIds(),
+			"from", req.GetFrom().AsTime(),
+			"to", req.GetTo().AsTime(),
+			"error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	return toProtoFreeBusy(freeBusy), nil
+}
+
+func validateFreeBusyRequest(req *calendarpb.FreeBusyRequest) error {
+	if len(req.GetUserIds()) == 0 {
+		return errors.New("field userIds is empty")
+	}
+	if req.GetFrom() == nil {
+		return errors.New("field from is empty")
+	}
+	if req.GetTo() == nil {
+		return errors.New("field to is empty")
+	}
+	if !req.GetFrom().AsTime().Before(req.GetTo().AsTime()) {
+		return errors.New("field from must be before field to")
+	}
+	if req.GetSlotDuration().AsDuration() < 0 {
+		return errors.New("field slotDuration is negative")
+	}
+	return models.CheckFreeBusyLimits(len(req.GetUserIds()), req.GetFrom().AsTime(), req.GetTo().AsTime(),
+		req.GetSlotDuration().AsDuration())
+}
+
+func toProtoFreeBusy(freeBusy *models.FreeBusy) *calendarpb.FreeBusyResponse {
+	users := make([]*calendarpb.UserBusy, len(freeBusy.Users))
+	for i := range freeBusy.Users {
+		users[i] = &calendarpb.UserBusy{
+			UserId: freeBusy.Users[i].UserID,
+			Busy:   toProtoIntervals(freeBusy.Users[i].Busy),
+		}
+	}
+
+	return &calendarpb.FreeBusyResponse{
+		Users:     users,
+		FreeSlots: toProtoIntervals(freeBusy.FreeSlots),
+	}
+}
+
+func toProtoIntervals(intervals []models.Interval) []*calendarpb.Interval {
+	pbIntervals := make([]*calendarpb.Interval, len(intervals))
+	for i := range intervals {
+		pbIntervals[i] = &calendarpb.Interval{
+			Start: timestamppb.New(intervals[i].Start),
+			End:   timestamppb.New(intervals[i].End),
+		}
+	}
+	return pbIntervals
+}
+
+func (s *Server) ExportCalendar(ctx context.Context, req *calendarpb.ExportCalendarRequest) (*calendarpb.CalendarData, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	events, err := s.app.GetEventsByUser(ctx, userID)
+	if err != nil {
+		log.Error("Can not get events of the user", "user_id", userID, "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+
+	var data bytes.Buffer
+	if err := ics.Encode(&data, events); err != nil {
+		log.Error("Encode calendar", "user_id", userID, "error", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &calendarpb.CalendarData{Data: data.Bytes()}, nil
+}
+
+func (s *Server) ImportCalendar(ctx context.Context, req *calendarpb.ImportCalendarRequest) (*calendarpb.ImportCalendarResponse, error) { //nolint:lll
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return nil, err
+	}
+
+	events, err := ics.Decode(bytes.NewReader(req.GetData()))
+	if err != nil {
+		log.Error("Decode calendar", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ids, err := s.app.ImportEvents(ctx, userID, events, req.GetAllowOverlap())
+	if err != nil {
+		log.Error("Import events", "user_id", userID, "error", err)
+		return nil, status.Error(errorCode(err), err.Error())
+	}
+	return &calendarpb.ImportCalendarResponse{Ids: ids}, nil
+}
+
+func validateRequestByRange(req *calendarpb.EventsRequestByRange) error {
+	if req.GetFrom() == nil {
+		return errors.New("field from is empty")
+	}
+	if req.GetTo() == nil {
+		return errors.New("field to is empty")
+	}
+	if !req.GetFrom().AsTime().Before(req.GetTo().AsTime()) {
+		return errors.New("field from must be before field to")
+	}
+	return nil
+}
+
+func validateRequestByDate(req *calendarpb.EventsRequestByDate) error {
 	if req.GetStartDate() == nil {
 		return errors.New("field startDate is empty")
 	}
+	if req.GetPageSize() < 0 || req.GetPageSize() > models.MaxPageSize {
+		return fmt.Errorf("field pageSize must be between 0 and %d", models.MaxPageSize)
+	}
+	if _, err := models.LoadTimezone(req.GetTz()); err != nil {
+		return fmt.Errorf("field tz is invalid: %w", err)
+	}
 	return nil
 }
 
+// requestDate returns the midnight of the requested date in the requested time
+// zone. The request must be validated.
+func requestDate(req *calendarpb.EventsRequestByDate) time.Time {
+	loc, _ := models.LoadTimezone(req.GetTz())
+	year, month, day := req.GetStartDate().AsTime().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+func toFilter(req *calendarpb.EventsRequestByDate) models.EventFilter {
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = models.DefaultPageSize
+	}
+
+	return models.EventFilter{
+		Title:            req.GetTitle(),
+		WithNotification: req.GetWithNotification(),
+		PageSize:         pageSize,
+		PageToken:        req.GetPageToken(),
+	}
+}
+
 func toProtoEvents(events []models.Event) *calendarpb.EventsResponse {
 	pbEvents := make([]*calendarpb.Event, len(events))
 	for i := range events {
-		var description string
-		if events[i].Description != nil {
-			description = *events[i].Description
-		}
+		pbEvents[i] = toProtoEvent(&events[i])
+	}
 
-		var notTime time.Duration
-		if events[i].NotificationTime != nil {
-			notTime = *events[i].NotificationTime
-		}
+	return &calendarpb.EventsResponse{Events: pbEvents}
+}
 
-		pbEvents[i] = &calendarpb.Event{
-			Id:               events[i].ID,
-			Title:            events[i].Title,
-			Description:      description,
-			UserId:           events[i].UserID,
-			StartDate:        timestamppb.New(events[i].StartDate),
-			EndDate:          timestamppb.New(events[i].EndDate),
-			NotificationTime: durationpb.New(notTime),
-		}
+func toProtoEvent(event *models.Event) *calendarpb.Event {
+	var description string
+	if event.Description != nil {
+		description = *event.Description
 	}
 
-	return &calendarpb.EventsResponse{Events: pbEvents}
+	var notTime time.Duration
+	if event.NotificationTime != nil {
+		notTime = *event.NotificationTime
+	}
+
+	var calendarID string
+	if event.CalendarID != nil {
+		calendarID = *event.CalendarID
+	}
+
+	var deletedAt *timestamppb.Timestamp
+	if event.DeletedAt != nil {
+		deletedAt = timestamppb.New(*event.DeletedAt)
+	}
+
+	return &calendarpb.Event{
+		Id:               event.ID,
+		Title:            event.Title,
+		Description:      description,
+		UserId:           event.UserID,
+		StartDate:        timestamppb.New(event.StartDate),
+		EndDate:          timestamppb.New(event.EndDate),
+		NotificationTime: durationpb.New(notTime),
+		RecurrenceRule:   event.RecurrenceRule,
+		ExceptionDates:   toProtoDates(event.ExceptionDates),
+		CalendarId:       calendarID,
+		Timezone:         event.Timezone,
+		AllDay:           event.AllDay,
+		Version:          event.Version,
+		DeletedAt:        deletedAt,
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"net"
+	"strconv"
 	"testing"
 	"time"
 
@@ -18,12 +19,43 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const testUserID = 1
+
+// tokenAuthenticator accepts a user ID as the bearer token.
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Authenticate(_ context.Context, token string) (int64, error) {
+	userID, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid token")
+	}
+	return userID, nil
+}
+
+func withUser(ctx context.Context, userID int64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+strconv.FormatInt(userID, 10))
+}
+
+// defaultUserInterceptor authenticates calls as testUserID unless they carry
+// their own authorization metadata.
+func defaultUserInterceptor(ctx context.Context, method string, req, reply interface{},
+	cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	if md, ok := metadata.FromOutgoingContext(ctx); !ok || len(md.Get("authorization")) == 0 {
+		ctx = withUser(ctx, testUserID)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
 func startServer(t *testing.T) (*mocks.Calendar, calendarpb.CalendarClient, func()) {
 	t.Helper()
 
@@ -33,11 +65,15 @@ func startServer(t *testing.T) (*mocks.Calendar, calendarpb.CalendarClient, func
 	appMock := mocks.NewCalendar(t)
 
 	calendarSrv := &Server{
-		app: appMock,
-		log: logger.NewMock(),
+		app:  appMock,
+		log:  logger.NewMock(),
+		stop: make(chan struct{}),
 	}
 
-	calendarSrv.srv = grpc.NewServer()
+	calendarSrv.srv = grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryAuthInterceptor(logger.NewMock(), tokenAuthenticator{})),
+		grpc.StreamInterceptor(StreamAuthInterceptor(logger.NewMock(), tokenAuthenticator{})),
+	)
 	calendarpb.RegisterCalendarServer(calendarSrv.srv, calendarSrv)
 
 	go func() {
@@ -49,7 +85,8 @@ func startServer(t *testing.T) (*mocks.Calendar, calendarpb.CalendarClient, func
 	conn, err := grpc.DialContext(context.Background(), "",
 		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
 			return lis.Dial()
-		}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		}), grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(defaultUserInterceptor))
 	require.NoError(t, err)
 
 	closeFn := func() {
@@ -79,7 +116,6 @@ func TestCreateEvent(t *testing.T) {
 			event: &calendarpb.CreateEventRequest{
 				Title:            "test",
 				Description:      "test",
-				UserId:           1,
 				StartDate:        timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
 				EndDate:          timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
 				NotificationTime: durationpb.New(5 * time.Second),
@@ -89,7 +125,6 @@ func TestCreateEvent(t *testing.T) {
 			name: "empty title",
 			event: &calendarpb.CreateEventRequest{
 				Description:      "test",
-				UserId:           1,
 				StartDate:        timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
 				EndDate:          timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
 				NotificationTime: durationpb.New(5 * time.Second),
@@ -97,24 +132,11 @@ func TestCreateEvent(t *testing.T) {
 			validateError: errors.New("field title is empty"),
 			code:          codes.InvalidArgument,
 		},
-		{
-			name: "empty userId",
-			event: &calendarpb.CreateEventRequest{
-				Title:            "test",
-				Description:      "test",
-				StartDate:        timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
-				EndDate:          timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
-				NotificationTime: durationpb.New(5 * time.Second),
-			},
-			validateError: errors.New("field userId is empty"),
-			code:          codes.InvalidArgument,
-		},
 		{
 			name: "empty start_ate",
 			event: &calendarpb.CreateEventRequest{
 				Title:            "test",
 				Description:      "test",
-				UserId:           1,
 				EndDate:          timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
 				NotificationTime: durationpb.New(5 * time.Second),
 			},
@@ -126,19 +148,78 @@ func TestCreateEvent(t *testing.T) {
 			event: &calendarpb.CreateEventRequest{
 				Title:            "test",
 				Description:      "test",
-				UserId:           1,
 				StartDate:        timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
 				NotificationTime: durationpb.New(5 * time.Second),
 			},
 			validateError: errors.New("field endDate is empty"),
 			code:          codes.InvalidArgument,
 		},
+		{
+			name: "too long event",
+			event: &calendarpb.CreateEventRequest{
+				Title:     "test",
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
+				EndDate:   timestamppb.New(time.Date(2123, 8, 16, 12, 0, 0, 0, time.UTC)),
+			},
+			validateError: errors.New("event must last at most 366 days"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "invalid recurrenceRule",
+			event: &calendarpb.CreateEventRequest{
+				Title:          "test",
+				StartDate:      timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
+				EndDate:        timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
+				RecurrenceRule: "FREQ=HOURLY",
+			},
+			validateError: errors.New(`field recurrenceRule is invalid: unsupported FREQ "HOURLY"`),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "time zone",
+			event: &calendarpb.CreateEventRequest{
+				Title:     "test",
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
+				EndDate:   timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
+				Timezone:  "Australia/Sydney",
+			},
+		},
+		{
+			name: "all day",
+			event: &calendarpb.CreateEventRequest{
+				Title:     "test",
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
+				EndDate:   timestamppb.New(time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC)),
+				AllDay:    true,
+			},
+		},
+		{
+			name: "invalid time zone",
+			event: &calendarpb.CreateEventRequest{
+				Title:     "test",
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
+				EndDate:   timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
+				Timezone:  "Mars/Olympus",
+			},
+			validateError: errors.New("field timezone is invalid: unknown time zone Mars/Olympus"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "server time zone",
+			event: &calendarpb.CreateEventRequest{
+				Title:     "test",
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
+				EndDate:   timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
+				Timezone:  "Local",
+			},
+			validateError: errors.New("field timezone is invalid: time zone Local is not an IANA name"),
+			code:          codes.InvalidArgument,
+		},
 		{
 			name: "create event error",
 			event: &calendarpb.CreateEventRequest{
 				Title:            "test",
 				Description:      "test",
-				UserId:           1,
 				StartDate:        timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
 				EndDate:          timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
 				NotificationTime: durationpb.New(5 * time.Second),
@@ -146,12 +227,22 @@ func TestCreateEvent(t *testing.T) {
 			mockError: errors.New("unexpected error"),
 			code:      codes.Internal,
 		},
+		{
+			name: "date is busy",
+			event: &calendarpb.CreateEventRequest{
+				Title:     "test",
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)),
+				EndDate:   timestamppb.New(time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)),
+			},
+			mockError: &storage.ConflictError{EventID: "id-1"},
+			code:      codes.AlreadyExists,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.validateError == nil || tc.mockError != nil {
-				appMock.On("CreateEvent", mock.Anything, toModelForCreate(tc.event)).
+				appMock.On("CreateEvent", mock.Anything, toModelForCreate(tc.event, testUserID)).
 					Return(mock.Anything, tc.mockError).
 					Once()
 			}
@@ -173,10 +264,87 @@ func TestCreateEvent(t *testing.T) {
 	}
 }
 
+func TestGetEvent(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	event := &models.Event{
+		ID:        "id-1",
+		Title:     "test",
+		UserID:    testUserID,
+		StartDate: time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 8, 15, 13, 0, 0, 0, time.UTC),
+		Version:   3,
+	}
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.GetEventRequest
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name:    "success",
+			request: &calendarpb.GetEventRequest{Id: "id-1"},
+		},
+		{
+			name:          "empty id",
+			request:       &calendarpb.GetEventRequest{},
+			validateError: errors.New("field id is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name:      "not existing event",
+			request:   &calendarpb.GetEventRequest{Id: "id-2"},
+			mockError: storage.ErrEventNotExist,
+			code:      codes.NotFound,
+		},
+		{
+			name:      "event of another user",
+			request:   &calendarpb.GetEventRequest{Id: "id-3"},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil {
+				var found *models.Event
+				if tc.mockError == nil {
+					found = event
+				}
+				appMock.On("GetEvent", mock.Anything, int64(testUserID), tc.request.GetId()).
+					Return(found, tc.mockError).
+					Once()
+			}
+
+			var header metadata.MD
+			resp, err := client.GetEvent(context.Background(), tc.request, grpc.Header(&header))
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+				require.True(t, proto.Equal(toProtoEvent(event), resp))
+				require.Equal(t, []string{formatETag(event.Version)}, header.Get(etagMetadata))
+			}
+		})
+	}
+}
+
 func TestUpdateEvent(t *testing.T) {
 	appMock, client, closeConn := startServer(t)
 	defer closeConn()
 
+	allDay := false
+
 	cases := []struct {
 		name          string
 		event         *calendarpb.Event
@@ -191,6 +359,13 @@ func TestUpdateEvent(t *testing.T) {
 				NotificationTime: durationpb.New(5 * time.Second),
 			},
 		},
+		{
+			name: "clear all day",
+			event: &calendarpb.Event{
+				Id:     "id-1",
+				AllDay: &allDay,
+			},
+		},
 		{
 			name: "empty id",
 			event: &calendarpb.Event{
@@ -205,15 +380,43 @@ func TestUpdateEvent(t *testing.T) {
 				Id:               "id-1",
 				NotificationTime: durationpb.New(5 * time.Second),
 			},
-			mockError: storage.ErrEventNotExist,
+			mockError: errors.New("unexpected error"),
 			code:      codes.Internal,
 		},
+		{
+			name: "not existing event",
+			event: &calendarpb.Event{
+				Id:    "id-1",
+				Title: "test",
+			},
+			mockError: storage.ErrEventNotExist,
+			code:      codes.NotFound,
+		},
+		{
+			name: "event of another user",
+			event: &calendarpb.Event{
+				Id:    "id-1",
+				Title: "other",
+			},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name: "version mismatch",
+			event: &calendarpb.Event{
+				Id:              "id-1",
+				Title:           "stale",
+				ExpectedVersion: 2,
+			},
+			mockError: storage.ErrVersionMismatch,
+			code:      codes.FailedPrecondition,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.validateError == nil || tc.mockError != nil {
-				appMock.On("UpdateEvent", mock.Anything, toModelForUpdate(tc.event)).
+				appMock.On("UpdateEvent", mock.Anything, int64(testUserID), toModelForUpdate(tc.event)).
 					Return(tc.mockError).
 					Once()
 			}
@@ -262,15 +465,41 @@ func TestDeleteEvent(t *testing.T) {
 			request: &calendarpb.DeleteEventRequest{
 				Id: "id-1",
 			},
-			mockError: storage.ErrEventNotExist,
+			mockError: errors.New("unexpected error"),
 			code:      codes.Internal,
 		},
+		{
+			name: "not existing event",
+			request: &calendarpb.DeleteEventRequest{
+				Id: "id-2",
+			},
+			mockError: storage.ErrEventNotExist,
+			code:      codes.NotFound,
+		},
+		{
+			name: "event of another user",
+			request: &calendarpb.DeleteEventRequest{
+				Id: "id-3",
+			},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name: "version mismatch",
+			request: &calendarpb.DeleteEventRequest{
+				Id:              "id-4",
+				ExpectedVersion: 2,
+			},
+			mockError: storage.ErrVersionMismatch,
+			code:      codes.FailedPrecondition,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.validateError == nil || tc.mockError != nil {
-				appMock.On("DeleteEvent", mock.Anything, tc.request.GetId()).
+				appMock.On("DeleteEvent", mock.Anything, int64(testUserID), tc.request.GetId(),
+					tc.request.GetExpectedVersion()).
 					Return(tc.mockError).
 					Once()
 			}
@@ -291,10 +520,178 @@ func TestDeleteEvent(t *testing.T) {
 	}
 }
 
+func TestListDeletedEvents(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	deletedAt := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+	events := []models.Event{
+		{
+			ID:        "id-1",
+			Title:     "test",
+			UserID:    testUserID,
+			StartDate: time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2023, 8, 15, 13, 0, 0, 0, time.UTC),
+			Version:   2,
+			DeletedAt: &deletedAt,
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		appMock.On("GetDeletedEvents", mock.Anything, int64(testUserID)).
+			Return(events, nil).
+			Once()
+
+		resp, err := client.ListDeletedEvents(context.Background(), &emptypb.Empty{})
+		require.NoError(t, err)
+		require.Len(t, resp.GetEvents(), 1)
+		require.Equal(t, "id-1", resp.GetEvents()[0].GetId())
+		require.Equal(t, deletedAt, resp.GetEvents()[0].GetDeletedAt().AsTime())
+	})
+
+	t.Run("get deleted events error", func(t *testing.T) {
+		mockError := errors.New("unexpected error")
+		appMock.On("GetDeletedEvents", mock.Anything, int64(testUserID)).
+			Return(nil, mockError).
+			Once()
+
+		_, err := client.ListDeletedEvents(context.Background(), &emptypb.Empty{})
+		require.Equal(t, status.Error(codes.Internal, mockError.Error()), err)
+	})
+}
+
+func TestRestoreEvent(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.RestoreEventRequest
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name: "success",
+			request: &calendarpb.RestoreEventRequest{
+				Id: "id-1",
+			},
+		},
+		{
+			name:          "empty id",
+			request:       &calendarpb.RestoreEventRequest{},
+			validateError: errors.New("field id is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "event is not in the trash",
+			request: &calendarpb.RestoreEventRequest{
+				Id: "id-2",
+			},
+			mockError: storage.ErrEventNotExist,
+			code:      codes.NotFound,
+		},
+		{
+			name: "event of another user",
+			request: &calendarpb.RestoreEventRequest{
+				Id: "id-3",
+			},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+		{
+			name: "date is busy",
+			request: &calendarpb.RestoreEventRequest{
+				Id: "id-4",
+			},
+			mockError: storage.ErrDateBusy,
+			code:      codes.AlreadyExists,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil {
+				appMock.On("RestoreEvent", mock.Anything, int64(testUserID), tc.request.GetId()).
+					Return(tc.mockError).
+					Once()
+			}
+
+			_, err := client.RestoreEvent(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetEventHistory(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	changedAt := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+	before := models.Event{ID: "id-1", Title: "old", UserID: testUserID, Version: 1}
+	after := models.Event{ID: "id-1", Title: "new", UserID: testUserID, Version: 2}
+
+	t.Run("success", func(t *testing.T) {
+		appMock.On("GetEventHistory", mock.Anything, int64(testUserID), "id-1").
+			Return([]models.HistoryEntry{
+				{
+					ID:        2,
+					EventID:   "id-1",
+					Action:    models.ActionUpdate,
+					ActorID:   testUserID,
+					RequestID: "request-1",
+					Before:    &before,
+					After:     &after,
+					ChangedAt: changedAt,
+				},
+			}, nil).
+			Once()
+
+		resp, err := client.GetEventHistory(context.Background(), &calendarpb.GetEventHistoryRequest{Id: "id-1"})
+		require.NoError(t, err)
+		require.Len(t, resp.GetEntries(), 1)
+
+		entry := resp.GetEntries()[0]
+		require.Equal(t, "update", entry.GetAction())
+		require.Equal(t, int64(testUserID), entry.GetActorId())
+		require.Equal(t, "request-1", entry.GetRequestId())
+		require.Equal(t, "old", entry.GetBefore().GetTitle())
+		require.Equal(t, "new", entry.GetAfter().GetTitle())
+		require.Equal(t, changedAt, entry.GetChangedAt().AsTime())
+	})
+
+	t.Run("empty id", func(t *testing.T) {
+		_, err := client.GetEventHistory(context.Background(), &calendarpb.GetEventHistoryRequest{})
+		require.Equal(t, status.Error(codes.InvalidArgument, "field id is empty"), err)
+	})
+
+	t.Run("not existing event", func(t *testing.T) {
+		appMock.On("GetEventHistory", mock.Anything, int64(testUserID), "id-2").
+			Return(nil, storage.ErrEventNotExist).
+			Once()
+
+		_, err := client.GetEventHistory(context.Background(), &calendarpb.GetEventHistoryRequest{Id: "id-2"})
+		require.Equal(t, status.Error(codes.NotFound, storage.ErrEventNotExist.Error()), err)
+	})
+}
+
 func TestGetEventsByDay(t *testing.T) {
 	appMock, client, closeConn := startServer(t)
 	defer closeConn()
 
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
 	cases := []struct {
 		name          string
 		request       *calendarpb.EventsRequestByDate
@@ -307,7 +704,6 @@ func TestGetEventsByDay(t *testing.T) {
 		{
 			name: "success",
 			request: &calendarpb.EventsRequestByDate{
-				UserId:    1,
 				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
 			},
 			date: time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC),
@@ -326,25 +722,58 @@ func TestGetEventsByDay(t *testing.T) {
 			},
 		},
 		{
-			name: "empty userId",
+			name:          "empty startDate",
+			request:       &calendarpb.EventsRequestByDate{},
+			validateError: errors.New("field startDate is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "invalid pageSize",
 			request: &calendarpb.EventsRequestByDate{
 				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
+				PageSize:  5000,
 			},
-			validateError: errors.New("field userId is empty"),
+			validateError: errors.New("field pageSize must be between 0 and 1000"),
 			code:          codes.InvalidArgument,
 		},
 		{
-			name: "empty startDate",
+			name: "time zone",
 			request: &calendarpb.EventsRequestByDate{
-				UserId: 1,
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
+				Tz:        "Australia/Sydney",
 			},
-			validateError: errors.New("field startDate is empty"),
+			date: time.Date(2023, 8, 16, 0, 0, 0, 0, sydney),
+			events: []models.Event{
+				{
+					Title:     "test",
+					UserID:    1,
+					StartDate: time.Date(2023, 8, 15, 22, 0, 0, 0, time.UTC),
+					EndDate:   time.Date(2023, 8, 15, 23, 0, 0, 0, time.UTC),
+					Timezone:  "Australia/Sydney",
+				},
+			},
+		},
+		{
+			name: "invalid time zone",
+			request: &calendarpb.EventsRequestByDate{
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
+				Tz:        "Mars/Olympus",
+			},
+			validateError: errors.New("field tz is invalid: unknown time zone Mars/Olympus"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "server time zone",
+			request: &calendarpb.EventsRequestByDate{
+				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
+				Tz:        "Local",
+			},
+			validateError: errors.New("field tz is invalid: time zone Local is not an IANA name"),
 			code:          codes.InvalidArgument,
 		},
 		{
 			name: "get events error",
 			request: &calendarpb.EventsRequestByDate{
-				UserId:    1,
 				StartDate: timestamppb.New(time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)),
 			},
 			date:      time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC),
@@ -356,8 +785,8 @@ func TestGetEventsByDay(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.validateError == nil || tc.mockError != nil {
-				appMock.On("GetEventByDay", mock.Anything, tc.request.UserId, tc.date).
-					Return(tc.events, tc.mockError).
+				appMock.On("GetEventByDay", mock.Anything, int64(testUserID), tc.date, mock.Anything).
+					Return(tc.events, "", tc.mockError).
 					Once()
 			}
 
@@ -394,7 +823,6 @@ func TestGetEventsByWeek(t *testing.T) {
 		{
 			name: "success",
 			request: &calendarpb.EventsRequestByDate{
-				UserId:    1,
 				StartDate: timestamppb.New(time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC)),
 			},
 			date: time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC),
@@ -413,25 +841,14 @@ func TestGetEventsByWeek(t *testing.T) {
 			},
 		},
 		{
-			name: "empty userId",
-			request: &calendarpb.EventsRequestByDate{
-				StartDate: timestamppb.New(time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC)),
-			},
-			validateError: errors.New("field userId is empty"),
-			code:          codes.InvalidArgument,
-		},
-		{
-			name: "empty startDate",
-			request: &calendarpb.EventsRequestByDate{
-				UserId: 1,
-			},
+			name:          "empty startDate",
+			request:       &calendarpb.EventsRequestByDate{},
 			validateError: errors.New("field startDate is empty"),
 			code:          codes.InvalidArgument,
 		},
 		{
 			name: "get events error",
 			request: &calendarpb.EventsRequestByDate{
-				UserId:    1,
 				StartDate: timestamppb.New(time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC)),
 			},
 			date:      time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC),
@@ -443,8 +860,8 @@ func TestGetEventsByWeek(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.validateError == nil || tc.mockError != nil {
-				appMock.On("GetEventByWeek", mock.Anything, tc.request.UserId, tc.date).
-					Return(tc.events, tc.mockError).
+				appMock.On("GetEventByWeek", mock.Anything, int64(testUserID), tc.date, mock.Anything).
+					Return(tc.events, "", tc.mockError).
 					Once()
 			}
 
@@ -481,7 +898,6 @@ func TestGetEventsByMonth(t *testing.T) {
 		{
 			name: "success",
 			request: &calendarpb.EventsRequestByDate{
-				UserId:    1,
 				StartDate: timestamppb.New(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)),
 			},
 			date: time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
@@ -500,42 +916,123 @@ func TestGetEventsByMonth(t *testing.T) {
 			},
 		},
 		{
-			name: "empty userId",
+			name:          "empty startDate",
+			request:       &calendarpb.EventsRequestByDate{},
+			validateError: errors.New("field startDate is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "get events error",
 			request: &calendarpb.EventsRequestByDate{
 				StartDate: timestamppb.New(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)),
 			},
-			validateError: errors.New("field userId is empty"),
+			date:      time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+			mockError: errors.New("unexpected error"),
+			code:      codes.Internal,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil || tc.mockError != nil {
+				appMock.On("GetEventByMonth", mock.Anything, int64(testUserID), tc.date, mock.Anything).
+					Return(tc.events, "", tc.mockError).
+					Once()
+			}
+
+			resp, err := client.GetEventsByMonth(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+				require.Equal(t, toProtoEvents(tc.events).GetEvents(), resp.GetEvents())
+			}
+		})
+	}
+}
+
+func TestGetEventsInRange(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	from := time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.EventsRequestByRange
+		events        []models.Event
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name: "success",
+			request: &calendarpb.EventsRequestByRange{
+				From: timestamppb.New(from),
+				To:   timestamppb.New(to),
+			},
+			events: []models.Event{
+				{
+					Title:     "test",
+					UserID:    1,
+					StartDate: time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+					EndDate:   time.Date(2023, 8, 17, 13, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name: "empty to",
+			request: &calendarpb.EventsRequestByRange{
+				From: timestamppb.New(from),
+			},
+			validateError: errors.New("field to is empty"),
 			code:          codes.InvalidArgument,
 		},
 		{
-			name: "empty startDate",
-			request: &calendarpb.EventsRequestByDate{
-				UserId: 1,
+			name: "from after to",
+			request: &calendarpb.EventsRequestByRange{
+				From: timestamppb.New(to),
+				To:   timestamppb.New(from),
 			},
-			validateError: errors.New("field startDate is empty"),
+			validateError: errors.New("field from must be before field to"),
 			code:          codes.InvalidArgument,
 		},
 		{
 			name: "get events error",
-			request: &calendarpb.EventsRequestByDate{
-				UserId:    1,
-				StartDate: timestamppb.New(time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)),
+			request: &calendarpb.EventsRequestByRange{
+				From: timestamppb.New(from),
+				To:   timestamppb.New(to),
 			},
-			date:      time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
 			mockError: errors.New("unexpected error"),
 			code:      codes.Internal,
 		},
+		{
+			name: "forbidden",
+			request: &calendarpb.EventsRequestByRange{
+				From: timestamppb.New(from),
+				To:   timestamppb.New(to),
+			},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			if tc.validateError == nil || tc.mockError != nil {
-				appMock.On("GetEventByMonth", mock.Anything, tc.request.UserId, tc.date).
+				appMock.On("GetEventsInRange", mock.Anything, int64(testUserID), from, to).
 					Return(tc.events, tc.mockError).
 					Once()
 			}
 
-			resp, err := client.GetEventsByMonth(context.Background(), tc.request)
+			resp, err := client.GetEventsInRange(context.Background(), tc.request)
 
 			switch {
 			case tc.mockError != nil:
@@ -551,3 +1048,188 @@ func TestGetEventsByMonth(t *testing.T) {
 		})
 	}
 }
+
+func TestFreeBusy(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	from := time.Date(2023, 8, 16, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name          string
+		request       *calendarpb.FreeBusyRequest
+		freeBusy      *models.FreeBusy
+		validateError error
+		mockError     error
+		code          codes.Code
+	}{
+		{
+			name: "success",
+			request: &calendarpb.FreeBusyRequest{
+				UserIds:      []int64{1, 2},
+				From:         timestamppb.New(from),
+				To:           timestamppb.New(to),
+				SlotDuration: durationpb.New(time.Hour),
+			},
+			freeBusy: &models.FreeBusy{
+				Users: []models.UserBusy{
+					{UserID: 1, Busy: []models.Interval{{Start: from, End: from.Add(time.Hour)}}},
+					{UserID: 2},
+				},
+				FreeSlots: []models.Interval{{Start: from.Add(time.Hour), End: from.Add(2 * time.Hour)}},
+			},
+		},
+		{
+			name: "empty userIds",
+			request: &calendarpb.FreeBusyRequest{
+				From:         timestamppb.New(from),
+				To:           timestamppb.New(to),
+				SlotDuration: durationpb.New(time.Hour),
+			},
+			validateError: errors.New("field userIds is empty"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "too long interval",
+			request: &calendarpb.FreeBusyRequest{
+				UserIds:      []int64{1},
+				From:         timestamppb.New(from),
+				To:           timestamppb.New(from.Add(365 * 24 * time.Hour)),
+				SlotDuration: durationpb.New(time.Nanosecond),
+			},
+			validateError: errors.New("interval must be at most 2208h0m0s"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "too many slots",
+			request: &calendarpb.FreeBusyRequest{
+				UserIds:      []int64{1},
+				From:         timestamppb.New(from),
+				To:           timestamppb.New(from.Add(30 * 24 * time.Hour)),
+				SlotDuration: durationpb.New(time.Minute),
+			},
+			validateError: errors.New("interval holds too many slots"),
+			code:          codes.InvalidArgument,
+		},
+		{
+			name: "free/busy error",
+			request: &calendarpb.FreeBusyRequest{
+				UserIds:      []int64{1, 2},
+				From:         timestamppb.New(from),
+				To:           timestamppb.New(to),
+				SlotDuration: durationpb.New(time.Hour),
+			},
+			mockError: errors.New("unexpected error"),
+			code:      codes.Internal,
+		},
+		{
+			name: "forbidden",
+			request: &calendarpb.FreeBusyRequest{
+				UserIds:      []int64{1, 2},
+				From:         timestamppb.New(from),
+				To:           timestamppb.New(to),
+				SlotDuration: durationpb.New(time.Hour),
+			},
+			mockError: storage.ErrForbidden,
+			code:      codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.validateError == nil || tc.mockError != nil {
+				appMock.On("FreeBusy", mock.Anything, tc.request.UserIds, from, to, time.Hour).
+					Return(tc.freeBusy, tc.mockError).
+					Once()
+			}
+
+			resp, err := client.FreeBusy(context.Background(), tc.request)
+
+			switch {
+			case tc.mockError != nil:
+				require.Equal(t, status.Error(tc.code, tc.mockError.Error()), err)
+
+			case tc.validateError != nil:
+				require.Equal(t, status.Error(tc.code, tc.validateError.Error()), err)
+
+			default:
+				require.NoError(t, err)
+				require.True(t, proto.Equal(toProtoFreeBusy(tc.freeBusy), resp))
+			}
+		})
+	}
+}
+
+func TestImportExportCalendar(t *testing.T) {
+	appMock, client, closeConn := startServer(t)
+	defer closeConn()
+
+	events := []models.Event{{
+		Title:     "test",
+		StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+	}}
+
+	appMock.On("GetEventsByUser", mock.Anything, int64(1)).
+		Return(events, nil).
+		Once()
+
+	exported, err := client.ExportCalendar(context.Background(), &calendarpb.ExportCalendarRequest{})
+	require.NoError(t, err)
+	require.Contains(t, string(exported.GetData()), "SUMMARY:test\r\n")
+
+	appMock.On("ImportEvents", mock.Anything, int64(2), events, false).
+		Return([]string{"id-1"}, nil).
+		Once()
+
+	imported, err := client.ImportCalendar(withUser(context.Background(), 2), &calendarpb.ImportCalendarRequest{
+		Data: exported.GetData(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"id-1"}, imported.GetIds())
+
+	_, err = client.ImportCalendar(context.Background(), &calendarpb.ImportCalendarRequest{
+		Data: []byte("BEGIN:VEVENT\r\n"),
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	appMock.On("GetEventsByUser", mock.Anything, int64(3)).
+		Return(nil, storage.ErrForbidden).
+		Once()
+
+	_, err = client.ExportCalendar(withUser(context.Background(), 3), &calendarpb.ExportCalendarRequest{})
+	require.Equal(t, status.Error(codes.PermissionDenied, storage.ErrForbidden.Error()), err)
+}
+
+func TestUnaryAuthInterceptor(t *testing.T) {
+	_, client, closeConn := startServer(t)
+	defer closeConn()
+
+	cases := []struct {
+		name          string
+		authorization string
+	}{
+		{
+			name:          "missing token",
+			authorization: "",
+		},
+		{
+			name:          "not a bearer token",
+			authorization: "Basic 1",
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer invalid",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", tc.authorization)
+
+			_, err := client.ExportCalendar(ctx, &calendarpb.ExportCalendarRequest{})
+			require.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+
+	"github.com/go-chi/render"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Gateway returns the REST API generated from the HTTP annotations of
+// calendar.proto. Requests are handled in process by the server, so the REST
+// and gRPC APIs share validation and error mapping. The user must already be
+// authenticated in the request context. The options are applied after the
+// default ones, so they can replace the marshaler.
+func (s *Server) Gateway(ctx context.Context, opts ...runtime.ServeMuxOption) (http.Handler, error) {
+	opts = append([]runtime.ServeMuxOption{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(gatewayErrorHandler),
+		runtime.WithIncomingHeaderMatcher(gatewayIncomingHeader),
+		runtime.WithOutgoingHeaderMatcher(gatewayOutgoingHeader),
+	}, opts...)
+	mux := runtime.NewServeMux(opts...)
+
+	if err := calendarpb.RegisterCalendarHandlerServer(ctx, mux, s); err != nil {
+		return nil, err
+	}
+	return notModified(mux), nil
+}
+
+// gatewayIncomingHeader passes the If-Match header as the if-match metadata.
+func gatewayIncomingHeader(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == "If-Match" {
+		return ifMatchMetadata, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// gatewayOutgoingHeader returns the etag metadata as the ETag header.
+func gatewayOutgoingHeader(key string) (string, bool) {
+	if key == etagMetadata {
+		return "ETag", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// gatewayErrorHandler writes errors in the format of the rest of the HTTP API.
+func gatewayErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler,
+	w http.ResponseWriter, r *http.Request, err error,
+) {
+	st := status.Convert(err)
+
+	code := runtime.HTTPStatusFromCode(st.Code())
+	if st.Code() == codes.FailedPrecondition {
+		// The only failed precondition is a version mismatch.
+		code = http.StatusPreconditionFailed
+	}
+
+	render.Status(r, code)
+	render.JSON(w, r, resp.Error(st.Message()))
+}
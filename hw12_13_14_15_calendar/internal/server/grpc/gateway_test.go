@@ -0,0 +1,176 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGateway(t *testing.T) {
+	appMock := mocks.NewCalendar(t)
+	srv := &Server{app: appMock, log: logger.NewMock()}
+
+	gateway, err := srv.Gateway(context.Background())
+	require.NoError(t, err)
+
+	day := time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		header map[string]string
+		mockFn func()
+		code   int
+		etag   string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "get event",
+			method: http.MethodGet,
+			url:    "/v2/calendar/events/id-1",
+			mockFn: func() {
+				appMock.On("GetEvent", mock.Anything, int64(testUserID), "id-1").
+					Return(&models.Event{ID: "id-1", Title: "title", UserID: testUserID, Version: 3}, nil).
+					Once()
+			},
+			code: http.StatusOK,
+			etag: `"3"`,
+			want: map[string]interface{}{"id": "id-1", "title": "title", "version": "3"},
+		},
+		{
+			name:   "not modified event",
+			method: http.MethodGet,
+			url:    "/v2/calendar/events/id-1",
+			header: map[string]string{"If-None-Match": `W/"3"`},
+			mockFn: func() {
+				appMock.On("GetEvent", mock.Anything, int64(testUserID), "id-1").
+					Return(&models.Event{ID: "id-1", Title: "title", UserID: testUserID, Version: 3}, nil).
+					Once()
+			},
+			code: http.StatusNotModified,
+			etag: `"3"`,
+		},
+		{
+			name:   "modified event",
+			method: http.MethodGet,
+			url:    "/v2/calendar/events/id-1",
+			header: map[string]string{"If-None-Match": `"2"`},
+			mockFn: func() {
+				appMock.On("GetEvent", mock.Anything, int64(testUserID), "id-1").
+					Return(&models.Event{ID: "id-1", Title: "title", UserID: testUserID, Version: 3}, nil).
+					Once()
+			},
+			code: http.StatusOK,
+			etag: `"3"`,
+			want: map[string]interface{}{"version": "3"},
+		},
+		{
+			name:   "not existing event",
+			method: http.MethodGet,
+			url:    "/v2/calendar/events/id-2",
+			mockFn: func() {
+				appMock.On("GetEvent", mock.Anything, int64(testUserID), "id-2").
+					Return(nil, storage.ErrEventNotExist).
+					Once()
+			},
+			code: http.StatusNotFound,
+			want: map[string]interface{}{"error": storage.ErrEventNotExist.Error()},
+		},
+		{
+			name:   "events by day are not routed to get event",
+			method: http.MethodGet,
+			url:    "/v2/calendar/events/day?startDate=2023-08-16T00:00:00Z",
+			mockFn: func() {
+				appMock.On("GetEventByDay", mock.Anything, int64(testUserID), day, models.EventFilter{PageSize: 100}).
+					Return([]models.Event{}, "", nil).
+					Once()
+			},
+			code: http.StatusOK,
+			want: map[string]interface{}{"events": []interface{}{}, "nextPageToken": ""},
+		},
+		{
+			name:   "validation error of the gRPC server",
+			method: http.MethodPost,
+			url:    "/v2/calendar/events",
+			body:   `{"title": "title"}`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "version mismatch",
+			method: http.MethodDelete,
+			url:    "/v2/calendar/events/id-1?expectedVersion=2",
+			mockFn: func() {
+				appMock.On("DeleteEvent", mock.Anything, int64(testUserID), "id-1", int64(2)).
+					Return(storage.ErrVersionMismatch).
+					Once()
+			},
+			code: http.StatusPreconditionFailed,
+			want: map[string]interface{}{"error": storage.ErrVersionMismatch.Error()},
+		},
+		{
+			name:   "version of the If-Match header",
+			method: http.MethodDelete,
+			url:    "/v2/calendar/events/id-1",
+			header: map[string]string{"If-Match": `"4"`},
+			mockFn: func() {
+				appMock.On("DeleteEvent", mock.Anything, int64(testUserID), "id-1", int64(4)).
+					Return(nil).
+					Once()
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:   "invalid If-Match header",
+			method: http.MethodDelete,
+			url:    "/v2/calendar/events/id-1",
+			header: map[string]string{"If-Match": "4"},
+			code:   http.StatusBadRequest,
+			want:   map[string]interface{}{"error": errInvalidIfMatch.Error()},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.mockFn != nil {
+				tc.mockFn()
+			}
+
+			ctx := auth.WithUserID(context.Background(), testUserID)
+			req, err := http.NewRequestWithContext(ctx, tc.method, tc.url, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			for key, value := range tc.header {
+				req.Header.Set(key, value)
+			}
+
+			rr := httptest.NewRecorder()
+			gateway.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code, rr.Body.String())
+			require.Equal(t, tc.etag, rr.Header().Get("ETag"))
+			if tc.code == http.StatusNotModified {
+				require.Empty(t, rr.Body.Bytes())
+				return
+			}
+
+			var got map[string]interface{}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			for key, value := range tc.want {
+				require.Equal(t, value, got[key], key)
+			}
+		})
+	}
+}
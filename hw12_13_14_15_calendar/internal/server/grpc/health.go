@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthServer implements the grpc.health.v1 service. The whole server ("")
+// and the calendar service are serving while the service is ready. Watch is
+// not supported.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	health server.HealthChecker
+	log    logger.ILogger
+}
+
+func (h *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) { //nolint:lll
+	if req.GetService() != "" && req.GetService() != calendarpb.Calendar_ServiceDesc.ServiceName {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	if err := h.health.Ready(ctx); err != nil {
+		server.RequestLogger(ctx, h.log).Warn("Service is not ready", "error", err)
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+// isPublicMethod reports whether the method is served without authentication.
+func isPublicMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
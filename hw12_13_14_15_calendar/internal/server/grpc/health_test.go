@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type healthCheckerFunc func(ctx context.Context) error
+
+func (f healthCheckerFunc) Ready(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHealthCheck(t *testing.T) {
+	cases := []struct {
+		name    string
+		service string
+		ready   error
+		status  healthpb.HealthCheckResponse_ServingStatus
+		code    codes.Code
+	}{
+		{
+			name:   "serving",
+			status: healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:    "calendar service is serving",
+			service: "calendar.Calendar",
+			status:  healthpb.HealthCheckResponse_SERVING,
+		},
+		{
+			name:   "not ready",
+			ready:  errors.New("connection refused"),
+			status: healthpb.HealthCheckResponse_NOT_SERVING,
+		},
+		{
+			name:    "unknown service",
+			service: "calendar.Unknown",
+			code:    codes.NotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			srv := &healthServer{
+				health: healthCheckerFunc(func(context.Context) error { return tc.ready }),
+				log:    logger.NewMock(),
+			}
+
+			resp, err := srv.Check(context.Background(), &healthpb.HealthCheckRequest{Service: tc.service})
+			if tc.code != codes.OK {
+				require.Equal(t, tc.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.status, resp.GetStatus())
+		})
+	}
+}
+
+func TestUnaryAuthInterceptor_HealthCheck(t *testing.T) {
+	interceptor := UnaryAuthInterceptor(logger.NewMock(), tokenAuthenticator{})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	resp, err := interceptor(context.Background(), nil, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/calendar.Calendar/GetEvent"}
+	_, err = interceptor(context.Background(), nil, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
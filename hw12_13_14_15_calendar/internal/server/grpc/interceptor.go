@@ -2,36 +2,215 @@ package grpc
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/google/uuid"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func UnaryLoggerInterceptor(log logger.ILogger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) { //nolint:lll
-		t1 := time.Now()
-		reqID := newRequestID()
+		ctx, done := startRequestLog(ctx, log, info.FullMethod)
+		defer done()
 
-		entry := log.With(
-			slog.String("request_id", reqID),
-			slog.String("method", info.FullMethod),
+		return handler(ctx, req)
+	}
+}
+
+// StreamLoggerInterceptor is UnaryLoggerInterceptor for streams.
+func StreamLoggerInterceptor(log logger.ILogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, done := startRequestLog(ss.Context(), log, info.FullMethod)
+		defer done()
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// startRequestLog stores a new request ID in the context. The returned
+// function logs the completed request.
+func startRequestLog(ctx context.Context, log logger.ILogger, method string) (context.Context, func()) {
+	t1 := time.Now()
+	reqID := newRequestID()
+
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, reqID)
+	entry := server.RequestLogger(ctx, log).With(
+		slog.String("method", method),
+	)
+
+	return ctx, func() {
+		entry.Info("gRPC/server: request completed",
+			slog.String("duration", time.Since(t1).String()),
 		)
+	}
+}
+
+// serverStream overrides the context of the stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryTracingInterceptor starts a server span for a request. The span
+// continues the trace of the traceparent metadata.
+func UnaryTracingInterceptor() grpc.UnaryServerInterceptor {
+	tracer := tracing.Tracer()
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) { //nolint:lll
+		ctx, span := startSpan(ctx, tracer, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+// StreamTracingInterceptor is UnaryTracingInterceptor for streams, the span
+// lasts until the stream ends.
+func StreamTracingInterceptor() grpc.StreamServerInterceptor {
+	tracer := tracing.Tracer()
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startSpan(ss.Context(), tracer, info.FullMethod)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		endSpan(span, err)
+		return err
+	}
+}
+
+func startSpan(ctx context.Context, tracer trace.Tracer, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	service, method := splitFullMethod(fullMethod)
+	return tracer.Start(ctx, fullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)),
+	)
+}
 
-		defer func() {
-			entry.Info("gRPC/server: request completed",
-				slog.String("duration", time.Since(t1).String()),
-			)
-		}()
+func endSpan(span trace.Span, err error) {
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))))
+	tracing.End(span, err)
+}
+
+// splitFullMethod splits "/package.Service/Method" into the service and the
+// method names.
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "", fullMethod
+	}
+	return service, method
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// UnaryMetricsInterceptor records the number, status codes and latency of
+// requests per method and the number of requests in flight.
+func UnaryMetricsInterceptor(m *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) { //nolint:lll
+		done := m.StartGRPCRequest(info.FullMethod)
 
-		ctx = context.WithValue(ctx, middleware.RequestIDKey, reqID)
+		resp, err := handler(ctx, req)
+		done(status.Code(err))
+		return resp, err
+	}
+}
+
+// UnaryAuthInterceptor authenticates the bearer token from the "authorization"
+// metadata and stores the ID of its user in the request context. Health checks
+// are not authenticated.
+func UnaryAuthInterceptor(log logger.ILogger, authenticator server.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) { //nolint:lll
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, log, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
 		return handler(ctx, req)
 	}
 }
 
+// StreamAuthInterceptor is UnaryAuthInterceptor for streams.
+func StreamAuthInterceptor(log logger.ILogger, authenticator server.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), log, authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, log logger.ILogger, authenticator server.Authenticator, method string,
+) (context.Context, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token, _ = auth.BearerToken(values[0])
+		}
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	userID, err := authenticator.Authenticate(ctx, token)
+	if err != nil {
+		server.RequestLogger(ctx, log).Error("Authenticate request",
+			"method", method,
+			"error", err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return auth.WithUserID(ctx, userID), nil
+}
+
 func newRequestID() string {
 	return uuid.New().String()
 }
@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryTracingInterceptor(t *testing.T) {
+	_, err := tracing.Init(&config.TracingConfig{Exporter: config.TracingExporterNone})
+	require.NoError(t, err)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	cases := []struct {
+		name    string
+		md      metadata.MD
+		traceID string
+	}{
+		{
+			name:    "with traceparent",
+			md:      metadata.Pairs("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01"),
+			traceID: traceID,
+		},
+		{
+			name: "without traceparent",
+			md:   metadata.MD{},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var gotTraceID string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				gotTraceID = tracing.TraceID(ctx)
+				return nil, nil
+			}
+
+			ctx := metadata.NewIncomingContext(context.Background(), tc.md)
+			info := &grpc.UnaryServerInfo{FullMethod: "/calendar.Calendar/GetEvent"}
+
+			_, err := UnaryTracingInterceptor()(ctx, nil, info, handler)
+			require.NoError(t, err)
+			require.Equal(t, tc.traceID, gotTraceID)
+		})
+	}
+}
+
+// testServerStream is a grpc.ServerStream with the given context.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	_, err := tracing.Init(&config.TracingConfig{Exporter: config.TracingExporterNone})
+	require.NoError(t, err)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	md := metadata.Pairs(
+		"traceparent", "00-"+traceID+"-00f067aa0ba902b7-01",
+		"authorization", "Bearer 2",
+	)
+	ss := &testServerStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+	info := &grpc.StreamServerInfo{FullMethod: "/calendar.Calendar/WatchEvents", IsServerStream: true}
+
+	var ctx context.Context
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		ctx = stream.Context()
+		return nil
+	}
+
+	authenticated := func(srv interface{}, stream grpc.ServerStream) error {
+		return StreamAuthInterceptor(logger.NewMock(), tokenAuthenticator{})(srv, stream, info, handler)
+	}
+	logged := func(srv interface{}, stream grpc.ServerStream) error {
+		return StreamLoggerInterceptor(logger.NewMock())(srv, stream, info, authenticated)
+	}
+
+	err = StreamTracingInterceptor()(nil, ss, info, logged)
+	require.NoError(t, err)
+
+	require.Equal(t, traceID, tracing.TraceID(ctx))
+	require.NotEmpty(t, middleware.GetReqID(ctx))
+	userID, ok := auth.UserID(ctx)
+	require.True(t, ok)
+	require.Equal(t, int64(2), userID)
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	cases := []struct {
+		fullMethod string
+		service    string
+		method     string
+	}{
+		{fullMethod: "/calendar.Calendar/GetEvent", service: "calendar.Calendar", method: "GetEvent"},
+		{fullMethod: "GetEvent", method: "GetEvent"},
+	}
+
+	for _, tc := range cases {
+		service, method := splitFullMethod(tc.fullMethod)
+		require.Equal(t, tc.service, service)
+		require.Equal(t, tc.method, method)
+	}
+}
@@ -6,26 +6,51 @@ import (
 
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 )
 
 type Server struct {
 	srv *grpc.Server
 	calendarpb.UnimplementedCalendarServer
-	app server.Calendar
-	log logger.ILogger
+	app    server.Calendar
+	health server.HealthChecker
+	log    logger.ILogger
+	// stop ends the running streams, so the server can stop gracefully.
+	stop chan struct{}
 }
 
-func NewServer(logger logger.ILogger, app server.Calendar, cfg *config.ServerGRPCConfig) *Server {
+// NewServer creates the gRPC server of the calendar API. Requests are not
+// instrumented if metrics is nil. Streams are not counted in the metrics, their
+// duration is not a latency. The grpc.health.v1 service is not served if
+// health is nil.
+func NewServer(logger logger.ILogger, app server.Calendar, authenticator server.Authenticator,
+	metrics *metrics.Metrics, health server.HealthChecker, cfg *config.ServerGRPCConfig,
+) *Server {
 	var serverOptions []grpc.ServerOption
 	if cfg != nil {
+		interceptors := []grpc.UnaryServerInterceptor{
+			UnaryTracingInterceptor(),
+			UnaryLoggerInterceptor(logger),
+		}
+		if metrics != nil {
+			interceptors = append(interceptors, UnaryMetricsInterceptor(metrics))
+		}
+		interceptors = append(interceptors, UnaryAuthInterceptor(logger, authenticator))
+
 		serverOptions = []grpc.ServerOption{
 			grpc.Creds(insecure.NewCredentials()),
-			grpc.UnaryInterceptor(UnaryLoggerInterceptor(logger)),
+			grpc.ChainUnaryInterceptor(interceptors...),
+			grpc.ChainStreamInterceptor(
+				StreamTracingInterceptor(),
+				StreamLoggerInterceptor(logger),
+				StreamAuthInterceptor(logger, authenticator),
+			),
 			grpc.KeepaliveParams(keepalive.ServerParameters{
 				MaxConnectionIdle: cfg.MaxConnectionIdle,
 				MaxConnectionAge:  cfg.MaxConnectionAge,
@@ -38,9 +63,11 @@ func NewServer(logger logger.ILogger, app server.Calendar, cfg *config.ServerGRP
 	srv := grpc.NewServer(serverOptions...)
 
 	return &Server{
-		srv: srv,
-		app: app,
-		log: logger,
+		srv:    srv,
+		app:    app,
+		health: health,
+		log:    logger,
+		stop:   make(chan struct{}),
 	}
 }
 
@@ -51,9 +78,13 @@ func (s *Server) Start(cfg *config.ServerGRPCConfig) error {
 	}
 
 	calendarpb.RegisterCalendarServer(s.srv, s)
+	if s.health != nil {
+		healthpb.RegisterHealthServer(s.srv, &healthServer{health: s.health, log: s.log})
+	}
 	return s.srv.Serve(lsn)
 }
 
 func (s *Server) Stop() {
+	close(s.stop)
 	s.srv.GracefulStop()
 }
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WatchEvents sends the changes of the events the user can read until the
+// client cancels the stream. A client that does not keep up with the changes
+// gets RESOURCE_EXHAUSTED, the stream ends with UNAVAILABLE when the server
+// stops.
+func (s *Server) WatchEvents(_ *calendarpb.WatchEventsRequest, stream calendarpb.Calendar_WatchEventsServer) error {
+	ctx := stream.Context()
+	log := server.RequestLogger(ctx, s.log)
+
+	userID, err := requestUserID(ctx)
+	if err != nil {
+		log.Error("Authenticate request", "error", err)
+		return err
+	}
+
+	sub := s.app.WatchEvents(userID)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+
+		case <-s.stop:
+			return status.Error(codes.Unavailable, "server is stopping")
+
+		case change, ok := <-sub.Changes():
+			if !ok {
+				// Only the feed closes the subscription, the subscriber is too
+				// slow.
+				err := sub.Err()
+				log.Error("Watch events", "user_id", userID, "error", err)
+				return status.Error(codes.ResourceExhausted, err.Error())
+			}
+
+			if err := stream.Send(toProtoEventChange(&change)); err != nil {
+				log.Error("Send event change", "user_id", userID, "error", err)
+				return err
+			}
+		}
+	}
+}
+
+func toProtoEventChange(change *models.EventChange) *calendarpb.EventChange {
+	return &calendarpb.EventChange{
+		Type:      string(change.Type),
+		Event:     toProtoEvent(&change.Event),
+		ChangedAt: timestamppb.New(change.ChangedAt),
+	}
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/feed"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWatchEvents(t *testing.T) {
+	changedAt := time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC)
+	change := func(changeType models.ChangeType, eventID string) models.EventChange {
+		return models.EventChange{
+			Type:      changeType,
+			Event:     models.Event{ID: eventID, UserID: testUserID},
+			UserIDs:   []int64{testUserID},
+			ChangedAt: changedAt,
+		}
+	}
+
+	t.Run("changes are streamed", func(t *testing.T) {
+		appMock, client, closeConn := startServer(t)
+		defer closeConn()
+
+		changes := feed.New(10)
+		appMock.On("WatchEvents", int64(testUserID)).Return(changes.Subscribe(testUserID)).Once()
+
+		ctx, cancel := context.WithCancel(withUser(context.Background(), testUserID))
+		defer cancel()
+
+		stream, err := client.WatchEvents(ctx, &calendarpb.WatchEventsRequest{})
+		require.NoError(t, err)
+
+		changes.Publish(change(models.ChangeCreated, "event-1"))
+		changes.Publish(change(models.ChangeDeleted, "event-1"))
+
+		for _, changeType := range []models.ChangeType{models.ChangeCreated, models.ChangeDeleted} {
+			got, err := stream.Recv()
+			require.NoError(t, err)
+			require.Equal(t, string(changeType), got.Type)
+			require.Equal(t, "event-1", got.Event.Id)
+			require.Equal(t, changedAt, got.ChangedAt.AsTime())
+		}
+	})
+
+	t.Run("slow client", func(t *testing.T) {
+		appMock, client, closeConn := startServer(t)
+		defer closeConn()
+
+		changes := feed.New(1)
+		appMock.On("WatchEvents", int64(testUserID)).Return(changes.Subscribe(testUserID)).Once()
+
+		// The second change does not fit into the buffer of the subscription.
+		changes.Publish(change(models.ChangeCreated, "event-1"))
+		changes.Publish(change(models.ChangeCreated, "event-2"))
+
+		stream, err := client.WatchEvents(withUser(context.Background(), testUserID), &calendarpb.WatchEventsRequest{})
+		require.NoError(t, err)
+
+		got, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, "event-1", got.Event.Id)
+
+		_, err = stream.Recv()
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, client, closeConn := startServer(t)
+		defer closeConn()
+
+		stream, err := client.WatchEvents(context.Background(), &calendarpb.WatchEventsRequest{})
+		require.NoError(t, err)
+
+		_, err = stream.Recv()
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
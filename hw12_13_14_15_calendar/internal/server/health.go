@@ -0,0 +1,8 @@
+package server
+
+import "context"
+
+// HealthChecker reports whether the service is ready to handle requests.
+type HealthChecker interface {
+	Ready(ctx context.Context) error
+}
@@ -0,0 +1,58 @@
+package internalhttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+)
+
+type Invitation struct {
+	Event  Event                 `json:"event"`
+	Status models.AttendeeStatus `json:"status"`
+}
+
+func (h *Handler) getInvitations() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		status := models.AttendeeStatus(r.URL.Query().Get("status"))
+		if status != "" && !status.Valid() {
+			err := fmt.Errorf("field status is invalid: %q", status)
+			log.Error("Validate request", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		invitations, err := h.app.GetInvitations(r.Context(), userID, status)
+		if err != nil {
+			log.Error("Can not get invitations", "user_id", userID, "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		response := make([]Invitation, len(invitations))
+		for i := range invitations {
+			response[i] = Invitation{
+				Event:  toEventResponse(&invitations[i].Event),
+				Status: invitations[i].Status,
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, response)
+	}
+}
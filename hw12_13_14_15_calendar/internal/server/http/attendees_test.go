@@ -0,0 +1,147 @@
+package internalhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      map[string]interface{}
+		status    models.AttendeeStatus
+		code      int
+		mockError error
+	}{
+		{
+			name:   "success",
+			body:   map[string]interface{}{"status": "accepted"},
+			status: models.StatusAccepted,
+			code:   http.StatusOK,
+		},
+		{
+			name: "invalid status",
+			body: map[string]interface{}{"status": "maybe"},
+			code: http.StatusBadRequest,
+		},
+		{
+			name:      "not invited",
+			body:      map[string]interface{}{"status": "declined"},
+			status:    models.StatusDeclined,
+			mockError: storage.ErrInvitationNotExist,
+			code:      http.StatusNotFound,
+		},
+		{
+			name:      "respond error",
+			body:      map[string]interface{}{"status": "tentative"},
+			status:    models.StatusTentative,
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			if tc.status != "" {
+				appMock.On("RespondToInvitation", mock.Anything, int64(testUserID), "id-1", tc.status).
+					Return(tc.mockError).
+					Once()
+			}
+
+			handler := eventsHandler(t, appMock)
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodPut,
+				eventsURL+"/id-1/rsvp", bytes.NewReader(body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+		})
+	}
+}
+
+func TestInviteHandler(t *testing.T) {
+	appMock := mocks.NewCalendar(t)
+	appMock.On("InviteAttendees", mock.Anything, int64(testUserID), "id-1", []int64{2, 3}).
+		Return(nil).
+		Once()
+
+	handler := eventsHandler(t, appMock)
+
+	for body, code := range map[string]int{
+		`{"userIds":[2,3]}`: http.StatusOK,
+		`{"userIds":[]}`:    http.StatusBadRequest,
+	} {
+		req, err := http.NewRequestWithContext(userContext(), http.MethodPost,
+			eventsURL+"/id-1/attendees", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		require.Equal(t, code, rr.Code, body)
+	}
+}
+
+func TestGetInvitationsHandler(t *testing.T) {
+	event := models.Event{
+		ID:        "id-1",
+		Title:     "test",
+		UserID:    2,
+		StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+	}
+
+	appMock := mocks.NewCalendar(t)
+	appMock.On("GetInvitations", mock.Anything, int64(testUserID), models.StatusAccepted).
+		Return([]models.Invitation{{Event: event, Status: models.StatusAccepted}}, nil).
+		Once()
+
+	handler := chi.NewRouter()
+	handler.Get(invitationsURL, NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil).getInvitations())
+
+	req, err := http.NewRequestWithContext(userContext(), http.MethodGet, invitationsURL+"?status=accepted", nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var responseBody []Invitation
+	err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+	require.NoError(t, err)
+	require.Equal(t, []Invitation{{Event: toEventResponse(&event), Status: models.StatusAccepted}}, responseBody)
+
+	req, err = http.NewRequestWithContext(userContext(), http.MethodGet, invitationsURL+"?status=maybe", nil)
+	require.NoError(t, err)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,351 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+)
+
+type CalendarRequest struct {
+	Name     string `json:"name"`
+	Timezone string `json:"timezone"`
+}
+
+type CreateCalendarResponse struct {
+	CalendarID string `json:"id"`
+}
+
+type Calendar struct {
+	ID       string      `json:"id"`
+	OwnerID  int64       `json:"ownerId"`
+	Name     string      `json:"name"`
+	Timezone string      `json:"timezone"`
+	Role     models.Role `json:"role"`
+}
+
+type ACLEntryRequest struct {
+	Role models.Role `json:"role"`
+}
+
+type ACLEntry struct {
+	UserID int64       `json:"userId"`
+	Role   models.Role `json:"role"`
+}
+
+func (h *Handler) createCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		var calendar CalendarRequest
+		if err := parseBody(r, &calendar); err != nil {
+			log.Error("Parse request body", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if len(calendar.Name) == 0 {
+			err = errors.New("field name is empty")
+		} else {
+			err = validateTimezone(calendar.Timezone)
+		}
+		if err != nil {
+			log.Error("Validate calendar", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		calendarID, err := h.app.CreateCalendar(r.Context(), &models.Calendar{
+			OwnerID:  userID,
+			Name:     calendar.Name,
+			Timezone: calendar.Timezone,
+		})
+		if err != nil {
+			log.Error("Create calendar", "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, CreateCalendarResponse{CalendarID: calendarID})
+	}
+}
+
+func (h *Handler) getCalendars() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		calendars, err := h.app.GetCalendars(r.Context(), userID)
+		if err != nil {
+			log.Error("Can not get calendars", "user_id", userID, "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		response := make([]Calendar, len(calendars))
+		for i := range calendars {
+			response[i] = toCalendarResponse(&calendars[i])
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, response)
+	}
+}
+
+func (h *Handler) getCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		calendar, err := h.app.GetCalendar(r.Context(), userID, parseID(r))
+		if err != nil {
+			log.Error("Get calendar", "calendar_id", parseID(r), "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, toCalendarResponse(calendar))
+	}
+}
+
+func (h *Handler) updateCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		var calendar CalendarRequest
+		if err := parseBody(r, &calendar); err != nil {
+			log.Error("Parse request body", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if err := validateTimezone(calendar.Timezone); err != nil {
+			log.Error("Validate calendar", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		err = h.app.UpdateCalendar(r.Context(), userID, &models.Calendar{
+			ID:       parseID(r),
+			Name:     calendar.Name,
+			Timezone: calendar.Timezone,
+		})
+		if err != nil {
+			log.Error("Update calendar", "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *Handler) deleteCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if err := h.app.DeleteCalendar(r.Context(), userID, parseID(r)); err != nil {
+			log.Error("Delete calendar", "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) getACL() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		entries, err := h.app.GetACL(r.Context(), userID, parseID(r))
+		if err != nil {
+			log.Error("Can not get calendar ACL", "calendar_id", parseID(r), "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		response := make([]ACLEntry, len(entries))
+		for i := range entries {
+			response[i] = ACLEntry{UserID: entries[i].UserID, Role: entries[i].Role}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, response)
+	}
+}
+
+func (h *Handler) setACLEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		memberID, err := parseMemberID(r)
+		if err != nil {
+			log.Error("Validate ACL entry", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		var entry ACLEntryRequest
+		if err := parseBody(r, &entry); err != nil {
+			log.Error("Parse request body", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if !entry.Role.Valid() {
+			err := fmt.Errorf("field role is invalid: %q", entry.Role)
+			log.Error("Validate ACL entry", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		err = h.app.SetACLEntry(r.Context(), userID, models.ACLEntry{
+			CalendarID: parseID(r),
+			UserID:     memberID,
+			Role:       entry.Role,
+		})
+		if err != nil {
+			log.Error("Set ACL entry", "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *Handler) deleteACLEntry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		memberID, err := parseMemberID(r)
+		if err != nil {
+			log.Error("Validate ACL entry", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if err := h.app.DeleteACLEntry(r.Context(), userID, parseID(r), memberID); err != nil {
+			log.Error("Delete ACL entry", "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseMemberID(r *http.Request) (int64, error) {
+	memberID, err := strconv.ParseInt(chi.URLParam(r, "userId"), 10, 64)
+	if err != nil || memberID == 0 {
+		return 0, errors.New("invalid user id")
+	}
+	return memberID, nil
+}
+
+func validateTimezone(timezone string) error {
+	if timezone == "" {
+		return nil
+	}
+	if _, err := models.LoadTimezone(timezone); err != nil {
+		return fmt.Errorf("field timezone is invalid: %w", err)
+	}
+	return nil
+}
+
+func toCalendarResponse(calendar *models.Calendar) Calendar {
+	return Calendar{
+		ID:       calendar.ID,
+		OwnerID:  calendar.OwnerID,
+		Name:     calendar.Name,
+		Timezone: calendar.Timezone,
+		Role:     calendar.Role,
+	}
+}
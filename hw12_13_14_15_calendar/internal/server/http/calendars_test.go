@@ -0,0 +1,230 @@
+package internalhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCalendarHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      map[string]interface{}
+		calendar  *models.Calendar
+		code      int
+		mockError error
+	}{
+		{
+			name:     "success",
+			body:     map[string]interface{}{"name": "team", "timezone": "Europe/Moscow"},
+			calendar: &models.Calendar{OwnerID: testUserID, Name: "team", Timezone: "Europe/Moscow"},
+			code:     http.StatusCreated,
+		},
+		{
+			name: "empty name",
+			body: map[string]interface{}{"timezone": "UTC"},
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid timezone",
+			body: map[string]interface{}{"name": "team", "timezone": "Mars/Olympus"},
+			code: http.StatusBadRequest,
+		},
+		{
+			name:      "create calendar error",
+			body:      map[string]interface{}{"name": "team"},
+			calendar:  &models.Calendar{OwnerID: testUserID, Name: "team"},
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			if tc.calendar != nil {
+				appMock.On("CreateCalendar", mock.Anything, tc.calendar).
+					Return("calendar-1", tc.mockError).
+					Once()
+			}
+
+			handler := chi.NewRouter()
+			handler.Post(calendarsURL, NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil).createCalendar())
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodPost, calendarsURL, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.code == http.StatusCreated {
+				var responseBody CreateCalendarResponse
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				require.Equal(t, "calendar-1", responseBody.CalendarID)
+			}
+		})
+	}
+}
+
+func TestGetCalendarHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		calendarID string
+		calendar   *models.Calendar
+		code       int
+		mockError  error
+	}{
+		{
+			name:       "success",
+			calendarID: "calendar-1",
+			calendar: &models.Calendar{
+				ID:       "calendar-1",
+				OwnerID:  2,
+				Name:     "team",
+				Timezone: "UTC",
+				Role:     models.RoleReader,
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:       "not existing calendar",
+			calendarID: "calendar-2",
+			mockError:  storage.ErrCalendarNotExist,
+			code:       http.StatusNotFound,
+		},
+		{
+			name:       "calendar of another user",
+			calendarID: "calendar-3",
+			mockError:  storage.ErrForbidden,
+			code:       http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			appMock.On("GetCalendar", mock.Anything, int64(testUserID), tc.calendarID).
+				Return(tc.calendar, tc.mockError).
+				Once()
+
+			handler := chi.NewRouter()
+			handler.Get(calendarsURL+"/{id}", NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil).getCalendar())
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet,
+				calendarsURL+"/"+tc.calendarID, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.calendar != nil {
+				var responseBody Calendar
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				require.Equal(t, toCalendarResponse(tc.calendar), responseBody)
+			}
+		})
+	}
+}
+
+func TestSetACLEntryHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		memberID  string
+		body      map[string]interface{}
+		entry     *models.ACLEntry
+		code      int
+		mockError error
+	}{
+		{
+			name:     "success",
+			memberID: "2",
+			body:     map[string]interface{}{"role": "writer"},
+			entry:    &models.ACLEntry{CalendarID: "calendar-1", UserID: 2, Role: models.RoleWriter},
+			code:     http.StatusOK,
+		},
+		{
+			name:     "invalid user id",
+			memberID: "abc",
+			body:     map[string]interface{}{"role": "writer"},
+			code:     http.StatusBadRequest,
+		},
+		{
+			name:     "invalid role",
+			memberID: "2",
+			body:     map[string]interface{}{"role": "admin"},
+			code:     http.StatusBadRequest,
+		},
+		{
+			name:      "not an owner",
+			memberID:  "2",
+			body:      map[string]interface{}{"role": "reader"},
+			entry:     &models.ACLEntry{CalendarID: "calendar-1", UserID: 2, Role: models.RoleReader},
+			mockError: storage.ErrForbidden,
+			code:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			if tc.entry != nil {
+				appMock.On("SetACLEntry", mock.Anything, int64(testUserID), *tc.entry).
+					Return(tc.mockError).
+					Once()
+			}
+
+			handler := chi.NewRouter()
+			handler.Put(calendarsURL+"/{id}/acl/{userId}",
+				NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil).setACLEntry())
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodPut,
+				calendarsURL+"/calendar-1/acl/"+tc.memberID, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+		})
+	}
+}
@@ -1,279 +1,81 @@
 package internalhttp
 
 import (
-	"errors"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/render"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
 	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
-	"golang.org/x/exp/slog"
 )
 
-type CreateRequest struct {
-	Title            string         `json:"title"`
-	Description      *string        `json:"description"`
-	UserID           int64          `json:"userId"`
-	StartDate        time.Time      `json:"startDate"`
-	EndDate          time.Time      `json:"endDate"`
-	NotificationTime *time.Duration `json:"notificationTime"`
-}
-
-type CreateResponse struct {
-	EventID string `json:"id"`
-}
-
-func (h *Handler) createEvent() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log := h.log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
-
-		var event CreateRequest
-		if err := parseBody(r, &event); err != nil {
-			log.Error("Parse request body", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		if err := event.validate(); err != nil {
-			log.Error("Validate event", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		eventID, err := h.app.CreateEvent(r.Context(), event.toModel())
-		if err != nil {
-			log.Error("Create event", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		w.WriteHeader(http.StatusCreated)
-		render.JSON(w, r, CreateResponse{EventID: eventID})
-	}
-}
-
-func (r *CreateRequest) validate() error {
-	if len(r.Title) == 0 {
-		return errors.New("field title is empty")
-	}
-	if r.UserID == 0 {
-		return errors.New("field userId is empty")
-	}
-	if r.StartDate.IsZero() {
-		return errors.New("field startDate is empty")
-	}
-	if r.EndDate.IsZero() {
-		return errors.New("field endDate is empty")
-	}
-	return nil
-}
-
-func (r *CreateRequest) toModel() *models.Event {
-	return &models.Event{
-		Title:            r.Title,
-		Description:      r.Description,
-		UserID:           r.UserID,
-		StartDate:        r.StartDate,
-		EndDate:          r.EndDate,
-		NotificationTime: r.NotificationTime,
-	}
-}
-
 type Event struct {
 	ID               string         `json:"id"`
 	Title            string         `json:"title"`
 	Description      *string        `json:"description"`
 	UserID           int64          `json:"userId"`
+	CalendarID       *string        `json:"calendarId,omitempty"`
 	StartDate        time.Time      `json:"startDate"`
 	EndDate          time.Time      `json:"endDate"`
 	NotificationTime *time.Duration `json:"notificationTime"`
-}
-
-func (h *Handler) updateEvent() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log := h.log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
-
-		eventID := parseID(r)
-
-		var event Event
-		if err := parseBody(r, &event); err != nil {
-			log.Error("Parse request body", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		event.ID = eventID
-		if err := h.app.UpdateEvent(r.Context(), event.toModel()); err != nil {
-			log.Error("Update event", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-	}
-}
-
-func (r *Event) toModel() *models.Event {
-	return &models.Event{
-		ID:               r.ID,
-		Title:            r.Title,
-		Description:      r.Description,
-		UserID:           r.UserID,
-		StartDate:        r.StartDate,
-		EndDate:          r.EndDate,
-		NotificationTime: r.NotificationTime,
-	}
+	RecurrenceRule   *string        `json:"recurrenceRule"`
+	ExceptionDates   []time.Time    `json:"exceptionDates"`
+	AllowOverlap     bool           `json:"allowOverlap,omitempty"`
+	Timezone         string         `json:"timezone,omitempty"`
+	AllDay           *bool          `json:"allDay,omitempty"`
+	// Version is ignored by updates, the expected version is taken from the
+	// If-Match header.
+	Version int64 `json:"version,omitempty"`
+	// DeletedAt is set for events in the trash. It is ignored by updates.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 type StartDate time.Time
 
+// nextPageTokenHeader carries the token of the next page of an event list.
+// It is not set on the last page.
+const nextPageTokenHeader = "X-Next-Page-Token"
+
+// GetByDateRequest is the JSON body of the day, week and month requests.
 type GetByDateRequest struct {
-	UserID int64     `json:"userId"`
-	Date   StartDate `json:"startDate"`
+	Date             StartDate `json:"startDate"`
+	PageSize         int       `json:"pageSize"`
+	PageToken        string    `json:"pageToken"`
+	Title            string    `json:"title"`
+	WithNotification bool      `json:"withNotification"`
+	// Timezone is the IANA time zone the date is taken in, UTC by default.
+	Timezone string `json:"tz"`
 }
 
 type EventsResponse []Event
 
-//nolint:dupl
-func (h *Handler) getEventsByDay() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log := h.log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
-
-		var request GetByDateRequest
-		if err := parseBody(r, &request); err != nil {
-			log.Error("Parse request body", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		if err := request.validate(); err != nil {
-			log.Error("Validate get by day request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		events, err := h.app.GetEventByDay(r.Context(), request.UserID, time.Time(request.Date))
-		if err != nil {
-			log.Error("Can not get events for the selected day",
-				"user_id", request.UserID,
-				"day", time.Time(request.Date),
-				"error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, toResponse(events))
-	}
-}
-
-//nolint:dupl
-func (h *Handler) getEventsByWeek() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log := h.log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
-
-		var request GetByDateRequest
-		if err := parseBody(r, &request); err != nil {
-			log.Error("Parse request body", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		if err := request.validate(); err != nil {
-			log.Error("Validate get by week request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		events, err := h.app.GetEventByWeek(r.Context(), request.UserID, time.Time(request.Date))
-		if err != nil {
-			log.Error("Can not get events for the selected week",
-				"user_id", request.UserID,
-				"week", time.Time(request.Date),
-				"error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, toResponse(events))
-	}
-}
-
-//nolint:dupl
-func (h *Handler) getEventsByMonth() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		log := h.log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
-
-		var request GetByDateRequest
-		if err := parseBody(r, &request); err != nil {
-			log.Error("Parse request body", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		if err := request.validate(); err != nil {
-			log.Error("Validate get by month request", "error", err)
-			w.WriteHeader(http.StatusBadRequest)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		events, err := h.app.GetEventByMonth(r.Context(), request.UserID, time.Time(request.Date))
-		if err != nil {
-			log.Error("Can not get events for the selected month",
-				"user_id", request.UserID,
-				"month", time.Time(request.Date),
-				"error", err)
-			w.WriteHeader(http.StatusInternalServerError)
-			render.JSON(w, r, resp.Error(err.Error()))
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-		render.JSON(w, r, toResponse(events))
-	}
-}
-
 func toResponse(events []models.Event) EventsResponse {
 	resp := make(EventsResponse, len(events))
 	for i := range events {
-		resp[i] = Event{
-			ID:               events[i].ID,
-			Title:            events[i].Title,
-			Description:      events[i].Description,
-			UserID:           events[i].UserID,
-			StartDate:        events[i].StartDate,
-			EndDate:          events[i].EndDate,
-			NotificationTime: events[i].NotificationTime,
-		}
+		resp[i] = toEventResponse(&events[i])
 	}
 	return resp
 }
 
-func (r *GetByDateRequest) validate() error {
-	if r.UserID == 0 {
-		return errors.New("field userId is empty")
+func toEventResponse(event *models.Event) Event {
+	return Event{
+		ID:               event.ID,
+		Title:            event.Title,
+		Description:      event.Description,
+		UserID:           event.UserID,
+		CalendarID:       event.CalendarID,
+		StartDate:        event.StartDate,
+		EndDate:          event.EndDate,
+		NotificationTime: event.NotificationTime,
+		RecurrenceRule:   event.RecurrenceRule,
+		ExceptionDates:   event.ExceptionDates,
+		Timezone:         event.Timezone,
+		AllDay:           event.AllDay,
+		Version:          event.Version,
+		DeletedAt:        event.DeletedAt,
 	}
-	if time.Time(r.Date).IsZero() {
-		return errors.New("field startDate is empty")
-	}
-	return nil
 }
 
 func (sd *StartDate) UnmarshalJSON(b []byte) error {
@@ -290,17 +92,39 @@ func (sd *StartDate) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (h *Handler) deleteEvent() http.HandlerFunc {
+func (h *Handler) getDeletedEvents() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		log := h.log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
 
-		if err := h.app.DeleteEvent(r.Context(), parseID(r)); err != nil {
-			log.Error("Delete event", "error", err)
-			w.WriteHeader(http.StatusInternalServerError)
+		events, err := h.app.GetDeletedEvents(r.Context(), userID)
+		if err != nil {
+			log.Error("Can not get deleted events", "user_id", userID, "error", err)
+			w.WriteHeader(errorStatus(err))
 			render.JSON(w, r, resp.Error(err.Error()))
 			return
 		}
 
-		w.WriteHeader(http.StatusNoContent)
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, toResponse(events))
 	}
 }
+
+// HistoryEntry is a change of an event. Before is empty for a created event.
+type HistoryEntry struct {
+	ID        int64                `json:"id"`
+	EventID   string               `json:"eventId"`
+	Action    models.HistoryAction `json:"action"`
+	ActorID   int64                `json:"actorId"`
+	RequestID string               `json:"requestId,omitempty"`
+	Before    *Event               `json:"before,omitempty"`
+	After     *Event               `json:"after,omitempty"`
+	ChangedAt time.Time            `json:"changedAt"`
+}
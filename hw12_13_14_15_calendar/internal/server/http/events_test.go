@@ -7,22 +7,34 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	internalgrpc "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/grpc"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+const testUserID = 1
+
+// userContext returns a context authenticated as testUserID, as WithAuth
+// leaves it for the handlers.
+func userContext() context.Context {
+	return auth.WithUserID(context.Background(), testUserID)
+}
+
 func TestCreateHandler(t *testing.T) {
 	cases := []struct {
 		name      string
-		event     CreateRequest
+		event     models.Event
 		body      map[string]interface{}
 		code      int
 		respError string
@@ -30,17 +42,15 @@ func TestCreateHandler(t *testing.T) {
 	}{
 		{
 			name: "success",
-			event: CreateRequest{
+			event: models.Event{
 				Title:            "test",
 				Description:      nil,
-				UserID:           1,
 				StartDate:        time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
 				EndDate:          time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
 				NotificationTime: nil,
 			},
 			body: map[string]interface{}{
 				"title":     "test",
-				"userId":    1,
 				"startDate": "2023-08-16T12:00:00Z",
 				"endDate":   "2023-08-16T13:00:00Z",
 			},
@@ -55,7 +65,6 @@ func TestCreateHandler(t *testing.T) {
 		{
 			name: "empty title",
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-16T12:00:00Z",
 				"endDate":   "2023-08-16T13:00:00Z",
 			},
@@ -63,54 +72,184 @@ func TestCreateHandler(t *testing.T) {
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty userId",
+			name: "empty startDate",
+			body: map[string]interface{}{
+				"title":   "test",
+				"endDate": "2023-08-16T13:00:00Z",
+			},
+			respError: "field startDate is empty",
+			code:      http.StatusBadRequest,
+		},
+		{
+			name: "empty endDate",
 			body: map[string]interface{}{
 				"title":     "test",
 				"startDate": "2023-08-16T12:00:00Z",
-				"endDate":   "2023-08-16T13:00:00Z",
 			},
-			respError: "field userId is empty",
+			respError: "field endDate is empty",
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty startDate",
+			name: "too long event",
 			body: map[string]interface{}{
-				"title":   "test",
-				"userId":  1,
-				"endDate": "2023-08-16T13:00:00Z",
+				"title":     "test",
+				"startDate": "2023-08-16T12:00:00Z",
+				"endDate":   "2123-08-16T12:00:00Z",
 			},
-			respError: "field startDate is empty",
+			respError: "event must last at most 366 days",
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty endDate",
+			name: "invalid recurrenceRule",
+			body: map[string]interface{}{
+				"title":          "test",
+				"startDate":      "2023-08-16T12:00:00Z",
+				"endDate":        "2023-08-16T13:00:00Z",
+				"recurrenceRule": "FREQ=HOURLY",
+			},
+			respError: `field recurrenceRule is invalid: unsupported FREQ "HOURLY"`,
+			code:      http.StatusBadRequest,
+		},
+		{
+			name: "time zone",
+			event: models.Event{
+				Title:     "test",
+				StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+				Timezone:  "Australia/Sydney",
+			},
+			body: map[string]interface{}{
+				"title":     "test",
+				"startDate": "2023-08-16T12:00:00Z",
+				"endDate":   "2023-08-16T13:00:00Z",
+				"timezone":  "Australia/Sydney",
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name: "all day",
+			event: models.Event{
+				Title:     "test",
+				StartDate: time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC),
+				AllDay:    boolPtr(true),
+			},
+			body: map[string]interface{}{
+				"title":     "test",
+				"startDate": "2023-08-16T00:00:00Z",
+				"endDate":   "2023-08-18T00:00:00Z",
+				"allDay":    true,
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name: "notification time in nanoseconds",
+			event: models.Event{
+				Title:            "test",
+				StartDate:        time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+				EndDate:          time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+				NotificationTime: durationPtr(5 * time.Minute),
+			},
+			body: map[string]interface{}{
+				"title":            "test",
+				"startDate":        "2023-08-16T12:00:00Z",
+				"endDate":          "2023-08-16T13:00:00Z",
+				"notificationTime": int64(5 * time.Minute),
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name: "invalid time zone",
 			body: map[string]interface{}{
 				"title":     "test",
-				"userId":    1,
 				"startDate": "2023-08-16T12:00:00Z",
+				"endDate":   "2023-08-16T13:00:00Z",
+				"timezone":  "Mars/Olympus",
 			},
-			respError: "field endDate is empty",
+			respError: "field timezone is invalid: unknown time zone Mars/Olympus",
 			code:      http.StatusBadRequest,
 		},
 		{
 			name: "create event error",
-			event: CreateRequest{
+			event: models.Event{
 				Title:            "test",
 				Description:      nil,
-				UserID:           1,
 				StartDate:        time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
 				EndDate:          time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
 				NotificationTime: nil,
 			},
 			body: map[string]interface{}{
 				"title":     "test",
-				"userId":    1,
 				"startDate": "2023-08-16T12:00:00Z",
 				"endDate":   "2023-08-16T13:00:00Z",
 			},
 			mockError: errors.New("unexpected error"),
 			code:      http.StatusInternalServerError,
 		},
+		{
+			name: "date is busy",
+			event: models.Event{
+				Title:     "test",
+				StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+			},
+			body: map[string]interface{}{
+				"title":     "test",
+				"startDate": "2023-08-16T12:00:00Z",
+				"endDate":   "2023-08-16T13:00:00Z",
+			},
+			mockError: &storage.ConflictError{EventID: "id-1"},
+			code:      http.StatusConflict,
+		},
+		{
+			name: "overlap allowed",
+			event: models.Event{
+				Title:        "test",
+				StartDate:    time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+				EndDate:      time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+				AllowOverlap: true,
+			},
+			body: map[string]interface{}{
+				"title":        "test",
+				"startDate":    "2023-08-16T12:00:00Z",
+				"endDate":      "2023-08-16T13:00:00Z",
+				"allowOverlap": true,
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name: "shared calendar",
+			event: models.Event{
+				Title:      "test",
+				StartDate:  time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+				EndDate:    time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+				CalendarID: stringPtr("calendar-1"),
+			},
+			body: map[string]interface{}{
+				"title":      "test",
+				"startDate":  "2023-08-16T12:00:00Z",
+				"endDate":    "2023-08-16T13:00:00Z",
+				"calendarId": "calendar-1",
+			},
+			code: http.StatusCreated,
+		},
+		{
+			name: "calendar does not exist",
+			event: models.Event{
+				Title:      "test",
+				StartDate:  time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+				EndDate:    time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+				CalendarID: stringPtr("calendar-2"),
+			},
+			body: map[string]interface{}{
+				"title":      "test",
+				"startDate":  "2023-08-16T12:00:00Z",
+				"endDate":    "2023-08-16T13:00:00Z",
+				"calendarId": "calendar-2",
+			},
+			mockError: storage.ErrCalendarNotExist,
+			code:      http.StatusNotFound,
+		},
 	}
 
 	for _, tc := range cases {
@@ -122,13 +261,17 @@ func TestCreateHandler(t *testing.T) {
 			appMock := mocks.NewCalendar(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				appMock.On("CreateEvent", mock.Anything, tc.event.toModel()).
-					Return(mock.Anything, tc.mockError).
+				event := tc.event
+				event.UserID = testUserID
+				appMock.On("CreateEvent", mock.Anything, &event).
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*models.Event).Version = 1
+					}).
+					Return("id-1", tc.mockError).
 					Once()
 			}
 
-			handler := chi.NewRouter()
-			handler.Post(eventsURL, NewHandler(logger.NewMock(), appMock).createEvent())
+			handler := eventsHandler(t, appMock)
 
 			var body []byte
 			var err error
@@ -137,7 +280,7 @@ func TestCreateHandler(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, eventsURL, bytes.NewReader(body))
+			req, err := http.NewRequestWithContext(userContext(), http.MethodPost, eventsURL, bytes.NewReader(body))
 			require.NoError(t, err)
 
 			rr := httptest.NewRecorder()
@@ -145,12 +288,112 @@ func TestCreateHandler(t *testing.T) {
 
 			require.Equal(t, tc.code, rr.Code)
 
+			if tc.respError != "" {
+				require.JSONEq(t, `{"error":`+strconv.Quote(tc.respError)+`}`, rr.Body.String())
+			}
 			if tc.respError == "" && tc.mockError == nil {
-				var responseBody CreateResponse
+				require.JSONEq(t, `{"id":"id-1"}`, rr.Body.String())
+				require.Equal(t, `"1"`, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestGetHandler(t *testing.T) {
+	event := &models.Event{
+		ID:               "id-1",
+		Title:            "test",
+		UserID:           testUserID,
+		StartDate:        time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+		EndDate:          time.Date(2023, 8, 15, 13, 0, 0, 0, time.UTC),
+		NotificationTime: durationPtr(5 * time.Minute),
+		Version:          3,
+	}
+
+	cases := []struct {
+		name        string
+		ifNoneMatch string
+		event       *models.Event
+		code        int
+		mockError   error
+	}{
+		{
+			name:  "success",
+			event: event,
+			code:  http.StatusOK,
+		},
+		{
+			name:        "not modified",
+			ifNoneMatch: `"3"`,
+			event:       event,
+			code:        http.StatusNotModified,
+		},
+		{
+			name:        "weak tag in a list",
+			ifNoneMatch: `"2", W/"3"`,
+			event:       event,
+			code:        http.StatusNotModified,
+		},
+		{
+			name:        "modified",
+			ifNoneMatch: `"2"`,
+			event:       event,
+			code:        http.StatusOK,
+		},
+		{
+			name:      "not existing event",
+			mockError: storage.ErrEventNotExist,
+			code:      http.StatusNotFound,
+		},
+		{
+			name:      "event of another user",
+			mockError: storage.ErrForbidden,
+			code:      http.StatusForbidden,
+		},
+		{
+			name:      "get event error",
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			appMock.On("GetEvent", mock.Anything, int64(testUserID), "id-1").
+				Return(tc.event, tc.mockError).
+				Once()
+
+			handler := eventsHandler(t, appMock)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet, eventsURL+"/id-1", nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			switch tc.code {
+			case http.StatusOK:
+				require.Equal(t, `"3"`, rr.Header().Get("ETag"))
+
+				var responseBody Event
 				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
 				require.NoError(t, err)
+				require.Equal(t, toEventResponse(tc.event), responseBody)
 
-				require.NotEqual(t, "", responseBody.EventID)
+			case http.StatusNotModified:
+				require.Equal(t, `"3"`, rr.Header().Get("ETag"))
+				require.Empty(t, rr.Body.Bytes())
 			}
 		})
 	}
@@ -159,15 +402,18 @@ func TestCreateHandler(t *testing.T) {
 func TestUpdateHandler(t *testing.T) {
 	cases := []struct {
 		name      string
-		event     Event
+		event     models.Event
 		body      map[string]interface{}
+		ifMatch   string
+		version   int64
+		etag      string
 		code      int
 		respError string
 		mockError error
 	}{
 		{
 			name: "success",
-			event: Event{
+			event: models.Event{
 				ID:    "id-1",
 				Title: "test",
 			},
@@ -177,9 +423,62 @@ func TestUpdateHandler(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+		{
+			name: "clear all day",
+			event: models.Event{
+				ID:     "id-1",
+				AllDay: boolPtr(false),
+			},
+			body: map[string]interface{}{
+				"id":     "id-1",
+				"allDay": false,
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "if match",
+			event: models.Event{
+				ID:    "id-1",
+				Title: "test",
+			},
+			body: map[string]interface{}{
+				"title": "test",
+			},
+			ifMatch: `"3"`,
+			version: 3,
+			etag:    `"4"`,
+			code:    http.StatusOK,
+		},
+		{
+			name: "version mismatch",
+			event: models.Event{
+				ID:    "id-1",
+				Title: "test",
+			},
+			body: map[string]interface{}{
+				"title": "test",
+			},
+			ifMatch:   `"2"`,
+			version:   2,
+			mockError: storage.ErrVersionMismatch,
+			code:      http.StatusPreconditionFailed,
+		},
+		{
+			name: "invalid if match",
+			event: models.Event{
+				ID:    "id-1",
+				Title: "test",
+			},
+			body: map[string]interface{}{
+				"title": "test",
+			},
+			ifMatch:   "2",
+			respError: "invalid If-Match header",
+			code:      http.StatusBadRequest,
+		},
 		{
 			name: "empty body",
-			event: Event{
+			event: models.Event{
 				ID:    "id-1",
 				Title: "test",
 			},
@@ -189,7 +488,7 @@ func TestUpdateHandler(t *testing.T) {
 		},
 		{
 			name: "update event error",
-			event: Event{
+			event: models.Event{
 				ID:    "id-1",
 				Title: "test",
 			},
@@ -200,6 +499,18 @@ func TestUpdateHandler(t *testing.T) {
 			mockError: errors.New("unexpected error"),
 			code:      http.StatusInternalServerError,
 		},
+		{
+			name: "event of another user",
+			event: models.Event{
+				ID:    "id-1",
+				Title: "test",
+			},
+			body: map[string]interface{}{
+				"title": "test",
+			},
+			mockError: storage.ErrForbidden,
+			code:      http.StatusForbidden,
+		},
 	}
 
 	for _, tc := range cases {
@@ -211,13 +522,17 @@ func TestUpdateHandler(t *testing.T) {
 			appMock := mocks.NewCalendar(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				appMock.On("UpdateEvent", mock.Anything, tc.event.toModel()).
+				event := tc.event
+				event.Version = tc.version
+				appMock.On("UpdateEvent", mock.Anything, int64(testUserID), &event).
+					Run(func(args mock.Arguments) {
+						args.Get(2).(*models.Event).Version++
+					}).
 					Return(tc.mockError).
 					Once()
 			}
 
-			handler := chi.NewRouter()
-			handler.Patch(eventsURL+"/{id}", NewHandler(logger.NewMock(), appMock).updateEvent())
+			handler := eventsHandler(t, appMock)
 
 			var body []byte
 			var err error
@@ -226,19 +541,28 @@ func TestUpdateHandler(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodPatch,
+			req, err := http.NewRequestWithContext(userContext(), http.MethodPatch,
 				eventsURL+"/"+tc.event.ID, bytes.NewReader(body))
 			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
 
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
 			require.Equal(t, tc.code, rr.Code)
+			if tc.etag != "" {
+				require.Equal(t, tc.etag, rr.Header().Get("ETag"))
+			}
 		})
 	}
 }
 
 func TestGetByDayHandler(t *testing.T) {
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
 	cases := []struct {
 		name      string
 		userID    int64
@@ -246,6 +570,7 @@ func TestGetByDayHandler(t *testing.T) {
 		request   GetByDateRequest
 		body      map[string]interface{}
 		events    []models.Event
+		nextPage  string
 		code      int
 		respError string
 		mockError error
@@ -255,7 +580,6 @@ func TestGetByDayHandler(t *testing.T) {
 			userID: 1,
 			day:    time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC),
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-16",
 			},
 			events: []models.Event{
@@ -273,6 +597,18 @@ func TestGetByDayHandler(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+		{
+			name:   "next page",
+			userID: 1,
+			day:    time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC),
+			body: map[string]interface{}{
+				"startDate": "2023-08-16",
+				"pageSize":  1,
+			},
+			events:   []models.Event{},
+			nextPage: "page-2",
+			code:     http.StatusOK,
+		},
 		{
 			name:      "empty body",
 			body:      nil,
@@ -280,28 +616,46 @@ func TestGetByDayHandler(t *testing.T) {
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty userId",
+			name:      "empty startDate",
+			body:      map[string]interface{}{},
+			respError: "json parse error",
+			code:      http.StatusBadRequest,
+		},
+		{
+			name: "invalid startDate",
 			body: map[string]interface{}{
-				"startDate": "2023-08-16",
+				"startDate": "11111",
 			},
-			respError: "field userId is empty",
+			respError: "json parse error",
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty startDate",
+			name: "invalid pageSize",
 			body: map[string]interface{}{
-				"userId": 1,
+				"startDate": "2023-08-16",
+				"pageSize":  5000,
 			},
-			respError: "json parse error",
+			respError: "field pageSize must be between 0 and 1000",
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "invalid startDate",
+			name:   "time zone",
+			userID: 1,
+			day:    time.Date(2023, 8, 16, 0, 0, 0, 0, sydney),
 			body: map[string]interface{}{
-				"userId":    1,
-				"startDate": "11111",
+				"startDate": "2023-08-16",
+				"tz":        "Australia/Sydney",
 			},
-			respError: "json parse error",
+			events: []models.Event{},
+			code:   http.StatusOK,
+		},
+		{
+			name: "invalid time zone",
+			body: map[string]interface{}{
+				"startDate": "2023-08-16",
+				"tz":        "Mars/Olympus",
+			},
+			respError: "field tz is invalid: unknown time zone Mars/Olympus",
 			code:      http.StatusBadRequest,
 		},
 		{
@@ -309,7 +663,6 @@ func TestGetByDayHandler(t *testing.T) {
 			userID: 1,
 			day:    time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC),
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-16",
 			},
 			mockError: errors.New("unexpected error"),
@@ -326,13 +679,12 @@ func TestGetByDayHandler(t *testing.T) {
 			appMock := mocks.NewCalendar(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				appMock.On("GetEventByDay", mock.Anything, tc.userID, tc.day).
-					Return(tc.events, tc.mockError).
+				appMock.On("GetEventByDay", mock.Anything, tc.userID, tc.day, mock.Anything).
+					Return(tc.events, tc.nextPage, tc.mockError).
 					Once()
 			}
 
-			handler := chi.NewRouter()
-			handler.Get(eventsURL+"/day", NewHandler(logger.NewMock(), appMock).getEventsByDay())
+			handler := eventsHandler(t, appMock)
 
 			var requestBody []byte
 			var err error
@@ -341,7 +693,7 @@ func TestGetByDayHandler(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet,
 				eventsURL+"/day", bytes.NewReader(requestBody))
 			require.NoError(t, err)
 
@@ -349,6 +701,7 @@ func TestGetByDayHandler(t *testing.T) {
 			handler.ServeHTTP(rr, req)
 
 			require.Equal(t, tc.code, rr.Code)
+			require.Equal(t, tc.nextPage, rr.Header().Get(nextPageTokenHeader))
 
 			if tc.events != nil {
 				var responseBody EventsResponse
@@ -378,7 +731,6 @@ func TestGetByWeekHandler(t *testing.T) {
 			userID: 1,
 			day:    time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC),
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-14",
 			},
 			events: []models.Event{
@@ -403,25 +755,14 @@ func TestGetByWeekHandler(t *testing.T) {
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty userId",
-			body: map[string]interface{}{
-				"startDate": "2023-08-14",
-			},
-			respError: "field userId is empty",
-			code:      http.StatusBadRequest,
-		},
-		{
-			name: "empty startDate",
-			body: map[string]interface{}{
-				"userId": 1,
-			},
+			name:      "empty startDate",
+			body:      map[string]interface{}{},
 			respError: "field startDate is empty",
 			code:      http.StatusBadRequest,
 		},
 		{
 			name: "invalid startDate",
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "11111",
 			},
 			respError: "json parse error",
@@ -432,7 +773,6 @@ func TestGetByWeekHandler(t *testing.T) {
 			userID: 1,
 			day:    time.Date(2023, 8, 14, 0, 0, 0, 0, time.UTC),
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-14",
 			},
 			mockError: errors.New("unexpected error"),
@@ -449,13 +789,12 @@ func TestGetByWeekHandler(t *testing.T) {
 			appMock := mocks.NewCalendar(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				appMock.On("GetEventByWeek", mock.Anything, tc.userID, tc.day).
-					Return(tc.events, tc.mockError).
+				appMock.On("GetEventByWeek", mock.Anything, tc.userID, tc.day, mock.Anything).
+					Return(tc.events, "", tc.mockError).
 					Once()
 			}
 
-			handler := chi.NewRouter()
-			handler.Get(eventsURL+"/week", NewHandler(logger.NewMock(), appMock).getEventsByWeek())
+			handler := eventsHandler(t, appMock)
 
 			var body []byte
 			var err error
@@ -464,7 +803,7 @@ func TestGetByWeekHandler(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet,
 				eventsURL+"/week", bytes.NewReader(body))
 			require.NoError(t, err)
 
@@ -501,7 +840,6 @@ func TestGetByMonthHandler(t *testing.T) {
 			userID: 1,
 			day:    time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-01",
 			},
 			events: []models.Event{
@@ -526,25 +864,14 @@ func TestGetByMonthHandler(t *testing.T) {
 			code:      http.StatusBadRequest,
 		},
 		{
-			name: "empty userId",
-			body: map[string]interface{}{
-				"startDate": "2023-08-01",
-			},
-			respError: "field userId is empty",
-			code:      http.StatusBadRequest,
-		},
-		{
-			name: "empty startDate",
-			body: map[string]interface{}{
-				"userId": 1,
-			},
+			name:      "empty startDate",
+			body:      map[string]interface{}{},
 			respError: "field startDate is empty",
 			code:      http.StatusBadRequest,
 		},
 		{
 			name: "invalid startDate",
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "11111",
 			},
 			respError: "json parse error",
@@ -555,7 +882,6 @@ func TestGetByMonthHandler(t *testing.T) {
 			userID: 1,
 			day:    time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
 			body: map[string]interface{}{
-				"userId":    1,
 				"startDate": "2023-08-01",
 			},
 			mockError: errors.New("unexpected error"),
@@ -572,13 +898,12 @@ func TestGetByMonthHandler(t *testing.T) {
 			appMock := mocks.NewCalendar(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				appMock.On("GetEventByMonth", mock.Anything, tc.userID, tc.day).
-					Return(tc.events, tc.mockError).
+				appMock.On("GetEventByMonth", mock.Anything, tc.userID, tc.day, mock.Anything).
+					Return(tc.events, "", tc.mockError).
 					Once()
 			}
 
-			handler := chi.NewRouter()
-			handler.Get(eventsURL+"/month", NewHandler(logger.NewMock(), appMock).getEventsByMonth())
+			handler := eventsHandler(t, appMock)
 
 			var body []byte
 			var err error
@@ -587,7 +912,7 @@ func TestGetByMonthHandler(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet,
 				eventsURL+"/month", bytes.NewReader(body))
 			require.NoError(t, err)
 
@@ -607,10 +932,96 @@ func TestGetByMonthHandler(t *testing.T) {
 	}
 }
 
+func TestGetInRangeHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		userID    int64
+		from      time.Time
+		to        time.Time
+		events    []models.Event
+		code      int
+		mockError error
+	}{
+		{
+			name:   "success",
+			query:  "?from=2023-08-16T10:00:00Z&to=2023-08-18T00:00:00Z",
+			userID: 1,
+			from:   time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC),
+			to:     time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC),
+			events: []models.Event{
+				{
+					Title:     "test",
+					UserID:    1,
+					StartDate: time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+					EndDate:   time.Date(2023, 8, 17, 13, 0, 0, 0, time.UTC),
+				},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "invalid from",
+			query: "?from=2023-08-16&to=2023-08-18T00:00:00Z",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "from after to",
+			query: "?from=2023-08-18T00:00:00Z&to=2023-08-16T10:00:00Z",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:      "get events error",
+			query:     "?from=2023-08-16T10:00:00Z&to=2023-08-18T00:00:00Z",
+			userID:    1,
+			from:      time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC),
+			to:        time.Date(2023, 8, 18, 0, 0, 0, 0, time.UTC),
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			if tc.code != http.StatusBadRequest {
+				appMock.On("GetEventsInRange", mock.Anything, tc.userID, tc.from, tc.to).
+					Return(tc.events, tc.mockError).
+					Once()
+			}
+
+			handler := eventsHandler(t, appMock)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet,
+				eventsURL+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.events != nil {
+				var responseBody EventsResponse
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				require.Equal(t, toResponse(tc.events), responseBody)
+			}
+		})
+	}
+}
+
 func TestDeleteHandler(t *testing.T) {
 	cases := []struct {
 		name      string
 		eventID   string
+		ifMatch   string
+		version   int64
 		code      int
 		mockError error
 	}{
@@ -619,10 +1030,37 @@ func TestDeleteHandler(t *testing.T) {
 			eventID: "id-1",
 			code:    http.StatusNoContent,
 		},
+		{
+			name:    "if match",
+			eventID: "id-1",
+			ifMatch: `"3"`,
+			version: 3,
+			code:    http.StatusNoContent,
+		},
+		{
+			name:      "version mismatch",
+			eventID:   "id-1",
+			ifMatch:   `"2"`,
+			version:   2,
+			mockError: storage.ErrVersionMismatch,
+			code:      http.StatusPreconditionFailed,
+		},
 		{
 			name:      "not existing event",
 			eventID:   "id-1",
 			mockError: storage.ErrEventNotExist,
+			code:      http.StatusNotFound,
+		},
+		{
+			name:      "event of another user",
+			eventID:   "id-1",
+			mockError: storage.ErrForbidden,
+			code:      http.StatusForbidden,
+		},
+		{
+			name:      "delete event error",
+			eventID:   "id-1",
+			mockError: errors.New("unexpected error"),
 			code:      http.StatusInternalServerError,
 		},
 	}
@@ -635,16 +1073,18 @@ func TestDeleteHandler(t *testing.T) {
 
 			appMock := mocks.NewCalendar(t)
 
-			appMock.On("DeleteEvent", mock.Anything, tc.eventID).
+			appMock.On("DeleteEvent", mock.Anything, int64(testUserID), tc.eventID, tc.version).
 				Return(tc.mockError).
 				Once()
 
-			handler := chi.NewRouter()
-			handler.Delete(eventsURL+"/{id}", NewHandler(logger.NewMock(), appMock).deleteEvent())
+			handler := eventsHandler(t, appMock)
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodDelete,
+			req, err := http.NewRequestWithContext(userContext(), http.MethodDelete,
 				eventsURL+"/"+tc.eventID, nil)
 			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
 
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
@@ -653,3 +1093,257 @@ func TestDeleteHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDeletedEventsHandler(t *testing.T) {
+	deletedAt := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		events    []models.Event
+		code      int
+		mockError error
+	}{
+		{
+			name: "success",
+			events: []models.Event{
+				{
+					ID:        "id-1",
+					Title:     "test",
+					UserID:    testUserID,
+					StartDate: time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+					EndDate:   time.Date(2023, 8, 15, 13, 0, 0, 0, time.UTC),
+					Version:   2,
+					DeletedAt: &deletedAt,
+				},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:      "get deleted events error",
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			appMock.On("GetDeletedEvents", mock.Anything, int64(testUserID)).
+				Return(tc.events, tc.mockError).
+				Once()
+
+			handler := chi.NewRouter()
+			handler.Get(trashURL, NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil).getDeletedEvents())
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet, trashURL, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.events != nil {
+				var responseBody EventsResponse
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				require.Equal(t, toResponse(tc.events), responseBody)
+				require.Equal(t, deletedAt, *responseBody[0].DeletedAt)
+			}
+		})
+	}
+}
+
+func TestRestoreHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		eventID   string
+		code      int
+		mockError error
+	}{
+		{
+			name:    "success",
+			eventID: "id-1",
+			code:    http.StatusNoContent,
+		},
+		{
+			name:      "event is not in the trash",
+			eventID:   "id-1",
+			mockError: storage.ErrEventNotExist,
+			code:      http.StatusNotFound,
+		},
+		{
+			name:      "event of another user",
+			eventID:   "id-1",
+			mockError: storage.ErrForbidden,
+			code:      http.StatusForbidden,
+		},
+		{
+			name:      "date is busy",
+			eventID:   "id-1",
+			mockError: storage.ErrDateBusy,
+			code:      http.StatusConflict,
+		},
+		{
+			name:      "restore event error",
+			eventID:   "id-1",
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			appMock.On("RestoreEvent", mock.Anything, int64(testUserID), tc.eventID).
+				Return(tc.mockError).
+				Once()
+
+			handler := eventsHandler(t, appMock)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodPost,
+				eventsURL+"/"+tc.eventID+"/restore", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+		})
+	}
+}
+
+func TestGetEventHistoryHandler(t *testing.T) {
+	changedAt := time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC)
+	event := models.Event{
+		ID:        "id-1",
+		Title:     "test",
+		UserID:    testUserID,
+		StartDate: time.Date(2023, 8, 15, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 8, 15, 13, 0, 0, 0, time.UTC),
+		Version:   1,
+	}
+
+	cases := []struct {
+		name      string
+		entries   []models.HistoryEntry
+		code      int
+		mockError error
+	}{
+		{
+			name: "success",
+			entries: []models.HistoryEntry{
+				{
+					ID:        1,
+					EventID:   "id-1",
+					Action:    models.ActionCreate,
+					ActorID:   testUserID,
+					RequestID: "request-1",
+					After:     &event,
+					ChangedAt: changedAt,
+				},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:      "not existing event",
+			mockError: storage.ErrEventNotExist,
+			code:      http.StatusNotFound,
+		},
+		{
+			name:      "event of another user",
+			mockError: storage.ErrForbidden,
+			code:      http.StatusForbidden,
+		},
+		{
+			name:      "get event history error",
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			appMock.On("GetEventHistory", mock.Anything, int64(testUserID), "id-1").
+				Return(tc.entries, tc.mockError).
+				Once()
+
+			handler := eventsHandler(t, appMock)
+
+			req, err := http.NewRequestWithContext(userContext(), http.MethodGet,
+				eventsURL+"/id-1/history", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.entries != nil {
+				var responseBody []HistoryEntry
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				after := toEventResponse(&event)
+				require.Equal(t, []HistoryEntry{{
+					ID:        1,
+					EventID:   "id-1",
+					Action:    models.ActionCreate,
+					ActorID:   testUserID,
+					RequestID: "request-1",
+					After:     &after,
+					ChangedAt: changedAt,
+				}}, responseBody)
+				require.Nil(t, responseBody[0].Before)
+			}
+		})
+	}
+}
+
+// legacyGateway returns the legacy gateway of the gRPC server of the app.
+func legacyGateway(t *testing.T, app server.Calendar) http.Handler {
+	t.Helper()
+
+	gateway, err := internalgrpc.NewServer(logger.NewMock(), app, nil, nil, nil, nil).
+		Gateway(context.Background(), LegacyGatewayOptions()...)
+	require.NoError(t, err)
+	return gateway
+}
+
+// eventsHandler returns the /v1 event routes served by the legacy gateway.
+func eventsHandler(t *testing.T, app server.Calendar) http.Handler {
+	t.Helper()
+
+	handler := chi.NewRouter()
+	NewHandler(logger.NewMock(), app, nil, nil, nil, nil, legacyGateway(t, app)).initAPIRoutes(handler)
+	return handler
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,152 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+)
+
+type FreeBusyRequest struct {
+	UserIDs      []int64
+	From         time.Time
+	To           time.Time
+	SlotDuration time.Duration
+}
+
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type UserBusy struct {
+	UserID int64      `json:"userId"`
+	Busy   []Interval `json:"busy"`
+}
+
+type FreeBusyResponse struct {
+	Users     []UserBusy `json:"users"`
+	FreeSlots []Interval `json:"freeSlots"`
+}
+
+func (h *Handler) freeBusy() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		request, err := parseFreeBusyRequest(r)
+		if err != nil {
+			log.Error("Parse request query", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if err := request.validate(); err != nil {
+			log.Error("Validate free/busy request", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		freeBusy, err := h.app.FreeBusy(r.Context(), request.UserIDs, request.From, request.To, request.SlotDuration)
+		if err != nil {
+			log.Error("Can not get free/busy intervals",
+				"user_ids", request.UserIDs,
+				"from", request.From,
+				"to", request.To,
+				"error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		render.JSON(w, r, toFreeBusyResponse(freeBusy))
+	}
+}
+
+func parseFreeBusyRequest(r *http.Request) (FreeBusyRequest, error) {
+	var request FreeBusyRequest
+	query := r.URL.Query()
+
+	for _, value := range query["userId"] {
+		userID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return request, fmt.Errorf("failed to parse userId: %w", err)
+		}
+		request.UserIDs = append(request.UserIDs, userID)
+	}
+
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return request, fmt.Errorf("failed to parse from: %w", err)
+		}
+		request.From = from
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return request, fmt.Errorf("failed to parse to: %w", err)
+		}
+		request.To = to
+	}
+
+	if value := query.Get("slotDuration"); value != "" {
+		slotDuration, err := time.ParseDuration(value)
+		if err != nil {
+			return request, fmt.Errorf("failed to parse slotDuration: %w", err)
+		}
+		request.SlotDuration = slotDuration
+	}
+	return request, nil
+}
+
+func (r *FreeBusyRequest) validate() error {
+	if len(r.UserIDs) == 0 {
+		return errors.New("field userId is empty")
+	}
+	if r.From.IsZero() {
+		return errors.New("field from is empty")
+	}
+	if r.To.IsZero() {
+		return errors.New("field to is empty")
+	}
+	if !r.From.Before(r.To) {
+		return errors.New("field from must be before field to")
+	}
+	if r.SlotDuration < 0 {
+		return errors.New("field slotDuration is negative")
+	}
+	return models.CheckFreeBusyLimits(len(r.UserIDs), r.From, r.To, r.SlotDuration)
+}
+
+func toFreeBusyResponse(freeBusy *models.FreeBusy) FreeBusyResponse {
+	users := make([]UserBusy, len(freeBusy.Users))
+	for i := range freeBusy.Users {
+		users[i] = UserBusy{
+			UserID: freeBusy.Users[i].UserID,
+			Busy:   toIntervals(freeBusy.Users[i].Busy),
+		}
+	}
+
+	return FreeBusyResponse{
+		Users:     users,
+		FreeSlots: toIntervals(freeBusy.FreeSlots),
+	}
+}
+
+func toIntervals(intervals []models.Interval) []Interval {
+	resp := make([]Interval, len(intervals))
+	for i := range intervals {
+		resp[i] = Interval{Start: intervals[i].Start, End: intervals[i].End}
+	}
+	return resp
+}
@@ -0,0 +1,118 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeBusyHandler(t *testing.T) {
+	from := time.Date(2023, 8, 16, 8, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		query     string
+		freeBusy  *models.FreeBusy
+		code      int
+		mockError error
+	}{
+		{
+			name:  "success",
+			query: "?userId=1&userId=2&from=2023-08-16T08:00:00Z&to=2023-08-16T13:00:00Z&slotDuration=1h",
+			freeBusy: &models.FreeBusy{
+				Users: []models.UserBusy{
+					{UserID: 1, Busy: []models.Interval{{Start: from, End: from.Add(time.Hour)}}},
+					{UserID: 2},
+				},
+				FreeSlots: []models.Interval{{Start: from.Add(time.Hour), End: from.Add(2 * time.Hour)}},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:  "empty userId",
+			query: "?from=2023-08-16T08:00:00Z&to=2023-08-16T13:00:00Z&slotDuration=1h",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "invalid slotDuration",
+			query: "?userId=1&from=2023-08-16T08:00:00Z&to=2023-08-16T13:00:00Z&slotDuration=hour",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "too short slotDuration",
+			query: "?userId=1&from=2023-08-16T08:00:00Z&to=2023-08-16T13:00:00Z&slotDuration=1ns",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "too long interval",
+			query: "?userId=1&from=2023-08-16T08:00:00Z&to=2025-08-16T13:00:00Z&slotDuration=1h",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "too many slots",
+			query: "?userId=1&from=2023-08-16T08:00:00Z&to=2023-10-16T13:00:00Z&slotDuration=1m",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name: "too many users",
+			query: "?" + strings.Repeat("userId=1&", models.MaxFreeBusyUsers+1) +
+				"from=2023-08-16T08:00:00Z&to=2023-08-16T13:00:00Z",
+			code: http.StatusBadRequest,
+		},
+		{
+			name:      "free/busy error",
+			query:     "?userId=1&userId=2&from=2023-08-16T08:00:00Z&to=2023-08-16T13:00:00Z&slotDuration=1h",
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			if tc.code != http.StatusBadRequest {
+				appMock.On("FreeBusy", mock.Anything, []int64{1, 2}, from, to, time.Hour).
+					Return(tc.freeBusy, tc.mockError).
+					Once()
+			}
+
+			handler := chi.NewRouter()
+			handler.Get(freeBusyURL, NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil).freeBusy())
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+				freeBusyURL+tc.query, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.freeBusy != nil {
+				var responseBody FreeBusyResponse
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				require.Equal(t, toFreeBusyResponse(tc.freeBusy), responseBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package internalhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// LegacyGatewayOptions make the gateway serve /v1/calendar/events in the format
+// of the former hand-written API: durations are in nanoseconds, 64-bit
+// integers are numbers, lists are not wrapped into objects and the status
+// codes of creates, deletes and restores are 201 and 204.
+func LegacyGatewayOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &legacyMarshaler{JSONPb: runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}}),
+		runtime.WithForwardResponseOption(legacyResponse),
+	}
+}
+
+type legacyMarshaler struct {
+	runtime.JSONPb
+}
+
+func (m *legacyMarshaler) Marshal(v interface{}) ([]byte, error) {
+	switch msg := v.(type) {
+	case *calendarpb.Event:
+		return json.Marshal(fromProtoEvent(msg))
+	case *calendarpb.EventsResponse:
+		events := make(EventsResponse, len(msg.GetEvents()))
+		for i, event := range msg.GetEvents() {
+			events[i] = fromProtoEvent(event)
+		}
+		return json.Marshal(events)
+	case *calendarpb.EventHistoryResponse:
+		entries := make([]HistoryEntry, len(msg.GetEntries()))
+		for i, entry := range msg.GetEntries() {
+			entries[i] = fromProtoHistoryEntry(entry)
+		}
+		return json.Marshal(entries)
+	case *emptypb.Empty:
+		return nil, nil
+	default:
+		return m.JSONPb.Marshal(v)
+	}
+}
+
+func (m *legacyMarshaler) Unmarshal(data []byte, v interface{}) error {
+	return m.JSONPb.Unmarshal(toProtoDurations(data), v)
+}
+
+var errEmptyBody = errors.New("request body is empty")
+
+// NewDecoder returns a decoder of the request bodies, which are required by
+// all /v1 routes that have them.
+func (m *legacyMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	return runtime.DecoderFunc(func(v interface{}) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			return errEmptyBody
+		}
+		return m.Unmarshal(data, v)
+	})
+}
+
+// toProtoDurations converts the notification time from nanoseconds to the
+// protobuf JSON format. The data is returned as is if it has no such field.
+func toProtoDurations(data []byte) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+
+	var nanoseconds int64
+	value, ok := fields["notificationTime"]
+	if !ok || json.Unmarshal(value, &nanoseconds) != nil {
+		return data
+	}
+
+	duration, err := protojson.Marshal(durationpb.New(time.Duration(nanoseconds)))
+	if err != nil {
+		return data
+	}
+	fields["notificationTime"] = duration
+
+	converted, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return converted
+}
+
+// legacyResponse sets the status code and the next page header of a response.
+func legacyResponse(ctx context.Context, w http.ResponseWriter, msg proto.Message) error {
+	if events, ok := msg.(*calendarpb.EventsResponse); ok && events.GetNextPageToken() != "" {
+		w.Header().Set(nextPageTokenHeader, events.GetNextPageToken())
+	}
+
+	method, _ := runtime.RPCMethod(ctx)
+	switch method {
+	case calendarpb.Calendar_CreateEvent_FullMethodName:
+		w.WriteHeader(http.StatusCreated)
+	case calendarpb.Calendar_DeleteEvent_FullMethodName, calendarpb.Calendar_RestoreEvent_FullMethodName:
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return nil
+}
+
+// withDateQuery passes the JSON body of the day, week and month requests to the
+// gateway as the query of the request.
+func (h *Handler) withDateQuery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		var request GetByDateRequest
+		if err := parseBody(r, &request); err != nil {
+			log.Error("Parse request body", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		r.URL.RawQuery = request.query().Encode()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (r *GetByDateRequest) query() url.Values {
+	query := url.Values{}
+	if date := time.Time(r.Date); !date.IsZero() {
+		query.Set("startDate", date.Format(time.RFC3339))
+	}
+	if r.PageSize != 0 {
+		query.Set("pageSize", strconv.Itoa(r.PageSize))
+	}
+	if r.PageToken != "" {
+		query.Set("pageToken", r.PageToken)
+	}
+	if r.Title != "" {
+		query.Set("title", r.Title)
+	}
+	if r.WithNotification {
+		query.Set("withNotification", "true")
+	}
+	if r.Timezone != "" {
+		query.Set("tz", r.Timezone)
+	}
+	return query
+}
+
+func fromProtoEvent(event *calendarpb.Event) Event {
+	response := Event{
+		ID:             event.GetId(),
+		Title:          event.GetTitle(),
+		UserID:         event.GetUserId(),
+		StartDate:      event.GetStartDate().AsTime(),
+		EndDate:        event.GetEndDate().AsTime(),
+		RecurrenceRule: event.RecurrenceRule,
+		Timezone:       event.GetTimezone(),
+		AllDay:         event.AllDay,
+		Version:        event.GetVersion(),
+	}
+	if event.GetDescription() != "" {
+		description := event.GetDescription()
+		response.Description = &description
+	}
+	if event.GetCalendarId() != "" {
+		calendarID := event.GetCalendarId()
+		response.CalendarID = &calendarID
+	}
+	if event.GetNotificationTime().AsDuration() != 0 {
+		notificationTime := event.GetNotificationTime().AsDuration()
+		response.NotificationTime = &notificationTime
+	}
+	for _, date := range event.GetExceptionDates() {
+		response.ExceptionDates = append(response.ExceptionDates, date.AsTime())
+	}
+	if event.GetDeletedAt() != nil {
+		deletedAt := event.GetDeletedAt().AsTime()
+		response.DeletedAt = &deletedAt
+	}
+	return response
+}
+
+func fromProtoHistoryEntry(entry *calendarpb.HistoryEntry) HistoryEntry {
+	response := HistoryEntry{
+		ID:        entry.GetId(),
+		EventID:   entry.GetEventId(),
+		Action:    models.HistoryAction(entry.GetAction()),
+		ActorID:   entry.GetActorId(),
+		RequestID: entry.GetRequestId(),
+		ChangedAt: entry.GetChangedAt().AsTime(),
+	}
+	if entry.GetBefore() != nil {
+		before := fromProtoEvent(entry.GetBefore())
+		response.Before = &before
+	}
+	if entry.GetAfter() != nil {
+		after := fromProtoEvent(entry.GetAfter())
+		response.After = &after
+	}
+	return response
+}
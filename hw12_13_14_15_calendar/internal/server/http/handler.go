@@ -1,42 +1,147 @@
 package internalhttp
 
 import (
+	"net/http"
+	"sync"
+	"time"
+
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
 )
 
 const (
-	eventsURL = "/v1/calendar/events"
+	eventsURL      = "/v1/calendar/events"
+	freeBusyURL    = "/v1/calendar/freebusy"
+	usersURL       = "/v1/calendar/users"
+	importURL      = "/v1/calendar/import"
+	calendarsURL   = "/v1/calendar/calendars"
+	invitationsURL = "/v1/calendar/invitations"
+	trashURL       = "/v1/calendar/trash"
+	metricsURL     = "/metrics"
+	healthzURL     = "/healthz"
+	readyzURL      = "/readyz"
+	gatewayURL     = "/v2"
+	openAPIURL     = gatewayURL + "/openapi.json"
 )
 
 type Handler struct {
-	app server.Calendar
-	log logger.ILogger
+	app     server.Calendar
+	auth    server.Authenticator
+	log     logger.ILogger
+	metrics *metrics.Metrics
+	health  server.HealthChecker
+	gateway http.Handler
+	// legacyGateway serves the /v1 event routes in the format of the former
+	// hand-written API.
+	legacyGateway http.Handler
+
+	// watchDuration limits the duration of an event stream, it is not limited
+	// if zero.
+	watchDuration time.Duration
+	// watchHeartbeat is the interval of the comments that keep an idle event
+	// stream open through proxies.
+	watchHeartbeat time.Duration
+	stop           chan struct{}
+	stopOnce       sync.Once
 }
 
-func NewHandler(log logger.ILogger, app server.Calendar) *Handler {
+// NewHandler creates the handler of the calendar API. Requests are not
+// instrumented and /metrics is not served if metrics is nil. /healthz and
+// /readyz are not served if health is nil. The REST API generated from
+// calendar.proto is served under /v2 by the gateway. The /v1 event routes,
+// except the event stream, are served by the legacy gateway, built with
+// LegacyGatewayOptions, and are not served if it is nil.
+func NewHandler(log logger.ILogger, app server.Calendar, authenticator server.Authenticator,
+	metrics *metrics.Metrics, health server.HealthChecker, gateway, legacyGateway http.Handler,
+) *Handler {
 	return &Handler{
-		app: app,
-		log: log,
+		app:            app,
+		auth:           authenticator,
+		log:            log,
+		metrics:        metrics,
+		health:         health,
+		gateway:        gateway,
+		legacyGateway:  legacyGateway,
+		watchHeartbeat: config.DefaultWatchHeartbeat,
+		stop:           make(chan struct{}),
 	}
 }
 
+// stopWatches ends the running event streams, so the server can shut down.
+func (h *Handler) stopWatches() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+}
+
 func (h *Handler) InitRoutes() *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
 	router.Use(middleware.Recoverer)
+	router.Use(WithTracing())
 	router.Use(WithLogger(h.log))
+	if h.metrics != nil {
+		router.Use(WithMetrics(h.metrics))
+		router.Handle(metricsURL, h.metrics.Handler())
+	}
+	if h.health != nil {
+		router.Get(healthzURL, h.healthz())
+		router.Get(readyzURL, h.readyz())
+	}
+	if h.gateway != nil {
+		router.Get(openAPIURL, h.openAPI())
+	}
 
-	router.Route(eventsURL, func(r chi.Router) {
-		r.Post("/", h.createEvent())
-		r.Get("/day", h.getEventsByDay())
-		r.Get("/week", h.getEventsByWeek())
-		r.Get("/month", h.getEventsByMonth())
-		r.Patch("/{id}", h.updateEvent())
-		r.Delete("/{id}", h.deleteEvent())
+	router.Group(func(r chi.Router) {
+		r.Use(WithAuth(h.log, h.auth))
+		h.initAPIRoutes(r)
+		if h.gateway != nil {
+			r.Handle(gatewayURL+"/*", h.gateway)
+		}
 	})
 	return router
 }
+
+func (h *Handler) initAPIRoutes(router chi.Router) {
+	router.Route(eventsURL, func(r chi.Router) {
+		r.Get("/watch", h.watchEvents())
+		if h.legacyGateway == nil {
+			return
+		}
+		// The routes are bound in calendar.proto, they are listed here to
+		// keep them in the metrics and traces.
+		gateway := h.legacyGateway.ServeHTTP
+		r.Post("/", gateway)
+		r.Get("/", gateway)
+		r.With(h.withDateQuery).Get("/day", gateway)
+		r.With(h.withDateQuery).Get("/week", gateway)
+		r.With(h.withDateQuery).Get("/month", gateway)
+		r.Get("/{id}", gateway)
+		r.Patch("/{id}", gateway)
+		r.Delete("/{id}", gateway)
+		r.Post("/{id}/restore", gateway)
+		r.Get("/{id}/history", gateway)
+		r.Post("/{id}/attendees", gateway)
+		r.Put("/{id}/rsvp", gateway)
+	})
+	router.Get(freeBusyURL, h.freeBusy())
+	router.Get(usersURL+"/{id}/calendar.ics", h.exportCalendar())
+	router.Post(importURL, h.importCalendar())
+	router.Get(invitationsURL, h.getInvitations())
+	router.Get(trashURL, h.getDeletedEvents())
+	router.Route(calendarsURL, func(r chi.Router) {
+		r.Post("/", h.createCalendar())
+		r.Get("/", h.getCalendars())
+		r.Get("/{id}", h.getCalendar())
+		r.Patch("/{id}", h.updateCalendar())
+		r.Delete("/{id}", h.deleteCalendar())
+		r.Get("/{id}/acl", h.getACL())
+		r.Put("/{id}/acl/{userId}", h.setACLEntry())
+		r.Delete("/{id}/acl/{userId}", h.deleteACLEntry())
+	})
+}
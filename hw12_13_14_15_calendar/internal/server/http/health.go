@@ -0,0 +1,34 @@
+package internalhttp
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+)
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// healthz reports that the server is alive.
+func (h *Handler) healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, HealthResponse{Status: "ok"})
+	}
+}
+
+// readyz reports whether the service is ready to handle requests. It fails
+// with 503 while the service is shutting down or the database is unreachable.
+func (h *Handler) readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.health.Ready(r.Context()); err != nil {
+			server.RequestLogger(r.Context(), h.log).Warn("Service is not ready", "error", err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+		render.JSON(w, r, HealthResponse{Status: "ok"})
+	}
+}
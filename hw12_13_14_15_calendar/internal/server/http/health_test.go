@@ -0,0 +1,70 @@
+package internalhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+type healthCheckerFunc func(ctx context.Context) error
+
+func (f healthCheckerFunc) Ready(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHealthHandlers(t *testing.T) {
+	cases := []struct {
+		name  string
+		url   string
+		ready error
+		code  int
+	}{
+		{
+			name: "alive",
+			url:  healthzURL,
+			code: http.StatusOK,
+		},
+		{
+			name:  "alive while not ready",
+			url:   healthzURL,
+			ready: errors.New("connection refused"),
+			code:  http.StatusOK,
+		},
+		{
+			name: "ready",
+			url:  readyzURL,
+			code: http.StatusOK,
+		},
+		{
+			name:  "not ready",
+			url:   readyzURL,
+			ready: errors.New("connection refused"),
+			code:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checker := healthCheckerFunc(func(context.Context) error { return tc.ready })
+			handler := NewHandler(logger.NewMock(), nil, tokenAuthenticator{}, nil, checker, nil, nil).InitRoutes()
+
+			// Probes are served without an access token.
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+		})
+	}
+}
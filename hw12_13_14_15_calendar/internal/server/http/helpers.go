@@ -8,6 +8,9 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
 )
 
 func parseBody(r *http.Request, body any) error {
@@ -24,3 +27,32 @@ func parseBody(r *http.Request, body any) error {
 func parseID(r *http.Request) string {
 	return chi.URLParam(r, "id")
 }
+
+var errUnauthenticated = errors.New("user is not authenticated")
+
+// requestUserID returns the user authenticated by WithAuth.
+func requestUserID(r *http.Request) (int64, error) {
+	userID, ok := auth.UserID(r.Context())
+	if !ok {
+		return 0, errUnauthenticated
+	}
+	return userID, nil
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrDateBusy):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, storage.ErrEventNotExist), errors.Is(err, storage.ErrCalendarNotExist),
+		errors.Is(err, storage.ErrInvitationNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrInvalidPageToken), errors.Is(err, models.ErrEventTooLong):
+		return http.StatusBadRequest
+	case errors.Is(err, storage.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
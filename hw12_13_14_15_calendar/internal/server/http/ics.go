@@ -0,0 +1,140 @@
+package internalhttp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/ics"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+)
+
+// maxImportSize limits the size of an imported calendar, like the default
+// limit of a gRPC message.
+const maxImportSize = 4 << 20
+
+type ImportResponse struct {
+	EventIDs []string `json:"ids"`
+}
+
+func (h *Handler) exportCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		pathUserID, err := strconv.ParseInt(parseID(r), 10, 64)
+		if err != nil || pathUserID == 0 {
+			err = errors.New("invalid user id")
+			log.Error("Validate export request", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if pathUserID != userID {
+			err = errors.New("can not export calendar of another user")
+			log.Error("Validate export request", "user_id", userID, "error", err)
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		events, err := h.app.GetEventsByUser(r.Context(), userID)
+		if err != nil {
+			log.Error("Can not get events of the user", "user_id", userID, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		var body bytes.Buffer
+		if err := ics.Encode(&body, events); err != nil {
+			log.Error("Encode calendar", "user_id", userID, "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", ics.ContentType+"; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="calendar.ics"`)
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(body.Bytes()); err != nil {
+			log.Error("Write calendar", "user_id", userID, "error", err)
+		}
+	}
+}
+
+func (h *Handler) importCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		allowOverlap, err := parseImportQuery(r)
+		if err != nil {
+			log.Error("Parse request query", "error", err)
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		events, err := ics.Decode(http.MaxBytesReader(w, r.Body, maxImportSize))
+		if err != nil {
+			log.Error("Decode calendar", "error", err)
+			w.WriteHeader(decodeStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		ids, err := h.app.ImportEvents(r.Context(), userID, events, allowOverlap)
+		if err != nil {
+			log.Error("Import events", "user_id", userID, "error", err)
+			w.WriteHeader(errorStatus(err))
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		render.JSON(w, r, ImportResponse{EventIDs: ids})
+	}
+}
+
+// decodeStatus returns 413 if the calendar exceeds maxImportSize and 400
+// otherwise.
+func decodeStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func parseImportQuery(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("allowOverlap")
+	if value == "" {
+		return false, nil
+	}
+
+	allowOverlap, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse allowOverlap: %w", err)
+	}
+	return allowOverlap, nil
+}
@@ -0,0 +1,166 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:test\r\n" +
+	"DTSTART:20230816T120000Z\r\n" +
+	"DTEND:20230816T130000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestExportHandler(t *testing.T) {
+	appMock := mocks.NewCalendar(t)
+	appMock.On("GetEventsByUser", mock.Anything, int64(1)).
+		Return([]models.Event{{
+			ID:        "id-1",
+			Title:     "test",
+			UserID:    1,
+			StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+		}}, nil).
+		Once()
+
+	handler := NewHandler(logger.NewMock(), appMock, tokenAuthenticator{}, nil, nil, nil, nil).InitRoutes()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		usersURL+"/1/calendar.ics", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer 1")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.True(t, strings.HasPrefix(rr.Header().Get("Content-Type"), "text/calendar"))
+	require.Contains(t, rr.Body.String(), "UID:id-1\r\n")
+	require.Contains(t, rr.Body.String(), "DTSTART:20230816T120000Z\r\n")
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet,
+		usersURL+"/abc/calendar.ics", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer 1")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet,
+		usersURL+"/2/calendar.ics", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer 1")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestImportHandler(t *testing.T) {
+	wantEvents := []models.Event{{
+		Title:     "test",
+		StartDate: time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2023, 8, 16, 13, 0, 0, 0, time.UTC),
+	}}
+
+	cases := []struct {
+		name      string
+		query     string
+		body      string
+		ids       []string
+		code      int
+		mockError error
+	}{
+		{
+			name:  "success",
+			query: "?allowOverlap=true",
+			body:  testCalendar,
+			ids:   []string{"id-1"},
+			code:  http.StatusCreated,
+		},
+		{
+			name:  "invalid allowOverlap",
+			query: "?allowOverlap=maybe",
+			body:  testCalendar,
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "invalid calendar",
+			query: "",
+			body:  "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "too large calendar",
+			query: "",
+			body:  strings.Repeat("X-PADDING:x\r\n", maxImportSize/8),
+			code:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:      "import error",
+			query:     "?allowOverlap=true",
+			body:      testCalendar,
+			mockError: errors.New("unexpected error"),
+			code:      http.StatusInternalServerError,
+		},
+		{
+			name:      "date is busy",
+			query:     "?allowOverlap=true",
+			body:      testCalendar,
+			mockError: &storage.ConflictError{EventID: "id-2"},
+			code:      http.StatusConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			appMock := mocks.NewCalendar(t)
+
+			if tc.code != http.StatusBadRequest && tc.code != http.StatusRequestEntityTooLarge {
+				appMock.On("ImportEvents", mock.Anything, int64(1), wantEvents, true).
+					Return(tc.ids, tc.mockError).
+					Once()
+			}
+
+			handler := NewHandler(logger.NewMock(), appMock, tokenAuthenticator{}, nil, nil, nil, nil).InitRoutes()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+				importURL+tc.query, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer 1")
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+
+			if tc.ids != nil {
+				var responseBody ImportResponse
+				err = json.Unmarshal(rr.Body.Bytes(), &responseBody)
+				require.NoError(t, err)
+
+				require.Equal(t, tc.ids, responseBody.EventIDs)
+			}
+		})
+	}
+}
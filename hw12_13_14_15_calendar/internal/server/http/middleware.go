@@ -4,8 +4,21 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/tracing"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slog"
 )
 
@@ -14,8 +27,7 @@ func WithLogger(log logger.ILogger) func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			t1 := time.Now()
 
-			entry := log.With(
-				slog.String("request_id", middleware.GetReqID(r.Context())),
+			entry := server.RequestLogger(r.Context(), log).With(
 				slog.String("ts", t1.String()),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("method", r.Method),
@@ -38,3 +50,93 @@ func WithLogger(log logger.ILogger) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(fn)
 	}
 }
+
+// WithTracing starts a server span for a request. The span continues the
+// trace of the traceparent header and its context is returned to the client.
+func WithTracing() func(next http.Handler) http.Handler {
+	tracer := tracing.Tracer()
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			propagator := otel.GetTextMapPropagator()
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			ctx, span := tracer.Start(ctx, "HTTP "+r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPMethod(r.Method),
+					semconv.HTTPTarget(r.URL.Path),
+					attribute.String("request_id", middleware.GetReqID(ctx)),
+				),
+			)
+			defer span.End()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			propagator.Inject(ctx, propagation.HeaderCarrier(ww.Header()))
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if route := chi.RouteContext(r.Context()).RoutePattern(); route != "" {
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
+			span.SetAttributes(semconv.HTTPStatusCode(ww.Status()))
+			if ww.Status() >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(ww.Status()))
+			}
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithMetrics records the number, status codes and latency of requests per
+// route and the number of requests in flight.
+func WithMetrics(m *metrics.Metrics) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			done := m.StartHTTPRequest()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				route := chi.RouteContext(r.Context()).RoutePattern()
+				if route == "" {
+					route = "unmatched"
+				}
+				done(r.Method, route, ww.Status())
+			}()
+
+			next.ServeHTTP(ww, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithAuth authenticates the bearer token of a request and stores the ID of
+// its user in the request context.
+func WithAuth(log logger.ILogger, authenticator server.Authenticator) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			token, ok := auth.BearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				unauthorized(w, r, "missing bearer token")
+				return
+			}
+
+			userID, err := authenticator.Authenticate(r.Context(), token)
+			if err != nil {
+				server.RequestLogger(r.Context(), log).Error("Authenticate request", "error", err)
+				unauthorized(w, r, err.Error())
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), userID)))
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	render.JSON(w, r, resp.Error(msg))
+}
@@ -0,0 +1,152 @@
+package internalhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/tracing"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenAuthenticator accepts a user ID as the bearer token.
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Authenticate(_ context.Context, token string) (int64, error) {
+	userID, err := strconv.ParseInt(token, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid token")
+	}
+	return userID, nil
+}
+
+func TestWithAuth(t *testing.T) {
+	cases := []struct {
+		name          string
+		authorization string
+		userID        int64
+		code          int
+	}{
+		{
+			name:          "success",
+			authorization: "Bearer 7",
+			userID:        7,
+			code:          http.StatusOK,
+		},
+		{
+			name: "missing token",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name:          "not a bearer token",
+			authorization: "Basic 7",
+			code:          http.StatusUnauthorized,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer invalid",
+			code:          http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var userID int64
+			handler := chi.NewRouter()
+			handler.Use(WithAuth(logger.NewMock(), tokenAuthenticator{}))
+			handler.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				userID, _ = auth.UserID(r.Context())
+			})
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+			require.Equal(t, tc.userID, userID)
+			if tc.code == http.StatusUnauthorized {
+				require.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	appMock := mocks.NewCalendar(t)
+	appMock.On("GetEvent", mock.Anything, int64(7), "42").
+		Return(nil, storage.ErrEventNotExist).Once()
+
+	m := metrics.New()
+	handler := NewHandler(logger.NewMock(), appMock, tokenAuthenticator{}, m, nil, nil,
+		legacyGateway(t, appMock)).InitRoutes()
+
+	for _, authorization := range []string{"Bearer 7", ""} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, eventsURL+"/42", nil)
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, metricsURL, nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	require.Contains(t, body, `calendar_http_requests_total{code="404",method="GET",route="/v1/calendar/events/{id}"} 1`)
+	// Unauthenticated requests are rejected before the route is matched.
+	require.Contains(t, body, `calendar_http_requests_total{code="401",method="GET",route="/v1/calendar/events/*"} 1`)
+	require.Contains(t, body, `calendar_http_request_duration_seconds_count{method="GET",route="/v1/calendar/events/{id}"} 1`) //nolint:lll
+	require.Contains(t, body, "calendar_http_requests_in_flight 1")
+}
+
+func TestWithTracing(t *testing.T) {
+	_, err := tracing.Init(&config.TracingConfig{Exporter: config.TracingExporterNone})
+	require.NoError(t, err)
+
+	const (
+		traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+		traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+	)
+
+	var gotTraceID string
+	handler := chi.NewRouter()
+	handler.Use(WithTracing())
+	handler.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		gotTraceID = tracing.TraceID(r.Context())
+	})
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.Header.Set("traceparent", traceparent)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, traceID, gotTraceID)
+	require.Contains(t, rr.Header().Get("traceparent"), traceID)
+}
@@ -0,0 +1,19 @@
+package internalhttp
+
+import (
+	"net/http"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/pkg/api/calendarpb"
+)
+
+// openAPI serves the OpenAPI specification of the REST API generated from
+// calendar.proto.
+func (h *Handler) openAPI() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(calendarpb.OpenAPI); err != nil {
+			server.RequestLogger(r.Context(), h.log).Error("Write OpenAPI specification", "error", err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayRoutes(t *testing.T) {
+	gateway := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := NewHandler(logger.NewMock(), nil, tokenAuthenticator{}, nil, nil, gateway, nil).InitRoutes()
+
+	cases := []struct {
+		name          string
+		url           string
+		authorization string
+		code          int
+	}{
+		{
+			name: "OpenAPI specification is public",
+			url:  openAPIURL,
+			code: http.StatusOK,
+		},
+		{
+			name:          "gateway",
+			url:           "/v2/calendar/events/id-1",
+			authorization: "Bearer 7",
+			code:          http.StatusTeapot,
+		},
+		{
+			name: "gateway requires authentication",
+			url:  "/v2/calendar/events/id-1",
+			code: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.code, rr.Code)
+			if tc.url == openAPIURL {
+				require.True(t, json.Valid(rr.Body.Bytes()))
+			}
+		})
+	}
+}
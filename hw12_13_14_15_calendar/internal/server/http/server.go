@@ -7,6 +7,7 @@ import (
 
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/config"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/metrics"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
 )
 
@@ -14,14 +15,24 @@ type Server struct {
 	srv *http.Server
 }
 
-func NewServer(log logger.ILogger, app server.Calendar, cfg *config.ServerHTTPConfig) *Server {
+func NewServer(log logger.ILogger, app server.Calendar, authenticator server.Authenticator,
+	metrics *metrics.Metrics, health server.HealthChecker, gateway, legacyGateway http.Handler,
+	cfg *config.ServerHTTPConfig,
+) *Server {
+	handler := NewHandler(log, app, authenticator, metrics, health, gateway, legacyGateway)
+	// The event stream clears the write deadline of its response, so its
+	// duration is configured apart from the write timeout.
+	handler.watchDuration = cfg.Watch.Duration
+	handler.watchHeartbeat = cfg.Watch.Heartbeat
+
 	serverCfg := http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
-		Handler:      NewHandler(log, app).InitRoutes(),
+		Handler:      handler.InitRoutes(),
 		ReadTimeout:  cfg.Timeout,
 		WriteTimeout: cfg.Timeout,
 		IdleTimeout:  cfg.IdleTimeout,
 	}
+	serverCfg.RegisterOnShutdown(handler.stopWatches)
 
 	return &Server{
 		srv: &serverCfg,
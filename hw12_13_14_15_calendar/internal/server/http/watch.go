@@ -0,0 +1,140 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server"
+	resp "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/lib/api/response"
+)
+
+// watchRetry is the reconnection delay suggested to the clients.
+const watchRetry = time.Second
+
+var errWriteDeadline = errors.New("write deadline can not be cleared")
+
+// EventChange is the data of a server-sent event of the event stream.
+type EventChange struct {
+	Type      models.ChangeType `json:"type"`
+	Event     Event             `json:"event"`
+	ChangedAt time.Time         `json:"changedAt"`
+}
+
+// watchEvents streams the changes of the events the user can read as
+// server-sent events named after the type of the change. The write timeout of
+// the server does not apply to the stream, it ends after watchDuration, when
+// the server stops or when the client does not keep up with the changes. The changes made while the client
+// reconnects are lost, so the client should reload the events after that.
+func (h *Handler) watchEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := server.RequestLogger(r.Context(), h.log)
+
+		userID, err := requestUserID(r)
+		if err != nil {
+			log.Error("Authenticate request", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			err := errors.New("streaming is not supported")
+			log.Error("Watch events", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			render.JSON(w, r, resp.Error(err.Error()))
+			return
+		}
+
+		if err := clearWriteDeadline(w); err != nil {
+			log.Error("Clear write deadline", "error", err)
+		}
+
+		sub := h.app.WatchEvents(userID)
+		defer sub.Close()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", watchRetry.Milliseconds())
+		flusher.Flush()
+
+		var timeout <-chan time.Time
+		if h.watchDuration > 0 {
+			timer := time.NewTimer(h.watchDuration)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		heartbeat := time.NewTicker(h.watchHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+
+			case <-h.stop:
+				return
+
+			case <-timeout:
+				return
+
+			case <-heartbeat.C:
+				if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+					log.Error("Send heartbeat", "user_id", userID, "error", err)
+					return
+				}
+
+			case change, ok := <-sub.Changes():
+				if !ok {
+					log.Error("Watch events", "user_id", userID, "error", sub.Err())
+					return
+				}
+
+				if err := writeEventChange(w, &change); err != nil {
+					log.Error("Send event change", "user_id", userID, "error", err)
+					return
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// clearWriteDeadline removes the deadline the write timeout of the server sets
+// for the response. It does what http.ResponseController of Go 1.20 does, so
+// the module keeps building with Go 1.19, where the deadline stays and ends
+// the stream. The wrappers of the writer must provide Unwrap.
+func clearWriteDeadline(w http.ResponseWriter) error {
+	for {
+		switch rw := w.(type) {
+		case interface{ SetWriteDeadline(time.Time) error }:
+			return rw.SetWriteDeadline(time.Time{})
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = rw.Unwrap()
+		default:
+			return errWriteDeadline
+		}
+	}
+}
+
+func writeEventChange(w io.Writer, change *models.EventChange) error {
+	data, err := json.Marshal(EventChange{
+		Type:      change.Type,
+		Event:     toEventResponse(&change.Event),
+		ChangedAt: change.ChangedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", change.Type, data)
+	return err
+}
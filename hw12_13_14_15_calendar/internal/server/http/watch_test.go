@@ -0,0 +1,188 @@
+package internalhttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/auth"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/feed"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/server/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// startWatch opens the event stream of testUserID fed by the subscription.
+func startWatch(t *testing.T, sub *feed.Subscription, configure func(*Handler)) (*bufio.Reader, func()) {
+	t.Helper()
+
+	appMock := mocks.NewCalendar(t)
+	appMock.On("WatchEvents", int64(testUserID)).Return(sub).Once()
+
+	handler := NewHandler(logger.NewMock(), appMock, nil, nil, nil, nil, nil)
+	if configure != nil {
+		configure(handler)
+	}
+	watch := handler.watchEvents()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		watch(w, r.WithContext(auth.WithUserID(r.Context(), testUserID)))
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+eventsURL+"/watch", nil)
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	body := bufio.NewReader(res.Body)
+	require.Equal(t, "retry: 1000", readEvent(t, body)[0])
+
+	return body, func() {
+		cancel()
+		res.Body.Close()
+		srv.Close()
+	}
+}
+
+// readEvent returns the lines of the next event of the stream.
+func readEvent(t *testing.T, body *bufio.Reader) []string {
+	t.Helper()
+
+	var lines []string
+	for {
+		line, err := body.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func requireStreamEnd(t *testing.T, body *bufio.Reader) {
+	t.Helper()
+
+	_, err := body.ReadString('\n')
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestWatchEventsHandler(t *testing.T) {
+	changedAt := time.Date(2023, 8, 16, 10, 0, 0, 0, time.UTC)
+	change := func(eventID string) models.EventChange {
+		return models.EventChange{
+			Type:      models.ChangeUpdated,
+			Event:     models.Event{ID: eventID, UserID: testUserID},
+			UserIDs:   []int64{testUserID},
+			ChangedAt: changedAt,
+		}
+	}
+
+	t.Run("changes are streamed", func(t *testing.T) {
+		changes := feed.New(10)
+		body, closeFn := startWatch(t, changes.Subscribe(testUserID), nil)
+		defer closeFn()
+
+		changes.Publish(change("event-1"))
+
+		lines := readEvent(t, body)
+		require.Len(t, lines, 2)
+		require.Equal(t, "event: updated", lines[0])
+
+		var data EventChange
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &data))
+		require.Equal(t, models.ChangeUpdated, data.Type)
+		require.Equal(t, "event-1", data.Event.ID)
+		require.Equal(t, changedAt, data.ChangedAt)
+	})
+
+	t.Run("slow client", func(t *testing.T) {
+		changes := feed.New(1)
+		sub := changes.Subscribe(testUserID)
+
+		// The second change does not fit into the buffer of the subscription.
+		changes.Publish(change("event-1"))
+		changes.Publish(change("event-2"))
+
+		body, closeFn := startWatch(t, sub, nil)
+		defer closeFn()
+
+		require.Equal(t, "event: updated", readEvent(t, body)[0])
+		requireStreamEnd(t, body)
+	})
+
+	t.Run("stream ends after the watch duration", func(t *testing.T) {
+		body, closeFn := startWatch(t, feed.New(1).Subscribe(testUserID), func(h *Handler) {
+			h.watchDuration = 10 * time.Millisecond
+		})
+		defer closeFn()
+
+		requireStreamEnd(t, body)
+	})
+
+	t.Run("heartbeat", func(t *testing.T) {
+		body, closeFn := startWatch(t, feed.New(1).Subscribe(testUserID), func(h *Handler) {
+			h.watchHeartbeat = time.Millisecond
+		})
+		defer closeFn()
+
+		require.Equal(t, []string{": heartbeat"}, readEvent(t, body))
+	})
+
+	t.Run("server stops", func(t *testing.T) {
+		var handler *Handler
+		body, closeFn := startWatch(t, feed.New(1).Subscribe(testUserID), func(h *Handler) {
+			handler = h
+		})
+		defer closeFn()
+
+		handler.stopWatches()
+		requireStreamEnd(t, body)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		handler := NewHandler(logger.NewMock(), mocks.NewCalendar(t), nil, nil, nil, nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, eventsURL+"/watch", nil)
+		rr := httptest.NewRecorder()
+		handler.watchEvents()(rr, req)
+
+		require.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+type deadlineWriter struct {
+	http.ResponseWriter
+	deadline *time.Time
+}
+
+func (w *deadlineWriter) SetWriteDeadline(deadline time.Time) error {
+	w.deadline = &deadline
+	return nil
+}
+
+func TestClearWriteDeadline(t *testing.T) {
+	t.Run("wrapped writer", func(t *testing.T) {
+		w := &deadlineWriter{ResponseWriter: httptest.NewRecorder()}
+
+		require.NoError(t, clearWriteDeadline(middleware.NewWrapResponseWriter(w, 1)))
+		require.NotNil(t, w.deadline)
+		require.True(t, w.deadline.IsZero())
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		require.ErrorIs(t, clearWriteDeadline(httptest.NewRecorder()), errWriteDeadline)
+	})
+}
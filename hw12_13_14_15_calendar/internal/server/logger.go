@@ -0,0 +1,20 @@
+package server
+
+import (
+	"context"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/logger"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/tracing"
+	"golang.org/x/exp/slog"
+)
+
+// RequestLogger adds the ID of the request and the ID of its trace, if any,
+// to the entries of the logger.
+func RequestLogger(ctx context.Context, log logger.ILogger) logger.ILogger {
+	log = log.With(slog.String("request_id", middleware.GetReqID(ctx)))
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		log = log.With(slog.String("trace_id", traceID))
+	}
+	return log
+}
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	feed "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/feed"
 	models "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
 	mock "github.com/stretchr/testify/mock"
 
@@ -16,6 +17,30 @@ type Calendar struct {
 	mock.Mock
 }
 
+// CreateCalendar provides a mock function with given fields: ctx, calendar
+func (_m *Calendar) CreateCalendar(ctx context.Context, calendar *models.Calendar) (string, error) {
+	ret := _m.Called(ctx, calendar)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Calendar) (string, error)); ok {
+		return rf(ctx, calendar)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Calendar) string); ok {
+		r0 = rf(ctx, calendar)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Calendar) error); ok {
+		r1 = rf(ctx, calendar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateEvent provides a mock function with given fields: _a0, _a1
 func (_m *Calendar) CreateEvent(_a0 context.Context, _a1 *models.Event) (string, error) {
 	ret := _m.Called(_a0, _a1)
@@ -40,13 +65,41 @@ func (_m *Calendar) CreateEvent(_a0 context.Context, _a1 *models.Event) (string,
 	return r0, r1
 }
 
-// DeleteEvent provides a mock function with given fields: _a0, _a1
-func (_m *Calendar) DeleteEvent(_a0 context.Context, _a1 string) error {
-	ret := _m.Called(_a0, _a1)
+// DeleteACLEntry provides a mock function with given fields: ctx, userID, calendarID, memberID
+func (_m *Calendar) DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) error {
+	ret := _m.Called(ctx, userID, calendarID, memberID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int64) error); ok {
+		r0 = rf(ctx, userID, calendarID, memberID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCalendar provides a mock function with given fields: ctx, userID, calendarID
+func (_m *Calendar) DeleteCalendar(ctx context.Context, userID int64, calendarID string) error {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteEvent provides a mock function with given fields: ctx, userID, eventID, version
+func (_m *Calendar) DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) error {
+	ret := _m.Called(ctx, userID, eventID, version)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int64) error); ok {
+		r0 = rf(ctx, userID, eventID, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -54,25 +107,280 @@ func (_m *Calendar) DeleteEvent(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// GetEventByDay provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Calendar) GetEventByDay(_a0 context.Context, _a1 int64, _a2 time.Time) ([]models.Event, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// FreeBusy provides a mock function with given fields: ctx, userIDs, from, to, slotDuration
+func (_m *Calendar) FreeBusy(ctx context.Context, userIDs []int64, from time.Time, to time.Time, slotDuration time.Duration) (*models.FreeBusy, error) {
+	ret := _m.Called(ctx, userIDs, from, to, slotDuration)
+
+	var r0 *models.FreeBusy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, time.Time, time.Time, time.Duration) (*models.FreeBusy, error)); ok {
+		return rf(ctx, userIDs, from, to, slotDuration)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64, time.Time, time.Time, time.Duration) *models.FreeBusy); ok {
+		r0 = rf(ctx, userIDs, from, to, slotDuration)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.FreeBusy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64, time.Time, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, userIDs, from, to, slotDuration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetACL provides a mock function with given fields: ctx, userID, calendarID
+func (_m *Calendar) GetACL(ctx context.Context, userID int64, calendarID string) ([]models.ACLEntry, error) {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	var r0 []models.ACLEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]models.ACLEntry, error)); ok {
+		return rf(ctx, userID, calendarID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []models.ACLEntry); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ACLEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, calendarID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCalendar provides a mock function with given fields: ctx, userID, calendarID
+func (_m *Calendar) GetCalendar(ctx context.Context, userID int64, calendarID string) (*models.Calendar, error) {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	var r0 *models.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*models.Calendar, error)); ok {
+		return rf(ctx, userID, calendarID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *models.Calendar); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Calendar)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, calendarID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCalendars provides a mock function with given fields: ctx, userID
+func (_m *Calendar) GetCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []models.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Calendar, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Calendar); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Calendar)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeletedEvents provides a mock function with given fields: ctx, userID
+func (_m *Calendar) GetDeletedEvents(ctx context.Context, userID int64) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID)
 
 	var r0 []models.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) ([]models.Event, error)); ok {
-		return rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Event, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Event); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEvent provides a mock function with given fields: ctx, userID, eventID
+func (_m *Calendar) GetEvent(ctx context.Context, userID int64, eventID string) (*models.Event, error) {
+	ret := _m.Called(ctx, userID, eventID)
+
+	var r0 *models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*models.Event, error)); ok {
+		return rf(ctx, userID, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *models.Event); ok {
+		r0 = rf(ctx, userID, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventByDay provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Calendar) GetEventByDay(_a0 context.Context, _a1 int64, _a2 time.Time, _a3 models.EventFilter) ([]models.Event, string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 []models.Event
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) []models.Event); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []models.Event); ok {
-		r0 = rf(_a0, _a1, _a2)
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, models.EventFilter) string); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, time.Time, models.EventFilter) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEventByMonth provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Calendar) GetEventByMonth(_a0 context.Context, _a1 int64, _a2 time.Time, _a3 models.EventFilter) ([]models.Event, string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 []models.Event
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) []models.Event); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, models.EventFilter) string); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, time.Time, models.EventFilter) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEventByWeek provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Calendar) GetEventByWeek(_a0 context.Context, _a1 int64, _a2 time.Time, _a3 models.EventFilter) ([]models.Event, string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 []models.Event
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, string, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) []models.Event); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, models.EventFilter) string); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, time.Time, models.EventFilter) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEventHistory provides a mock function with given fields: ctx, userID, eventID
+func (_m *Calendar) GetEventHistory(ctx context.Context, userID int64, eventID string) ([]models.HistoryEntry, error) {
+	ret := _m.Called(ctx, userID, eventID)
+
+	var r0 []models.HistoryEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]models.HistoryEntry, error)); ok {
+		return rf(ctx, userID, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []models.HistoryEntry); ok {
+		r0 = rf(ctx, userID, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.HistoryEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, eventID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,25 +388,25 @@ func (_m *Calendar) GetEventByDay(_a0 context.Context, _a1 int64, _a2 time.Time)
 	return r0, r1
 }
 
-// GetEventByMonth provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Calendar) GetEventByMonth(_a0 context.Context, _a1 int64, _a2 time.Time) ([]models.Event, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// GetEventsByUser provides a mock function with given fields: ctx, userID
+func (_m *Calendar) GetEventsByUser(ctx context.Context, userID int64) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID)
 
 	var r0 []models.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) ([]models.Event, error)); ok {
-		return rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Event, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []models.Event); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Event); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -106,25 +414,25 @@ func (_m *Calendar) GetEventByMonth(_a0 context.Context, _a1 int64, _a2 time.Tim
 	return r0, r1
 }
 
-// GetEventByWeek provides a mock function with given fields: _a0, _a1, _a2
-func (_m *Calendar) GetEventByWeek(_a0 context.Context, _a1 int64, _a2 time.Time) ([]models.Event, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// GetEventsInRange provides a mock function with given fields: ctx, userID, from, to
+func (_m *Calendar) GetEventsInRange(ctx context.Context, userID int64, from time.Time, to time.Time) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID, from, to)
 
 	var r0 []models.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) ([]models.Event, error)); ok {
-		return rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) ([]models.Event, error)); ok {
+		return rf(ctx, userID, from, to)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []models.Event); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) []models.Event); ok {
+		r0 = rf(ctx, userID, from, to)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, userID, from, to)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -132,13 +440,65 @@ func (_m *Calendar) GetEventByWeek(_a0 context.Context, _a1 int64, _a2 time.Time
 	return r0, r1
 }
 
-// UpdateEvent provides a mock function with given fields: _a0, _a1
-func (_m *Calendar) UpdateEvent(_a0 context.Context, _a1 *models.Event) error {
-	ret := _m.Called(_a0, _a1)
+// GetInvitations provides a mock function with given fields: ctx, userID, status
+func (_m *Calendar) GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) ([]models.Invitation, error) {
+	ret := _m.Called(ctx, userID, status)
+
+	var r0 []models.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.AttendeeStatus) ([]models.Invitation, error)); ok {
+		return rf(ctx, userID, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.AttendeeStatus) []models.Invitation); ok {
+		r0 = rf(ctx, userID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.AttendeeStatus) error); ok {
+		r1 = rf(ctx, userID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportEvents provides a mock function with given fields: ctx, userID, events, allowOverlap
+func (_m *Calendar) ImportEvents(ctx context.Context, userID int64, events []models.Event, allowOverlap bool) ([]string, error) {
+	ret := _m.Called(ctx, userID, events, allowOverlap)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.Event, bool) ([]string, error)); ok {
+		return rf(ctx, userID, events, allowOverlap)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.Event, bool) []string); ok {
+		r0 = rf(ctx, userID, events, allowOverlap)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []models.Event, bool) error); ok {
+		r1 = rf(ctx, userID, events, allowOverlap)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteAttendees provides a mock function with given fields: ctx, userID, eventID, attendeeIDs
+func (_m *Calendar) InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) error {
+	ret := _m.Called(ctx, userID, eventID, attendeeIDs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.Event) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, []int64) error); ok {
+		r0 = rf(ctx, userID, eventID, attendeeIDs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -146,6 +506,92 @@ func (_m *Calendar) UpdateEvent(_a0 context.Context, _a1 *models.Event) error {
 	return r0
 }
 
+// RespondToInvitation provides a mock function with given fields: ctx, userID, eventID, status
+func (_m *Calendar) RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) error {
+	ret := _m.Called(ctx, userID, eventID, status)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.AttendeeStatus) error); ok {
+		r0 = rf(ctx, userID, eventID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreEvent provides a mock function with given fields: ctx, userID, eventID
+func (_m *Calendar) RestoreEvent(ctx context.Context, userID int64, eventID string) error {
+	ret := _m.Called(ctx, userID, eventID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetACLEntry provides a mock function with given fields: ctx, userID, entry
+func (_m *Calendar) SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) error {
+	ret := _m.Called(ctx, userID, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.ACLEntry) error); ok {
+		r0 = rf(ctx, userID, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCalendar provides a mock function with given fields: ctx, userID, calendar
+func (_m *Calendar) UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) error {
+	ret := _m.Called(ctx, userID, calendar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.Calendar) error); ok {
+		r0 = rf(ctx, userID, calendar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEvent provides a mock function with given fields: ctx, userID, event
+func (_m *Calendar) UpdateEvent(ctx context.Context, userID int64, event *models.Event) error {
+	ret := _m.Called(ctx, userID, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.Event) error); ok {
+		r0 = rf(ctx, userID, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchEvents provides a mock function with given fields: userID
+func (_m *Calendar) WatchEvents(userID int64) *feed.Subscription {
+	ret := _m.Called(userID)
+
+	var r0 *feed.Subscription
+	if rf, ok := ret.Get(0).(func(int64) *feed.Subscription); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*feed.Subscription)
+		}
+	}
+
+	return r0
+}
+
 // NewCalendar creates a new instance of Calendar. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCalendar(t interface {
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+var ErrCalendarNotExist = errors.New("calendar does not exist")
+
+// CheckRole returns ErrForbidden unless the role grants the wanted one.
+func CheckRole(role, want models.Role) error {
+	if !role.Includes(want) {
+		return fmt.Errorf("%w: %s role is required", ErrForbidden, want)
+	}
+	return nil
+}
+
+// CanWriteEvent reports whether the user may change the event. A personal
+// event can be changed by its creator, an event of a calendar by the current
+// writers of the calendar only; role is the role of the user on that calendar.
+func CanWriteEvent(event *models.Event, userID int64, role models.Role) bool {
+	if event.CalendarID == nil {
+		return event.UserID == userID
+	}
+	return role.Includes(models.RoleWriter)
+}
+
+// CheckACLChange checks that the user described by calendar.Role may set or
+// remove the role of the member. The owner of the calendar always keeps the
+// owner role.
+func CheckACLChange(calendar *models.Calendar, memberID int64) error {
+	if err := CheckRole(calendar.Role, models.RoleOwner); err != nil {
+		return err
+	}
+	if memberID == calendar.OwnerID {
+		return fmt.Errorf("%w: role of the calendar owner can not be changed", ErrForbidden)
+	}
+	return nil
+}
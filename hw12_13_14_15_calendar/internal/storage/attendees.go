@@ -0,0 +1,28 @@
+package storage
+
+import "errors"
+
+var ErrInvitationNotExist = errors.New("invitation does not exist")
+
+// WithRecipients returns a copy of every notification for each of the users in
+// addition to the notification of the event owner.
+func WithRecipients(notifications []DueNotification, userIDs []int64) []DueNotification {
+	if len(userIDs) == 0 {
+		return notifications
+	}
+
+	all := make([]DueNotification, 0, len(notifications)*(len(userIDs)+1))
+	for _, notification := range notifications {
+		all = append(all, notification)
+
+		ownerID := notification.UserID
+		for _, userID := range userIDs {
+			if userID == ownerID {
+				continue
+			}
+			notification.UserID = userID
+			all = append(all, notification)
+		}
+	}
+	return all
+}
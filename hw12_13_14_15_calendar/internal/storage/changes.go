@@ -0,0 +1,33 @@
+package storage
+
+import "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+
+// ChangePublisher receives the changes of events once they are saved. Publish
+// must not block.
+type ChangePublisher interface {
+	Publish(change models.EventChange)
+}
+
+// NewEventChange converts the history entry to a change for the users.
+func NewEventChange(entry models.HistoryEntry, userIDs []int64) models.EventChange {
+	change := models.EventChange{
+		UserIDs:   userIDs,
+		ChangedAt: entry.ChangedAt,
+	}
+
+	switch entry.Action {
+	case models.ActionCreate, models.ActionRestore:
+		change.Type = models.ChangeCreated
+	case models.ActionUpdate:
+		change.Type = models.ChangeUpdated
+	case models.ActionDelete, models.ActionPurge:
+		change.Type = models.ChangeDeleted
+	}
+
+	if entry.After != nil {
+		change.Event = *entry.After
+	} else if entry.Before != nil {
+		change.Event = *entry.Before
+	}
+	return change
+}
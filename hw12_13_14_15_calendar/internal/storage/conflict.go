@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// conflictHorizon limits how far ahead occurrences of a recurring event are
+// checked for conflicts.
+const conflictHorizon = 366 * 24 * time.Hour
+
+var ErrDateBusy = errors.New("date is busy")
+
+// ConflictError describes an existing event that overlaps the one being saved.
+type ConflictError struct {
+	EventID   string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s: overlaps event %s from %s to %s", ErrDateBusy, e.EventID,
+		e.StartDate.Format(time.RFC3339), e.EndDate.Format(time.RFC3339))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrDateBusy
+}
+
+// ConflictWindow returns the interval that the event, or the checked
+// occurrences of a recurring event, occupy.
+func ConflictWindow(event *models.Event) (time.Time, time.Time) {
+	if !event.IsRecurring() {
+		return event.StartDate, event.EndDate
+	}
+	return event.StartDate, event.StartDate.Add(conflictHorizon).Add(event.EndDate.Sub(event.StartDate))
+}
+
+// ChangesSchedule reports whether the update touches the fields that define
+// when the event takes place.
+func ChangesSchedule(event *models.Event) bool {
+	return !event.StartDate.IsZero() || !event.EndDate.IsZero() || event.RecurrenceRule != nil ||
+		event.ExceptionDates != nil || event.Timezone != "" || event.AllDay != nil
+}
+
+// FindConflict returns a ConflictError for the first of the events that
+// overlaps the event. The event itself is skipped, so the events may include
+// its stored version. All-day events do not make the user busy and never
+// conflict.
+func FindConflict(event *models.Event, events []models.Event) error {
+	if event.IsAllDay() {
+		return nil
+	}
+
+	candidates := []models.Event{*event}
+	if event.IsRecurring() {
+		var err error
+		candidates, err = ExpandOccurrences(event, event.StartDate, event.StartDate.Add(conflictHorizon))
+		if err != nil {
+			return err
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	from := candidates[0].StartDate
+	to := candidates[len(candidates)-1].EndDate
+
+	for i := range events {
+		if events[i].ID == event.ID || events[i].IsAllDay() {
+			continue
+		}
+
+		occurrences, err := OverlappingEvents(&events[i], from, to)
+		if err != nil {
+			return err
+		}
+
+		for j := range occurrences {
+			for k := range candidates {
+				if Overlaps(&occurrences[j], candidates[k].StartDate, candidates[k].EndDate) {
+					return &ConflictError{
+						EventID:   occurrences[j].ID,
+						StartDate: occurrences[j].StartDate,
+						EndDate:   occurrences[j].EndDate,
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// MergeEvent copies the fields set in the event to the updated event.
+// An empty recurrence rule clears the rule of the updated event.
+func MergeEvent(updated *models.Event, event *models.Event) {
+	if event.Description != nil {
+		updated.Description = event.Description
+	}
+	if len(event.Title) != 0 {
+		updated.Title = event.Title
+	}
+	if !event.StartDate.IsZero() {
+		updated.StartDate = event.StartDate
+	}
+	if !event.Day.IsZero() {
+		updated.Day = event.Day
+	}
+	if !event.Week.IsZero() {
+		updated.Week = event.Week
+	}
+	if !event.Month.IsZero() {
+		updated.Month = event.Month
+	}
+	if !event.LastDay.IsZero() {
+		updated.LastDay = event.LastDay
+	}
+	if !event.LastWeek.IsZero() {
+		updated.LastWeek = event.LastWeek
+	}
+	if !event.LastMonth.IsZero() {
+		updated.LastMonth = event.LastMonth
+	}
+	if !event.EndDate.IsZero() {
+		updated.EndDate = event.EndDate
+	}
+	if event.NotificationTime != nil {
+		updated.NotificationTime = event.NotificationTime
+	}
+	if event.RecurrenceRule != nil {
+		updated.RecurrenceRule = event.RecurrenceRule
+		if *event.RecurrenceRule == "" {
+			updated.RecurrenceRule = nil
+		}
+	}
+	if event.ExceptionDates != nil {
+		updated.ExceptionDates = event.ExceptionDates
+	}
+	if event.Timezone != "" {
+		updated.Timezone = event.Timezone
+	}
+	if event.AllDay != nil {
+		updated.AllDay = event.AllDay
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// SchedulerActorID is the actor of the cleanup made by the scheduler.
+const SchedulerActorID int64 = 0
+
+// CanReadPurgedHistory reports whether the user may read the history of an
+// event that no longer exists: the history of a purged event stays available
+// to its owner.
+func CanReadPurgedHistory(history []models.HistoryEntry, userID int64) bool {
+	if len(history) == 0 {
+		return false
+	}
+	last := history[len(history)-1]
+	return last.Action == models.ActionPurge && last.Before != nil && last.Before.UserID == userID
+}
+
+// NewHistoryEntry records the change of the event made by the actor. The
+// request ID is taken from the context, where the HTTP middleware and the gRPC
+// interceptor put it. The snapshots are copied, so later changes of the events
+// do not affect the entry.
+func NewHistoryEntry(ctx context.Context, action models.HistoryAction, actorID int64, before, after *models.Event) models.HistoryEntry { //nolint:lll
+	entry := models.HistoryEntry{
+		Action:    action,
+		ActorID:   actorID,
+		RequestID: middleware.GetReqID(ctx),
+		Before:    snapshot(before),
+		After:     snapshot(after),
+		ChangedAt: time.Now().UTC(),
+	}
+
+	if before != nil {
+		entry.EventID = before.ID
+	} else if after != nil {
+		entry.EventID = after.ID
+	}
+	return entry
+}
+
+// snapshot copies the stored fields of the event.
+func snapshot(event *models.Event) *models.Event {
+	if event == nil {
+		return nil
+	}
+
+	copied := *event
+	copied.AllowOverlap = false
+	return &copied
+}
@@ -0,0 +1,107 @@
+package memorystorage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+// InviteAttendees invites the users to the event on behalf of the user, who
+// must be able to change the event. Users that are already invited keep their
+// response; the owner of the event is not invited.
+func (s *Storage) InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event, ok := s.events[eventID]
+	if !ok {
+		return storage.ErrEventNotExist
+	}
+	if !s.canWrite(event, userID) {
+		return storage.ErrForbidden
+	}
+
+	if _, ok := s.attendees[eventID]; !ok {
+		s.attendees[eventID] = make(map[int64]models.AttendeeStatus)
+	}
+	for _, attendeeID := range attendeeIDs {
+		if _, ok := s.attendees[eventID][attendeeID]; ok || attendeeID == event.UserID {
+			continue
+		}
+		s.attendees[eventID][attendeeID] = models.StatusNeedsAction
+	}
+	return nil
+}
+
+// RespondToInvitation saves the response of the user to the invitation.
+func (s *Storage) RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) error { //nolint:lll
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.attendees[eventID][userID]; !ok || s.events[eventID] == nil {
+		return storage.ErrInvitationNotExist
+	}
+
+	s.attendees[eventID][userID] = status
+	return nil
+}
+
+// GetInvitations returns the events the user is invited to ordered by start
+// date. If status is not empty, only invitations with that response are
+// returned. Recurring events are not expanded.
+func (s *Storage) GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) ([]models.Invitation, error) { //nolint:lll
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var invitations []models.Invitation
+	for eventID, attendees := range s.attendees {
+		response, ok := attendees[userID]
+		if !ok || (status != "" && response != status) || s.events[eventID] == nil {
+			continue
+		}
+		invitations = append(invitations, models.Invitation{Event: *s.events[eventID], Status: response})
+	}
+
+	sort.Slice(invitations, func(i, j int) bool {
+		if !invitations[i].StartDate.Equal(invitations[j].StartDate) {
+			return invitations[i].StartDate.Before(invitations[j].StartDate)
+		}
+		return invitations[i].ID < invitations[j].ID
+	})
+	return invitations, nil
+}
+
+// acceptedAttendees returns the users that accepted the event ordered by ID.
+func (s *Storage) acceptedAttendees(eventID string) []int64 {
+	var userIDs []int64
+	for userID, status := range s.attendees[eventID] {
+		if status == models.StatusAccepted {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	sort.Slice(userIDs, func(i, j int) bool {
+		return userIDs[i] < userIDs[j]
+	})
+	return userIDs
+}
@@ -0,0 +1,131 @@
+package memorystorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttendees(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newInvitation := func(t *testing.T) (*Storage, *models.Event) {
+		t.Helper()
+
+		memoryStorage := New()
+
+		event := generateEvents(day.Add(13*time.Hour), day.Add(15*time.Hour), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+		require.NoError(t, memoryStorage.InviteAttendees(ctx, 1, event.ID, []int64{1, 2, 3}))
+
+		return memoryStorage, &event
+	}
+
+	t.Run("invite attendees", func(t *testing.T) {
+		memoryStorage, event := newInvitation(t)
+
+		require.Equal(t, map[int64]models.AttendeeStatus{
+			2: models.StatusNeedsAction,
+			3: models.StatusNeedsAction,
+		}, memoryStorage.attendees[event.ID])
+
+		invitations, err := memoryStorage.GetInvitations(ctx, 2, "")
+		require.NoError(t, err)
+		require.Equal(t, []models.Invitation{{Event: *event, Status: models.StatusNeedsAction}}, invitations)
+
+		invitations, err = memoryStorage.GetInvitations(ctx, 2, models.StatusAccepted)
+		require.NoError(t, err)
+		require.Empty(t, invitations)
+	})
+
+	t.Run("invite keeps responses", func(t *testing.T) {
+		memoryStorage, event := newInvitation(t)
+
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 2, event.ID, models.StatusDeclined))
+		require.NoError(t, memoryStorage.InviteAttendees(ctx, 1, event.ID, []int64{2}))
+		require.Equal(t, models.StatusDeclined, memoryStorage.attendees[event.ID][2])
+	})
+
+	t.Run("only writers invite", func(t *testing.T) {
+		memoryStorage, event := newInvitation(t)
+
+		err := memoryStorage.InviteAttendees(ctx, 2, event.ID, []int64{4})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.InviteAttendees(ctx, 1, "id", []int64{4})
+		require.ErrorIs(t, err, storage.ErrEventNotExist)
+	})
+
+	t.Run("respond without invitation", func(t *testing.T) {
+		memoryStorage, event := newInvitation(t)
+
+		err := memoryStorage.RespondToInvitation(ctx, 4, event.ID, models.StatusAccepted)
+		require.ErrorIs(t, err, storage.ErrInvitationNotExist)
+	})
+
+	t.Run("accepted events are listed", func(t *testing.T) {
+		memoryStorage, event := newInvitation(t)
+
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 2, event.ID, models.StatusAccepted))
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 3, event.ID, models.StatusDeclined))
+
+		events, _, err := memoryStorage.GetEventByDay(ctx, 2, day, models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, event.ID, events[0].ID)
+
+		events, _, err = memoryStorage.GetEventByDay(ctx, 3, day, models.EventFilter{})
+		require.NoError(t, err)
+		require.Empty(t, events)
+
+		err = memoryStorage.UpdateEvent(ctx, 2, &models.Event{ID: event.ID, Title: "new title"})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("accepted attendees are notified", func(t *testing.T) {
+		memoryStorage := New()
+
+		notificationTime := time.Hour
+		event := generateEvents(day.Add(13*time.Hour), day.Add(15*time.Hour), 1)[0]
+		event.NotificationTime = &notificationTime
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+		require.NoError(t, memoryStorage.InviteAttendees(ctx, 1, event.ID, []int64{2, 3, 4}))
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 3, event.ID, models.StatusAccepted))
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 2, event.ID, models.StatusAccepted))
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 4, event.ID, models.StatusTentative))
+
+		enqueued, err := memoryStorage.EnqueueNotifications(ctx, day.Add(11*time.Hour), day.Add(12*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(3), enqueued)
+
+		var want []models.Notification
+		for _, userID := range []int64{1, 2, 3} {
+			want = append(want, models.Notification{
+				EventID: event.ID,
+				Title:   event.Title,
+				Date:    event.StartDate,
+				UserID:  userID,
+			})
+		}
+		require.Equal(t, want, drainOutbox(t, memoryStorage))
+	})
+
+	t.Run("delete event with attendees", func(t *testing.T) {
+		memoryStorage, event := newInvitation(t)
+
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+
+		invitations, err := memoryStorage.GetInvitations(ctx, 2, "")
+		require.NoError(t, err)
+		require.Empty(t, invitations)
+
+		_, err = memoryStorage.PurgeDeletedEvents(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Empty(t, memoryStorage.attendees)
+	})
+}
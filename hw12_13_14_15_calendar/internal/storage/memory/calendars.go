@@ -0,0 +1,231 @@
+package memorystorage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+// CreateCalendar saves the calendar and makes its owner the owner in the ACL.
+func (s *Storage) CreateCalendar(ctx context.Context, calendar *models.Calendar) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *calendar
+	stored.Role = ""
+	s.calendars[calendar.ID] = &stored
+	s.acl[calendar.ID] = map[int64]models.Role{calendar.OwnerID: models.RoleOwner}
+
+	return nil
+}
+
+// GetCalendar returns the calendar if the user can read it.
+func (s *Storage) GetCalendar(ctx context.Context, userID int64, calendarID string) (*models.Calendar, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	calendar, err := s.userCalendar(userID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.CheckRole(calendar.Role, models.RoleReader); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// GetCalendars returns the calendars the user has access to, ordered by name.
+func (s *Storage) GetCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var calendars []models.Calendar
+	for calendarID := range s.calendars {
+		if role := s.role(calendarID, userID); role.Valid() {
+			calendar := *s.calendars[calendarID]
+			calendar.Role = role
+			calendars = append(calendars, calendar)
+		}
+	}
+
+	sort.Slice(calendars, func(i, j int) bool {
+		if calendars[i].Name != calendars[j].Name {
+			return calendars[i].Name < calendars[j].Name
+		}
+		return calendars[i].ID < calendars[j].ID
+	})
+	return calendars, nil
+}
+
+// UpdateCalendar changes the non-empty fields of the calendar. Only owners may
+// change a calendar.
+func (s *Storage) UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.userCalendar(userID, calendar.ID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckRole(stored.Role, models.RoleOwner); err != nil {
+		return err
+	}
+
+	if calendar.Name != "" {
+		s.calendars[calendar.ID].Name = calendar.Name
+	}
+	if calendar.Timezone != "" {
+		s.calendars[calendar.ID].Timezone = calendar.Timezone
+	}
+	return nil
+}
+
+// DeleteCalendar deletes the calendar together with its events and ACL. Only
+// owners may delete a calendar. The purges of the events are recorded in the
+// history and published as deletes.
+func (s *Storage) DeleteCalendar(ctx context.Context, userID int64, calendarID string) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendar, err := s.userCalendar(userID, calendarID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckRole(calendar.Role, models.RoleOwner); err != nil {
+		return err
+	}
+
+	for _, events := range []events{s.events, s.trash} {
+		for _, event := range events {
+			if event.CalendarID != nil && *event.CalendarID == calendarID {
+				s.purgeEvent(ctx, userID, event)
+			}
+		}
+	}
+	delete(s.acl, calendarID)
+	delete(s.calendars, calendarID)
+
+	return nil
+}
+
+// GetACL returns the ACL of the calendar ordered by user ID. Only owners may
+// see the ACL.
+func (s *Storage) GetACL(ctx context.Context, userID int64, calendarID string) ([]models.ACLEntry, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	calendar, err := s.userCalendar(userID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.CheckRole(calendar.Role, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.ACLEntry, 0, len(s.acl[calendarID]))
+	for memberID, role := range s.acl[calendarID] {
+		entries = append(entries, models.ACLEntry{CalendarID: calendarID, UserID: memberID, Role: role})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].UserID < entries[j].UserID
+	})
+	return entries, nil
+}
+
+// SetACLEntry grants the member the role on the calendar, replacing the
+// previous one.
+func (s *Storage) SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendar, err := s.userCalendar(userID, entry.CalendarID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckACLChange(calendar, entry.UserID); err != nil {
+		return err
+	}
+
+	s.acl[entry.CalendarID][entry.UserID] = entry.Role
+	return nil
+}
+
+// DeleteACLEntry revokes the access of the member to the calendar.
+func (s *Storage) DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendar, err := s.userCalendar(userID, calendarID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckACLChange(calendar, memberID); err != nil {
+		return err
+	}
+
+	delete(s.acl[calendarID], memberID)
+	return nil
+}
+
+// userCalendar returns a copy of the calendar with the role of the user.
+func (s *Storage) userCalendar(userID int64, calendarID string) (*models.Calendar, error) {
+	stored, ok := s.calendars[calendarID]
+	if !ok {
+		return nil, storage.ErrCalendarNotExist
+	}
+
+	calendar := *stored
+	calendar.Role = s.role(calendarID, userID)
+	return &calendar, nil
+}
@@ -0,0 +1,216 @@
+package memorystorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ownerID    = int64(1)
+	writerID   = int64(2)
+	readerID   = int64(3)
+	strangerID = int64(4)
+)
+
+// newSharedCalendar returns a storage with a calendar of ownerID shared with
+// writerID and readerID, and an event of the owner in that calendar.
+func newSharedCalendar(t *testing.T) (*Storage, *models.Event) {
+	t.Helper()
+
+	ctx := context.Background()
+	memoryStorage := New()
+
+	calendar := &models.Calendar{ID: "calendar-1", OwnerID: ownerID, Name: "team", Timezone: "UTC"}
+	require.NoError(t, memoryStorage.CreateCalendar(ctx, calendar))
+	require.NoError(t, memoryStorage.SetACLEntry(ctx, ownerID,
+		models.ACLEntry{CalendarID: calendar.ID, UserID: writerID, Role: models.RoleWriter}))
+	require.NoError(t, memoryStorage.SetACLEntry(ctx, ownerID,
+		models.ACLEntry{CalendarID: calendar.ID, UserID: readerID, Role: models.RoleReader}))
+
+	event := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)[0]
+	event.CalendarID = &calendar.ID
+	require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+	return memoryStorage, &event
+}
+
+func TestCalendarAccess(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("members read shared events", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		for _, userID := range []int64{ownerID, writerID, readerID} {
+			events, _, err := memoryStorage.GetEventByDay(ctx, userID, day, models.EventFilter{})
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			require.Equal(t, event.ID, events[0].ID)
+		}
+
+		events, _, err := memoryStorage.GetEventByDay(ctx, strangerID, day, models.EventFilter{})
+		require.NoError(t, err)
+		require.Empty(t, events)
+
+		events, err = memoryStorage.GetEventsInRange(ctx, readerID, day, day.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+	})
+
+	t.Run("writer changes shared events", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		err := memoryStorage.UpdateEvent(ctx, writerID, &models.Event{ID: event.ID, Title: "new title"})
+		require.NoError(t, err)
+		require.Equal(t, "new title", memoryStorage.events[event.ID].Title)
+
+		err = memoryStorage.DeleteEvent(ctx, writerID, event.ID, 0)
+		require.NoError(t, err)
+		require.NotContains(t, memoryStorage.events, event.ID)
+	})
+
+	t.Run("reader can not change shared events", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		err := memoryStorage.UpdateEvent(ctx, readerID, &models.Event{ID: event.ID, Title: "new title"})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.DeleteEvent(ctx, readerID, event.ID, 0)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		newEvent := generateEvents(day.Add(16*time.Hour), day.Add(17*time.Hour), 1)[0]
+		newEvent.UserID = readerID
+		newEvent.CalendarID = event.CalendarID
+		err = memoryStorage.CreateEvent(ctx, &newEvent)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("demoted creator can not change shared events", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		newEvent := generateEvents(day.Add(16*time.Hour), day.Add(17*time.Hour), 1)[0]
+		newEvent.UserID = writerID
+		newEvent.CalendarID = event.CalendarID
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &newEvent))
+
+		require.NoError(t, memoryStorage.SetACLEntry(ctx, ownerID,
+			models.ACLEntry{CalendarID: *event.CalendarID, UserID: writerID, Role: models.RoleReader}))
+
+		err := memoryStorage.UpdateEvent(ctx, writerID, &models.Event{ID: newEvent.ID, Title: "new title"})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.DeleteEvent(ctx, writerID, newEvent.ID, 0)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		require.NoError(t, memoryStorage.DeleteACLEntry(ctx, ownerID, *event.CalendarID, writerID))
+
+		err = memoryStorage.DeleteEvent(ctx, writerID, newEvent.ID, 0)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("calendar does not exist", func(t *testing.T) {
+		memoryStorage := New()
+
+		calendarID := "calendar-1"
+		event := generateEvents(day.Add(13*time.Hour), day.Add(15*time.Hour), 1)[0]
+		event.CalendarID = &calendarID
+		err := memoryStorage.CreateEvent(ctx, &event)
+		require.ErrorIs(t, err, storage.ErrCalendarNotExist)
+
+		_, err = memoryStorage.GetCalendar(ctx, ownerID, calendarID)
+		require.ErrorIs(t, err, storage.ErrCalendarNotExist)
+	})
+}
+
+func TestCalendarACL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("list calendars and ACL", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		calendars, err := memoryStorage.GetCalendars(ctx, readerID)
+		require.NoError(t, err)
+		require.Len(t, calendars, 1)
+		require.Equal(t, models.RoleReader, calendars[0].Role)
+
+		calendars, err = memoryStorage.GetCalendars(ctx, strangerID)
+		require.NoError(t, err)
+		require.Empty(t, calendars)
+
+		entries, err := memoryStorage.GetACL(ctx, ownerID, *event.CalendarID)
+		require.NoError(t, err)
+		require.Equal(t, []models.ACLEntry{
+			{CalendarID: *event.CalendarID, UserID: ownerID, Role: models.RoleOwner},
+			{CalendarID: *event.CalendarID, UserID: writerID, Role: models.RoleWriter},
+			{CalendarID: *event.CalendarID, UserID: readerID, Role: models.RoleReader},
+		}, entries)
+
+		_, err = memoryStorage.GetACL(ctx, writerID, *event.CalendarID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("only owner manages the calendar", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		err := memoryStorage.SetACLEntry(ctx, writerID,
+			models.ACLEntry{CalendarID: *event.CalendarID, UserID: strangerID, Role: models.RoleReader})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.UpdateCalendar(ctx, writerID, &models.Calendar{ID: *event.CalendarID, Name: "new"})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.DeleteCalendar(ctx, writerID, *event.CalendarID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.UpdateCalendar(ctx, ownerID, &models.Calendar{ID: *event.CalendarID, Name: "new"})
+		require.NoError(t, err)
+
+		calendar, err := memoryStorage.GetCalendar(ctx, ownerID, *event.CalendarID)
+		require.NoError(t, err)
+		require.Equal(t, "new", calendar.Name)
+		require.Equal(t, "UTC", calendar.Timezone)
+	})
+
+	t.Run("owner entry is immutable", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		err := memoryStorage.SetACLEntry(ctx, ownerID,
+			models.ACLEntry{CalendarID: *event.CalendarID, UserID: ownerID, Role: models.RoleReader})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+
+		err = memoryStorage.DeleteACLEntry(ctx, ownerID, *event.CalendarID, ownerID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("revoked member loses access", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		err := memoryStorage.DeleteACLEntry(ctx, ownerID, *event.CalendarID, readerID)
+		require.NoError(t, err)
+
+		_, err = memoryStorage.GetCalendar(ctx, readerID, *event.CalendarID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("delete calendar with its events", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		err := memoryStorage.DeleteCalendar(ctx, ownerID, *event.CalendarID)
+		require.NoError(t, err)
+
+		require.Empty(t, memoryStorage.events)
+		require.Empty(t, memoryStorage.calendars)
+		require.Empty(t, memoryStorage.acl)
+
+		history, err := memoryStorage.GetEventHistory(ctx, ownerID, event.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.ActionPurge, history[len(history)-1].Action)
+		require.Equal(t, ownerID, history[len(history)-1].ActorID)
+	})
+}
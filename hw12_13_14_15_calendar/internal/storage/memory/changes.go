@@ -0,0 +1,54 @@
+package memorystorage
+
+import (
+	"sort"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+// SetChangePublisher publishes the changes of events to the publisher. It must
+// be called before the storage is used.
+func (s *Storage) SetChangePublisher(publisher storage.ChangePublisher) {
+	s.publisher = publisher
+}
+
+// publish sends the change to the users who could read the event before or
+// after it. It must be called with the lock held.
+func (s *Storage) publish(entry models.HistoryEntry) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.Publish(storage.NewEventChange(entry, s.readers(entry)))
+}
+
+// readers returns the owner of the event, the readers of its calendar and the
+// users that accepted it, ordered by ID.
+func (s *Storage) readers(entry models.HistoryEntry) []int64 {
+	users := make(map[int64]struct{})
+	for _, event := range []*models.Event{entry.Before, entry.After} {
+		if event == nil {
+			continue
+		}
+		users[event.UserID] = struct{}{}
+		if event.CalendarID != nil {
+			for userID, role := range s.acl[*event.CalendarID] {
+				if role.Includes(models.RoleReader) {
+					users[userID] = struct{}{}
+				}
+			}
+		}
+	}
+	for _, userID := range s.acceptedAttendees(entry.EventID) {
+		users[userID] = struct{}{}
+	}
+
+	userIDs := make([]int64, 0, len(users))
+	for userID := range users {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		return userIDs[i] < userIDs[j]
+	})
+	return userIDs
+}
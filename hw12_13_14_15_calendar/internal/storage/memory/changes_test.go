@@ -0,0 +1,102 @@
+package memorystorage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+type publisherMock struct {
+	changes []models.EventChange
+}
+
+func (p *publisherMock) Publish(change models.EventChange) {
+	p.changes = append(p.changes, change)
+}
+
+func TestChanges(t *testing.T) {
+	ctx := context.Background()
+
+	memoryStorage, event := newSharedCalendar(t)
+	publisher := &publisherMock{}
+	memoryStorage.SetChangePublisher(publisher)
+
+	require.NoError(t, memoryStorage.InviteAttendees(ctx, ownerID, event.ID, []int64{strangerID}))
+	require.NoError(t, memoryStorage.RespondToInvitation(ctx, strangerID, event.ID, models.StatusAccepted))
+
+	updated := *event
+	updated.Title = "updated"
+	require.NoError(t, memoryStorage.UpdateEvent(ctx, writerID, &updated))
+	require.NoError(t, memoryStorage.DeleteEvent(ctx, ownerID, event.ID, 0))
+	require.NoError(t, memoryStorage.RestoreEvent(ctx, ownerID, event.ID))
+
+	require.Len(t, publisher.changes, 3)
+
+	users := []int64{ownerID, writerID, readerID, strangerID}
+	for i, changeType := range []models.ChangeType{models.ChangeUpdated, models.ChangeDeleted, models.ChangeCreated} {
+		change := publisher.changes[i]
+		require.Equal(t, changeType, change.Type)
+		require.Equal(t, event.ID, change.Event.ID)
+		require.Equal(t, "updated", change.Event.Title)
+		require.Equal(t, users, change.UserIDs)
+	}
+	require.NotNil(t, publisher.changes[1].Event.DeletedAt)
+	require.Nil(t, publisher.changes[2].Event.DeletedAt)
+}
+
+func TestPurgeChanges(t *testing.T) {
+	ctx := context.Background()
+	users := []int64{ownerID, writerID, readerID, strangerID}
+
+	t.Run("calendar is deleted", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+		require.NoError(t, memoryStorage.InviteAttendees(ctx, ownerID, event.ID, []int64{strangerID}))
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, strangerID, event.ID, models.StatusAccepted))
+
+		publisher := &publisherMock{}
+		memoryStorage.SetChangePublisher(publisher)
+
+		require.NoError(t, memoryStorage.DeleteCalendar(ctx, ownerID, *event.CalendarID))
+
+		require.Len(t, publisher.changes, 1)
+		require.Equal(t, models.ChangeDeleted, publisher.changes[0].Type)
+		require.Equal(t, event.ID, publisher.changes[0].Event.ID)
+		require.Equal(t, users, publisher.changes[0].UserIDs)
+	})
+
+	t.Run("trash is purged", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, ownerID, event.ID, 0))
+
+		publisher := &publisherMock{}
+		memoryStorage.SetChangePublisher(publisher)
+
+		purged, err := memoryStorage.PurgeDeletedEvents(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), purged)
+
+		require.Len(t, publisher.changes, 1)
+		require.Equal(t, models.ChangeDeleted, publisher.changes[0].Type)
+		require.Equal(t, event.ID, publisher.changes[0].Event.ID)
+		require.Equal(t, users[:3], publisher.changes[0].UserIDs)
+	})
+
+	t.Run("old events are deleted", func(t *testing.T) {
+		memoryStorage, event := newSharedCalendar(t)
+
+		publisher := &publisherMock{}
+		memoryStorage.SetChangePublisher(publisher)
+
+		deleted, err := memoryStorage.DeleteEventsBefore(ctx, event.EndDate.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		require.Len(t, publisher.changes, 1)
+		require.Equal(t, models.ChangeDeleted, publisher.changes[0].Type)
+		require.Equal(t, event.ID, publisher.changes[0].Event.ID)
+		require.Equal(t, users[:3], publisher.changes[0].UserIDs)
+	})
+}
@@ -2,6 +2,7 @@ package memorystorage
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -17,20 +18,45 @@ type (
 	dates  map[time.Time]map[id]struct{}
 )
 
+type outboxKey struct {
+	eventID  id
+	userID   int64
+	notifyAt int64
+}
+
 type Storage struct {
-	events events
-	days   dates
-	weeks  dates
-	months dates
-	mu     sync.RWMutex
+	events    events
+	trash     events
+	days      dates
+	weeks     dates
+	months    dates
+	recurring map[id]struct{}
+	calendars map[id]*models.Calendar
+	acl       map[id]map[int64]models.Role
+	attendees map[id]map[int64]models.AttendeeStatus
+	history   map[id][]models.HistoryEntry
+	historyID int64
+	publisher storage.ChangePublisher
+	mu        sync.RWMutex
+
+	outbox   map[outboxKey]models.Notification
+	pending  []outboxKey
+	outboxMu sync.Mutex
 }
 
 func New() *Storage {
 	return &Storage{
-		events: make(events),
-		days:   make(dates),
-		weeks:  make(dates),
-		months: make(dates),
+		events:    make(events),
+		trash:     make(events),
+		days:      make(dates),
+		weeks:     make(dates),
+		months:    make(dates),
+		recurring: make(map[id]struct{}),
+		calendars: make(map[id]*models.Calendar),
+		acl:       make(map[id]map[int64]models.Role),
+		attendees: make(map[id]map[int64]models.AttendeeStatus),
+		history:   make(map[id][]models.HistoryEntry),
+		outbox:    make(map[outboxKey]models.Notification),
 	}
 }
 
@@ -41,43 +67,179 @@ func (s *Storage) CreateEvent(ctx context.Context, event *models.Event) error {
 	default:
 	}
 
-	storage.FillDates(event)
+	prepareEvent(event)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.events[event.ID] = event
-	s.saveDates(event.ID, event.Day, event.Week, event.Month)
+	if err := s.checkCreate(event, nil); err != nil {
+		return err
+	}
+	s.saveEvent(ctx, event)
 
 	return nil
 }
 
-func (s *Storage) saveDates(eventID string, day, week, month time.Time) {
-	if _, ok := s.days[day]; !ok {
-		s.days[day] = make(map[id]struct{})
+// CreateEvents saves the events that do not exist yet. The events are checked
+// before any of them is saved, so none of them is saved if one fails. It
+// returns the IDs of the saved events.
+func (s *Storage) CreateEvents(ctx context.Context, events []*models.Event) ([]string, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	for _, event := range events {
+		prepareEvent(event)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		created []*models.Event
+		batch   []models.Event
+		ids     = make(map[id]struct{})
+	)
+	for _, event := range events {
+		_, active := s.events[event.ID]
+		_, trashed := s.trash[event.ID]
+		_, seen := ids[event.ID]
+		if active || trashed || seen {
+			continue
+		}
+		if err := s.checkCreate(event, batch); err != nil {
+			return nil, fmt.Errorf("event %q: %w", event.Title, err)
+		}
+		created = append(created, event)
+		batch = append(batch, *event)
+		ids[event.ID] = struct{}{}
+	}
+
+	createdIDs := make([]string, 0, len(created))
+	for _, event := range created {
+		s.saveEvent(ctx, event)
+		createdIDs = append(createdIDs, event.ID)
+	}
+	return createdIDs, nil
+}
+
+func prepareEvent(event *models.Event) {
+	storage.FillDates(event)
+	if !event.IsRecurring() {
+		event.RecurrenceRule = nil
 	}
-	s.days[day][eventID] = struct{}{}
+	event.Version = 1
+}
 
-	if _, ok := s.weeks[week]; !ok {
-		s.weeks[week] = make(map[id]struct{})
+// checkCreate checks that the user can write to the calendar of the new event
+// and that it overlaps neither the events of the user nor the pending ones.
+func (s *Storage) checkCreate(event *models.Event, pending []models.Event) error {
+	if err := models.CheckEventDuration(event.StartDate, event.EndDate); err != nil {
+		return err
+	}
+	if event.CalendarID != nil {
+		if _, ok := s.calendars[*event.CalendarID]; !ok {
+			return storage.ErrCalendarNotExist
+		}
+		if err := storage.CheckRole(s.role(*event.CalendarID, event.UserID), models.RoleWriter); err != nil {
+			return err
+		}
 	}
-	s.weeks[week][eventID] = struct{}{}
 
-	if _, ok := s.months[month]; !ok {
-		s.months[month] = make(map[id]struct{})
+	if !event.AllowOverlap {
+		var others []models.Event
+		for i := range pending {
+			if pending[i].UserID == event.UserID {
+				others = append(others, pending[i])
+			}
+		}
+		if err := storage.FindConflict(event, append(s.userEvents(event.UserID), others...)); err != nil {
+			return err
+		}
 	}
-	s.months[month][eventID] = struct{}{}
+	return nil
 }
 
-func (s *Storage) UpdateEvent(ctx context.Context, event *models.Event) error {
+func (s *Storage) saveEvent(ctx context.Context, event *models.Event) {
+	s.events[event.ID] = event
+	s.index(event)
+	s.record(storage.NewHistoryEntry(ctx, models.ActionCreate, event.UserID, nil, event))
+}
+
+// index adds the event to every day, week and month it touches. Recurring
+// events are expanded on read instead.
+func (s *Storage) index(event *models.Event) {
+	if event.IsRecurring() {
+		s.recurring[event.ID] = struct{}{}
+		return
+	}
+	s.days.save(event.ID, event.Day, event.LastDay, nextDay)
+	s.weeks.save(event.ID, event.Week, event.LastWeek, nextWeek)
+	s.months.save(event.ID, event.Month, event.LastMonth, nextMonth)
+}
+
+func (s *Storage) unindex(event *models.Event) {
+	if event.IsRecurring() {
+		delete(s.recurring, event.ID)
+		return
+	}
+	s.days.delete(event.ID, event.Day, event.LastDay, nextDay)
+	s.weeks.delete(event.ID, event.Week, event.LastWeek, nextWeek)
+	s.months.delete(event.ID, event.Month, event.LastMonth, nextMonth)
+}
+
+func (s *Storage) userEvents(userID int64) []models.Event {
+	var events []models.Event
+	for _, event := range s.events {
+		if event.UserID == userID {
+			events = append(events, *event)
+		}
+	}
+	return events
+}
+
+// role returns the role of the user on the calendar, or an empty role if the
+// user has no access to it.
+func (s *Storage) role(calendarID string, userID int64) models.Role {
+	return s.acl[calendarID][userID]
+}
+
+// canRead reports whether the event is one of the user, belongs to a calendar
+// the user can read or is accepted by the user.
+func (s *Storage) canRead(event *models.Event, userID int64) bool {
+	if event.UserID == userID || s.attendees[event.ID][userID] == models.StatusAccepted {
+		return true
+	}
+	return event.CalendarID != nil && s.role(*event.CalendarID, userID).Includes(models.RoleReader)
+}
+
+func (s *Storage) canWrite(event *models.Event, userID int64) bool {
+	var role models.Role
+	if event.CalendarID != nil {
+		role = s.role(*event.CalendarID, userID)
+	}
+	return storage.CanWriteEvent(event, userID, role)
+}
+
+// save adds the event to the buckets from first to last.
+func (d dates) save(eventID string, first, last time.Time, next func(time.Time) time.Time) {
+	for bucket := first; !bucket.After(last); bucket = next(bucket) {
+		if _, ok := d[bucket]; !ok {
+			d[bucket] = make(map[id]struct{})
+		}
+		d[bucket][eventID] = struct{}{}
+	}
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, userID int64, event *models.Event) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	default:
 	}
 
-	storage.FillDates(event)
-
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -85,41 +247,125 @@ func (s *Storage) UpdateEvent(ctx context.Context, event *models.Event) error {
 	if !ok {
 		return storage.ErrEventNotExist
 	}
+	if !s.canWrite(updated, userID) {
+		return storage.ErrForbidden
+	}
+	if err := storage.CheckVersion(updated, event.Version); err != nil {
+		return err
+	}
 
-	updateEventFields(updated, event)
-	s.events[event.ID] = updated
+	merged := *updated
+	storage.MergeEvent(&merged, event)
+	if err := models.CheckEventDuration(merged.StartDate, merged.EndDate); err != nil {
+		return err
+	}
+
+	if !event.AllowOverlap && storage.ChangesSchedule(event) {
+		if err := storage.FindConflict(&merged, s.userEvents(merged.UserID)); err != nil {
+			return err
+		}
+	}
+
+	before := *updated
+	s.unindex(updated)
+	storage.MergeEvent(updated, event)
+	storage.FillDates(updated)
+	updated.Version++
+	event.Version = updated.Version
+	s.index(updated)
+	s.record(storage.NewHistoryEntry(ctx, models.ActionUpdate, userID, &before, updated))
 
 	return nil
 }
 
-func updateEventFields(updated *models.Event, event *models.Event) {
-	if event.Description != nil {
-		updated.Description = event.Description
-	}
-	if len(event.Title) != 0 {
-		updated.Title = event.Title
+// DeleteEvent moves the event to the trash. A non-zero version must match the
+// version of the event.
+func (s *Storage) DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
 	}
-	if !event.StartDate.IsZero() {
-		updated.StartDate = event.StartDate
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted, ok := s.events[eventID]
+	if !ok {
+		return storage.ErrEventNotExist
 	}
-	if !event.Day.IsZero() {
-		updated.Day = event.Day
+	if !s.canWrite(deleted, userID) {
+		return storage.ErrForbidden
 	}
-	if !event.Week.IsZero() {
-		updated.Week = event.Week
+	if err := storage.CheckVersion(deleted, version); err != nil {
+		return err
 	}
-	if !event.Month.IsZero() {
-		updated.Month = event.Month
+
+	before := *deleted
+	s.trashEvent(deleted)
+	s.record(storage.NewHistoryEntry(ctx, models.ActionDelete, userID, &before, deleted))
+	return nil
+}
+
+// trashEvent moves the event to the trash. The attendees of the event are kept
+// for a restore, its unsent notifications are dropped.
+func (s *Storage) trashEvent(event *models.Event) {
+	s.unindex(event)
+	s.deletePendingOutbox(event.ID)
+	delete(s.events, event.ID)
+
+	deletedAt := time.Now().UTC()
+	event.DeletedAt = &deletedAt
+	event.Version++
+	s.trash[event.ID] = event
+}
+
+// deleteEvent removes the event together with its attendees and notifications.
+func (s *Storage) deleteEvent(event *models.Event) {
+	if event.DeletedAt == nil {
+		s.unindex(event)
 	}
-	if !event.EndDate.IsZero() {
-		updated.EndDate = event.EndDate
+	s.deleteOutbox(event.ID)
+	delete(s.attendees, event.ID)
+	delete(s.events, event.ID)
+	delete(s.trash, event.ID)
+}
+
+// purgeEvent deletes the event permanently and records it in the history, on
+// behalf of the actor. The purge is recorded first, so the change is published
+// to the attendees that are deleted with the event.
+func (s *Storage) purgeEvent(ctx context.Context, actorID int64, event *models.Event) {
+	before := *event
+	s.record(storage.NewHistoryEntry(ctx, models.ActionPurge, actorID, &before, nil))
+	s.deleteEvent(event)
+}
+
+// GetDeletedEvents returns the trashed events the user may restore, the most
+// recently deleted first.
+func (s *Storage) GetDeletedEvents(ctx context.Context, userID int64) ([]models.Event, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
 	}
-	if event.NotificationTime != nil {
-		updated.NotificationTime = event.NotificationTime
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var events []models.Event
+	for _, event := range s.trash {
+		if s.canWrite(event, userID) {
+			events = append(events, *event)
+		}
 	}
+
+	storage.SortByDeletedAt(events)
+	return events, nil
 }
 
-func (s *Storage) DeleteEvent(ctx context.Context, eventID string) error {
+// RestoreEvent moves the event back from the trash unless it overlaps another
+// event of its owner.
+func (s *Storage) RestoreEvent(ctx context.Context, userID int64, eventID string) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
@@ -129,35 +375,110 @@ func (s *Storage) DeleteEvent(ctx context.Context, eventID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	deleted, ok := s.events[eventID]
+	event, ok := s.trash[eventID]
 	if !ok {
 		return storage.ErrEventNotExist
 	}
+	if !s.canWrite(event, userID) {
+		return storage.ErrForbidden
+	}
+	if err := storage.FindConflict(event, s.userEvents(event.UserID)); err != nil {
+		return err
+	}
 
-	s.deleteDates(eventID, deleted.Day, deleted.Week, deleted.Month)
-	delete(s.events, eventID)
+	before := *event
+	delete(s.trash, eventID)
+	event.DeletedAt = nil
+	event.Version++
+	s.events[eventID] = event
+	s.index(event)
+	s.record(storage.NewHistoryEntry(ctx, models.ActionRestore, userID, &before, event))
 
 	return nil
 }
 
-func (s *Storage) deleteDates(eventID string, day, week, month time.Time) {
-	delete(s.days[day], eventID)
-	if len(s.days[day]) == 0 {
-		delete(s.days, day)
+// record appends the entry to the history of its event and publishes the
+// change.
+func (s *Storage) record(entry models.HistoryEntry) {
+	s.historyID++
+	entry.ID = s.historyID
+	s.history[entry.EventID] = append(s.history[entry.EventID], entry)
+	s.publish(entry)
+}
+
+// GetEventHistory returns the changes of the event from the oldest one. The
+// history of an event is available to the users who can read it, or restore
+// it while it is in the trash. The history of a purged event is kept for its
+// owner.
+func (s *Storage) GetEventHistory(ctx context.Context, userID int64, eventID string) ([]models.HistoryEntry, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if event, ok := s.events[eventID]; ok {
+		if !s.canRead(event, userID) {
+			return nil, storage.ErrForbidden
+		}
+	} else if event, ok := s.trash[eventID]; ok {
+		if !s.canWrite(event, userID) {
+			return nil, storage.ErrForbidden
+		}
+	} else if !storage.CanReadPurgedHistory(s.history[eventID], userID) {
+		return nil, storage.ErrEventNotExist
+	}
+
+	return append([]models.HistoryEntry(nil), s.history[eventID]...), nil
+}
+
+// CountEvents returns the number of stored events and events in the trash.
+func (s *Storage) CountEvents() (active, deleted int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.events), len(s.trash)
+}
+
+// PurgeDeletedEvents permanently deletes the events that were moved to the
+// trash before the date. The purges are recorded in the history and published
+// as deletes.
+func (s *Storage) PurgeDeletedEvents(ctx context.Context, before time.Time) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
 	}
 
-	delete(s.weeks[week], eventID)
-	if len(s.weeks[week]) == 0 {
-		delete(s.weeks, week)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for _, event := range s.trash {
+		if event.DeletedAt.Before(before) {
+			s.purgeEvent(ctx, storage.SchedulerActorID, event)
+			purged++
+		}
 	}
+	return purged, nil
+}
 
-	delete(s.months[month], eventID)
-	if len(s.months[month]) == 0 {
-		delete(s.months, month)
+// delete removes the event from the buckets from first to last.
+func (d dates) delete(eventID string, first, last time.Time, next func(time.Time) time.Time) {
+	for bucket := first; !bucket.After(last); bucket = next(bucket) {
+		delete(d[bucket], eventID)
+		if len(d[bucket]) == 0 {
+			delete(d, bucket)
+		}
 	}
 }
 
-func (s *Storage) GetEventByDay(ctx context.Context, userID int64, day time.Time) ([]models.Event, error) {
+// GetEvent returns the event if the user can read it. Recurring events are
+// not expanded.
+func (s *Storage) GetEvent(ctx context.Context, userID int64, eventID string) (*models.Event, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -166,10 +487,67 @@ func (s *Storage) GetEventByDay(ctx context.Context, userID int64, day time.Time
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.getSortedEventsByIDs(userID, s.days[day]), nil
+
+	event, ok := s.events[eventID]
+	if !ok {
+		return nil, storage.ErrEventNotExist
+	}
+	if !s.canRead(event, userID) {
+		return nil, storage.ErrForbidden
+	}
+
+	found := *event
+	return &found, nil
+}
+
+func (s *Storage) GetEventByDay(ctx context.Context, userID int64, day time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	select {
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to := storage.DayRange(day)
+	first, last := storage.DayBuckets(from, to)
+	return s.getSortedEvents(userID, bucketIDs(s.days, first, last, nextDay), from, to, filter)
+}
+
+func (s *Storage) GetEventByWeek(ctx context.Context, userID int64, week time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	select {
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to := storage.WeekRange(week)
+	first, last := storage.WeekBuckets(from, to)
+	return s.getSortedEvents(userID, bucketIDs(s.weeks, first, last, nextWeek), from, to, filter)
 }
 
-func (s *Storage) GetEventByWeek(ctx context.Context, userID int64, week time.Time) ([]models.Event, error) {
+func (s *Storage) GetEventByMonth(ctx context.Context, userID int64, month time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	select {
+	case <-ctx.Done():
+		return nil, "", ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to := storage.MonthRange(month)
+	first, last := storage.MonthBuckets(from, to)
+	return s.getSortedEvents(userID, bucketIDs(s.months, first, last, nextMonth), from, to, filter)
+}
+
+// GetEventsByUser returns the stored events of the user. Recurring events are
+// not expanded.
+func (s *Storage) GetEventsByUser(ctx context.Context, userID int64) ([]models.Event, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -178,10 +556,15 @@ func (s *Storage) GetEventByWeek(ctx context.Context, userID int64, week time.Ti
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.getSortedEventsByIDs(userID, s.weeks[week]), nil
+
+	events := s.userEvents(userID)
+	storage.SortByStartDate(events)
+	return events, nil
 }
 
-func (s *Storage) GetEventByMonth(ctx context.Context, userID int64, month time.Time) ([]models.Event, error) {
+// GetEventsInRange returns the events readable by the user and occurrences of
+// recurring events that overlap the [from, to) interval.
+func (s *Storage) GetEventsInRange(ctx context.Context, userID int64, from, to time.Time) ([]models.Event, error) {
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -190,23 +573,232 @@ func (s *Storage) GetEventByMonth(ctx context.Context, userID int64, month time.
 
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.getSortedEventsByIDs(userID, s.months[month]), nil
+
+	var events []models.Event
+	for _, event := range s.events {
+		if !s.canRead(event, userID) {
+			continue
+		}
+
+		overlapping, err := storage.OverlappingEvents(event, from, to)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, overlapping...)
+	}
+
+	storage.SortByStartDate(events)
+	return events, nil
 }
 
-func (s *Storage) getSortedEventsByIDs(userID int64, ids map[id]struct{}) []models.Event {
-	if len(ids) == 0 {
-		return nil
+// bucketIDs returns the IDs of the events in the buckets from first to last.
+func bucketIDs(buckets dates, first, last time.Time, next func(time.Time) time.Time) map[id]struct{} {
+	ids := make(map[id]struct{})
+	for bucket := first; !bucket.After(last); bucket = next(bucket) {
+		for id := range buckets[bucket] {
+			ids[id] = struct{}{}
+		}
 	}
+	return ids
+}
+
+func nextDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1)
+}
+
+func nextWeek(week time.Time) time.Time {
+	return week.AddDate(0, 0, 7)
+}
 
+func nextMonth(month time.Time) time.Time {
+	return month.AddDate(0, 1, 0)
+}
+
+func (s *Storage) getSortedEvents(userID int64, ids map[id]struct{}, from, to time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
 	events := make([]models.Event, 0, len(ids))
 	for id := range ids {
-		if userID == s.events[id].UserID {
+		if s.canRead(s.events[id], userID) && storage.OccursIn(s.events[id], from, to) {
 			events = append(events, *s.events[id])
 		}
 	}
 
-	sort.Slice(events, func(i, j int) bool {
-		return events[i].StartDate.Before(events[j].StartDate)
+	for id := range s.recurring {
+		if !s.canRead(s.events[id], userID) {
+			continue
+		}
+
+		occurrences, err := storage.OverlappingEvents(s.events[id], from, to)
+		if err != nil {
+			return nil, "", err
+		}
+		events = append(events, occurrences...)
+	}
+
+	events, nextPageToken, err := storage.Paginate(events, filter)
+	if err != nil || len(events) == 0 {
+		return nil, "", err
+	}
+	return events, nextPageToken, nil
+}
+
+// EnqueueNotifications adds to the outbox a notification for every event or
+// occurrence whose notification time is in the (from, to] interval. The
+// notifications are sent to the owner and to every accepted attendee of the event.
+// A notification for the same event, user and notification time is enqueued only once.
+// New notifications are enqueued in the order of their notification time.
+func (s *Storage) EnqueueNotifications(ctx context.Context, from, to time.Time) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+
+	s.pruneOutbox(from)
+
+	var keys []outboxKey
+	for _, event := range s.events {
+		notifications, err := storage.DueNotifications(event, from, to)
+		if err != nil {
+			return 0, err
+		}
+
+		notifications = storage.WithRecipients(notifications, s.acceptedAttendees(event.ID))
+
+		for _, notification := range notifications {
+			key := outboxKey{
+				eventID:  event.ID,
+				userID:   notification.UserID,
+				notifyAt: notification.NotifyAt.UnixNano(),
+			}
+			if _, ok := s.outbox[key]; ok {
+				continue
+			}
+
+			s.outbox[key] = notification.Notification
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].notifyAt != keys[j].notifyAt {
+			return keys[i].notifyAt < keys[j].notifyAt
+		}
+		if keys[i].eventID != keys[j].eventID {
+			return keys[i].eventID < keys[j].eventID
+		}
+		return keys[i].userID < keys[j].userID
 	})
-	return events
+	s.pending = append(s.pending, keys...)
+
+	return int64(len(keys)), nil
+}
+
+// pruneOutbox drops the sent notifications due before from. They are out of
+// the window of EnqueueNotifications, so they can not be enqueued again. It
+// must be called with the outbox lock held.
+func (s *Storage) pruneOutbox(from time.Time) {
+	pending := make(map[outboxKey]struct{}, len(s.pending))
+	for _, key := range s.pending {
+		pending[key] = struct{}{}
+	}
+
+	for key := range s.outbox {
+		if _, ok := pending[key]; ok || key.notifyAt > from.UnixNano() {
+			continue
+		}
+		delete(s.outbox, key)
+	}
+}
+
+// ProcessOutbox passes up to limit unsent notifications to fn in the order they
+// were enqueued. A notification is marked as sent when fn succeeds; sent
+// notifications stay in the outbox so that they are not enqueued again while
+// they are in the window of EnqueueNotifications.
+func (s *Storage) ProcessOutbox(ctx context.Context, limit int, fn func(context.Context, models.Notification) error) (int, error) { //nolint:lll
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+
+	var processed int
+	for processed < limit && processed < len(s.pending) {
+		if err := ctx.Err(); err != nil {
+			s.pending = s.pending[processed:]
+			return processed, err
+		}
+
+		if err := fn(ctx, s.outbox[s.pending[processed]]); err != nil {
+			s.pending = s.pending[processed:]
+			return processed, err
+		}
+		processed++
+	}
+
+	s.pending = s.pending[processed:]
+	return processed, nil
+}
+
+func (s *Storage) deleteOutbox(eventID string) {
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+
+	for key := range s.outbox {
+		if key.eventID == eventID {
+			delete(s.outbox, key)
+		}
+	}
+
+	pending := s.pending[:0]
+	for _, key := range s.pending {
+		if key.eventID != eventID {
+			pending = append(pending, key)
+		}
+	}
+	s.pending = pending
+}
+
+// deletePendingOutbox drops the unsent notifications of the event. Sent ones
+// are kept, so they are not enqueued again if the event is restored.
+func (s *Storage) deletePendingOutbox(eventID string) {
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+
+	pending := s.pending[:0]
+	for _, key := range s.pending {
+		if key.eventID == eventID {
+			delete(s.outbox, key)
+			continue
+		}
+		pending = append(pending, key)
+	}
+	s.pending = pending
+}
+
+func (s *Storage) DeleteEventsBefore(ctx context.Context, date time.Time) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for _, event := range s.events {
+		ended, err := storage.EndedBefore(event, date)
+		if err != nil {
+			return deleted, err
+		}
+
+		if ended {
+			s.purgeEvent(ctx, storage.SchedulerActorID, event)
+			deleted++
+		}
+	}
+	return deleted, nil
 }
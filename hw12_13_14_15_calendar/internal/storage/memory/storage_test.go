@@ -2,9 +2,11 @@ package memorystorage
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/go-chi/chi/middleware"
 	"github.com/google/uuid"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
@@ -42,7 +44,7 @@ func TestDeleteEvent(t *testing.T) {
 		err := memoryStorage.CreateEvent(context.Background(), &newEvents[0])
 		require.NoError(t, err)
 
-		err = memoryStorage.DeleteEvent(context.Background(), newEvents[0].ID)
+		err = memoryStorage.DeleteEvent(context.Background(), 1, newEvents[0].ID, 0)
 		require.NoError(t, err)
 
 		assert.Equal(t, wantEvents, memoryStorage.events)
@@ -54,7 +56,236 @@ func TestDeleteEvent(t *testing.T) {
 	t.Run("event does not exist", func(t *testing.T) {
 		memoryStorage := New()
 
-		err := memoryStorage.DeleteEvent(context.Background(), "id")
+		err := memoryStorage.DeleteEvent(context.Background(), 1, "id", 0)
+		require.ErrorIs(t, err, storage.ErrEventNotExist)
+	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)
+
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[0])
+		require.NoError(t, err)
+
+		err = memoryStorage.DeleteEvent(context.Background(), 2, newEvents[0].ID, 0)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+		require.Contains(t, memoryStorage.events, newEvents[0].ID)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newDeletedEvent := func(t *testing.T) (*Storage, *models.Event) {
+		t.Helper()
+
+		memoryStorage := New()
+
+		event := generateEvents(day.Add(13*time.Hour), day.Add(15*time.Hour), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+
+		return memoryStorage, &event
+	}
+
+	t.Run("deleted event is moved to the trash", func(t *testing.T) {
+		memoryStorage, event := newDeletedEvent(t)
+
+		events, _, err := memoryStorage.GetEventByDay(ctx, 1, day, models.EventFilter{})
+		require.NoError(t, err)
+		require.Empty(t, events)
+
+		deleted, err := memoryStorage.GetDeletedEvents(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, event.ID, deleted[0].ID)
+		require.NotNil(t, deleted[0].DeletedAt)
+		require.Equal(t, int64(2), deleted[0].Version)
+
+		err = memoryStorage.DeleteEvent(ctx, 1, event.ID, 0)
+		require.ErrorIs(t, err, storage.ErrEventNotExist)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		memoryStorage, event := newDeletedEvent(t)
+
+		require.NoError(t, memoryStorage.RestoreEvent(ctx, 1, event.ID))
+
+		events, _, err := memoryStorage.GetEventByDay(ctx, 1, day, models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Nil(t, events[0].DeletedAt)
+		require.Equal(t, int64(3), events[0].Version)
+
+		deleted, err := memoryStorage.GetDeletedEvents(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+
+		err = memoryStorage.RestoreEvent(ctx, 1, event.ID)
+		require.ErrorIs(t, err, storage.ErrEventNotExist)
+	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		memoryStorage, event := newDeletedEvent(t)
+
+		deleted, err := memoryStorage.GetDeletedEvents(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+
+		err = memoryStorage.RestoreEvent(ctx, 2, event.ID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("restore into busy time is rejected", func(t *testing.T) {
+		memoryStorage, event := newDeletedEvent(t)
+
+		overlapping := generateEvents(day.Add(14*time.Hour), day.Add(16*time.Hour), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &overlapping))
+
+		err := memoryStorage.RestoreEvent(ctx, 1, event.ID)
+		require.ErrorIs(t, err, storage.ErrDateBusy)
+		require.Contains(t, memoryStorage.trash, event.ID)
+	})
+
+	t.Run("attendees are kept until the event is purged", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := generateEvents(day.Add(13*time.Hour), day.Add(15*time.Hour), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+		require.NoError(t, memoryStorage.InviteAttendees(ctx, 1, event.ID, []int64{2}))
+		require.NoError(t, memoryStorage.RespondToInvitation(ctx, 2, event.ID, models.StatusAccepted))
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+
+		invitations, err := memoryStorage.GetInvitations(ctx, 2, "")
+		require.NoError(t, err)
+		require.Empty(t, invitations)
+
+		err = memoryStorage.RespondToInvitation(ctx, 2, event.ID, models.StatusDeclined)
+		require.ErrorIs(t, err, storage.ErrInvitationNotExist)
+
+		require.NoError(t, memoryStorage.RestoreEvent(ctx, 1, event.ID))
+		invitations, err = memoryStorage.GetInvitations(ctx, 2, models.StatusAccepted)
+		require.NoError(t, err)
+		require.Len(t, invitations, 1)
+
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+		purged, err := memoryStorage.PurgeDeletedEvents(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), purged)
+		require.Empty(t, memoryStorage.trash)
+		require.Empty(t, memoryStorage.attendees)
+	})
+
+	t.Run("purge keeps recently deleted events", func(t *testing.T) {
+		memoryStorage, event := newDeletedEvent(t)
+
+		purged, err := memoryStorage.PurgeDeletedEvents(ctx, time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+		require.Zero(t, purged)
+		require.Contains(t, memoryStorage.trash, event.ID)
+	})
+}
+
+func TestEventHistory(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "request-1")
+	day := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	memoryStorage := New()
+
+	event := generateEvents(day.Add(13*time.Hour), day.Add(15*time.Hour), 1)[0]
+	require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+	require.NoError(t, memoryStorage.UpdateEvent(ctx, 1, &models.Event{ID: event.ID, Title: "new title"}))
+	require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+	require.NoError(t, memoryStorage.RestoreEvent(ctx, 1, event.ID))
+
+	t.Run("every change is recorded", func(t *testing.T) {
+		history, err := memoryStorage.GetEventHistory(ctx, 1, event.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 4)
+
+		actions := make([]models.HistoryAction, len(history))
+		for i := range history {
+			actions[i] = history[i].Action
+			require.Equal(t, event.ID, history[i].EventID)
+			require.Equal(t, int64(1), history[i].ActorID)
+			require.Equal(t, "request-1", history[i].RequestID)
+		}
+		require.Equal(t, []models.HistoryAction{
+			models.ActionCreate, models.ActionUpdate, models.ActionDelete, models.ActionRestore,
+		}, actions)
+
+		require.Nil(t, history[0].Before)
+		require.Equal(t, "some title", history[0].After.Title)
+		require.Equal(t, "some title", history[1].Before.Title)
+		require.Equal(t, "new title", history[1].After.Title)
+		require.Equal(t, int64(2), history[1].After.Version)
+		require.Nil(t, history[2].Before.DeletedAt)
+		require.NotNil(t, history[2].After.DeletedAt)
+		require.NotNil(t, history[3].Before.DeletedAt)
+		require.Nil(t, history[3].After.DeletedAt)
+	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		_, err := memoryStorage.GetEventHistory(ctx, 2, event.ID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("event does not exist", func(t *testing.T) {
+		_, err := memoryStorage.GetEventHistory(ctx, 1, "id")
+		require.ErrorIs(t, err, storage.ErrEventNotExist)
+	})
+
+	t.Run("history is kept for the owner of a purged event", func(t *testing.T) {
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+
+		history, err := memoryStorage.GetEventHistory(ctx, 1, event.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 5)
+
+		_, err = memoryStorage.PurgeDeletedEvents(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		history, err = memoryStorage.GetEventHistory(ctx, 1, event.ID)
+		require.NoError(t, err)
+		require.Len(t, history, 6)
+
+		purged := history[5]
+		require.Equal(t, models.ActionPurge, purged.Action)
+		require.Equal(t, storage.SchedulerActorID, purged.ActorID)
+		require.NotNil(t, purged.Before.DeletedAt)
+		require.Nil(t, purged.After)
+
+		_, err = memoryStorage.GetEventHistory(ctx, 2, event.ID)
+		require.ErrorIs(t, err, storage.ErrEventNotExist)
+	})
+}
+
+func TestGetEvent(t *testing.T) {
+	ctx := context.Background()
+	memoryStorage := New()
+
+	event := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)[0]
+	require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+	t.Run("success", func(t *testing.T) {
+		got, err := memoryStorage.GetEvent(ctx, 1, event.ID)
+		require.NoError(t, err)
+		require.Equal(t, event, *got)
+	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		_, err := memoryStorage.GetEvent(ctx, 2, event.ID)
+		require.ErrorIs(t, err, storage.ErrForbidden)
+	})
+
+	t.Run("deleted event", func(t *testing.T) {
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+
+		_, err := memoryStorage.GetEvent(ctx, 1, event.ID)
 		require.ErrorIs(t, err, storage.ErrEventNotExist)
 	})
 }
@@ -75,7 +306,7 @@ func TestUpdateEvent(t *testing.T) {
 			Title: "new title",
 		}
 
-		err = memoryStorage.UpdateEvent(context.Background(), &updatedEvent)
+		err = memoryStorage.UpdateEvent(context.Background(), 1, &updatedEvent)
 		require.NoError(t, err)
 		require.Equal(t, updatedEvent.Title, memoryStorage.events[newEvents[0].ID].Title)
 	})
@@ -83,9 +314,135 @@ func TestUpdateEvent(t *testing.T) {
 	t.Run("event does not exist", func(t *testing.T) {
 		memoryStorage := New()
 
-		err := memoryStorage.UpdateEvent(context.Background(), &models.Event{ID: "id"})
+		err := memoryStorage.UpdateEvent(context.Background(), 1, &models.Event{ID: "id"})
 		require.ErrorIs(t, err, storage.ErrEventNotExist)
 	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)
+
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[0])
+		require.NoError(t, err)
+
+		err = memoryStorage.UpdateEvent(context.Background(), 2, &models.Event{
+			ID:    newEvents[0].ID,
+			Title: "new title",
+		})
+		require.ErrorIs(t, err, storage.ErrForbidden)
+		require.Equal(t, newEvents[0].Title, memoryStorage.events[newEvents[0].ID].Title)
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)
+
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[0])
+		require.NoError(t, err)
+		require.Equal(t, int64(1), newEvents[0].Version)
+
+		first := models.Event{ID: newEvents[0].ID, Title: "first", Version: 1}
+		err = memoryStorage.UpdateEvent(context.Background(), 1, &first)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), first.Version)
+
+		second := models.Event{ID: newEvents[0].ID, Title: "second", Version: 1}
+		err = memoryStorage.UpdateEvent(context.Background(), 1, &second)
+		require.ErrorIs(t, err, storage.ErrVersionMismatch)
+		require.Equal(t, "first", memoryStorage.events[newEvents[0].ID].Title)
+
+		err = memoryStorage.DeleteEvent(context.Background(), 1, newEvents[0].ID, 1)
+		require.ErrorIs(t, err, storage.ErrVersionMismatch)
+
+		err = memoryStorage.DeleteEvent(context.Background(), 1, newEvents[0].ID, 2)
+		require.NoError(t, err)
+	})
+}
+
+func TestEventConflicts(t *testing.T) {
+	t.Run("overlapping event is rejected", func(t *testing.T) {
+		memoryStorage := New()
+
+		existing := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)[0]
+		err := memoryStorage.CreateEvent(context.Background(), &existing)
+		require.NoError(t, err)
+
+		overlapping := generateEvents(time.Date(2010, 1, 1, 14, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 16, 0, 0, 0, time.UTC), 1)[0]
+		err = memoryStorage.CreateEvent(context.Background(), &overlapping)
+		require.ErrorIs(t, err, storage.ErrDateBusy)
+
+		var conflict *storage.ConflictError
+		require.ErrorAs(t, err, &conflict)
+		require.Equal(t, existing.ID, conflict.EventID)
+		require.NotContains(t, memoryStorage.events, overlapping.ID)
+
+		adjacent := generateEvents(time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 16, 0, 0, 0, time.UTC), 1)[0]
+		err = memoryStorage.CreateEvent(context.Background(), &adjacent)
+		require.NoError(t, err)
+	})
+
+	t.Run("overlap is allowed on request", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)
+		newEvents = append(newEvents, newEvents[0])
+		newEvents[1].ID = uuid.New().String()
+		newEvents[1].AllowOverlap = true
+
+		for i := range newEvents {
+			err := memoryStorage.CreateEvent(context.Background(), &newEvents[i])
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("occurrence of recurring event conflicts", func(t *testing.T) {
+		memoryStorage := New()
+
+		rule := "FREQ=WEEKLY"
+		recurring := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)[0]
+		recurring.RecurrenceRule = &rule
+		err := memoryStorage.CreateEvent(context.Background(), &recurring)
+		require.NoError(t, err)
+
+		event := generateEvents(time.Date(2010, 1, 15, 14, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 15, 16, 0, 0, 0, time.UTC), 1)[0]
+		err = memoryStorage.CreateEvent(context.Background(), &event)
+		require.ErrorIs(t, err, storage.ErrDateBusy)
+	})
+
+	t.Run("update into busy time is rejected", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 2)
+		for i := range newEvents {
+			err := memoryStorage.CreateEvent(context.Background(), &newEvents[i])
+			require.NoError(t, err)
+		}
+
+		err := memoryStorage.UpdateEvent(context.Background(), 1, &models.Event{
+			ID:        newEvents[1].ID,
+			StartDate: newEvents[0].StartDate,
+			EndDate:   newEvents[0].EndDate,
+		})
+		require.ErrorIs(t, err, storage.ErrDateBusy)
+		require.Equal(t, newEvents[1].StartDate, memoryStorage.events[newEvents[1].ID].StartDate)
+
+		err = memoryStorage.UpdateEvent(context.Background(), 1, &models.Event{
+			ID:        newEvents[0].ID,
+			StartDate: newEvents[0].StartDate.Add(time.Hour),
+		})
+		require.NoError(t, err)
+	})
 }
 
 func TestGetEventByDay(t *testing.T) {
@@ -100,8 +457,8 @@ func TestGetEventByDay(t *testing.T) {
 	err = memoryStorage.CreateEvent(context.Background(), &newEvents[1])
 	require.NoError(t, err)
 
-	got, err := memoryStorage.GetEventByDay(context.Background(), newEvents[0].UserID,
-		time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC))
+	got, _, err := memoryStorage.GetEventByDay(context.Background(), newEvents[0].UserID,
+		time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), models.EventFilter{})
 	require.NoError(t, err)
 	assert.Equal(t, []models.Event{newEvents[0]}, got)
 }
@@ -118,8 +475,8 @@ func TestGetEventByWeek(t *testing.T) {
 	err = memoryStorage.CreateEvent(context.Background(), &newEvents[1])
 	require.NoError(t, err)
 
-	got, err := memoryStorage.GetEventByWeek(context.Background(), newEvents[0].UserID,
-		time.Date(2009, 12, 28, 0, 0, 0, 0, time.UTC))
+	got, _, err := memoryStorage.GetEventByWeek(context.Background(), newEvents[0].UserID,
+		time.Date(2009, 12, 28, 0, 0, 0, 0, time.UTC), models.EventFilter{})
 	require.NoError(t, err)
 
 	require.Equal(t, newEvents, got)
@@ -137,13 +494,551 @@ func TestGetEventByMonth(t *testing.T) {
 	err = memoryStorage.CreateEvent(context.Background(), &newEvents[1])
 	require.NoError(t, err)
 
-	got, err := memoryStorage.GetEventByMonth(context.Background(), newEvents[0].UserID,
-		time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC))
+	got, _, err := memoryStorage.GetEventByMonth(context.Background(), newEvents[0].UserID,
+		time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), models.EventFilter{})
 	require.NoError(t, err)
 
 	require.Equal(t, newEvents, got)
 }
 
+func TestGetEventsPagination(t *testing.T) {
+	memoryStorage := New()
+
+	newEvents := generateEvents(time.Date(2010, 1, 4, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 4, 15, 0, 0, 0, time.UTC), 5)
+	newEvents[0].Title = "Standup"
+	newEvents[3].Title = "standup"
+
+	notificationTime := time.Hour
+	newEvents[1].NotificationTime = &notificationTime
+	newEvents[3].NotificationTime = &notificationTime
+
+	for i := range newEvents {
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[i])
+		require.NoError(t, err)
+	}
+
+	week := time.Date(2010, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	t.Run("pages", func(t *testing.T) {
+		var got []models.Event
+		filter := models.EventFilter{PageSize: 2}
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 3)
+
+			page, nextPageToken, err := memoryStorage.GetEventByWeek(context.Background(), 1, week, filter)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page), 2)
+
+			got = append(got, page...)
+			if nextPageToken == "" {
+				break
+			}
+			filter.PageToken = nextPageToken
+		}
+		require.Equal(t, newEvents, got)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		got, nextPageToken, err := memoryStorage.GetEventByWeek(context.Background(), 1, week,
+			models.EventFilter{Title: "STAND", WithNotification: true})
+		require.NoError(t, err)
+		require.Empty(t, nextPageToken)
+		require.Equal(t, []models.Event{newEvents[3]}, got)
+	})
+
+	t.Run("invalid page token", func(t *testing.T) {
+		_, _, err := memoryStorage.GetEventByWeek(context.Background(), 1, week,
+			models.EventFilter{PageToken: "invalid"})
+		require.ErrorIs(t, err, storage.ErrInvalidPageToken)
+	})
+}
+
+func TestGetEventsInRange(t *testing.T) {
+	memoryStorage := New()
+
+	multiDay := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 3, 15, 0, 0, 0, time.UTC), 1)[0]
+	inside := generateEvents(time.Date(2010, 1, 2, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 2, 15, 0, 0, 0, time.UTC), 1)[0]
+	outside := generateEvents(time.Date(2010, 1, 5, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 5, 15, 0, 0, 0, time.UTC), 1)[0]
+	otherUser := generateEvents(time.Date(2010, 1, 2, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 2, 15, 0, 0, 0, time.UTC), 1)[0]
+	otherUser.UserID = multiDay.UserID + 1
+	inside.AllowOverlap = true
+
+	for _, event := range []*models.Event{&multiDay, &inside, &outside, &otherUser} {
+		err := memoryStorage.CreateEvent(context.Background(), event)
+		require.NoError(t, err)
+	}
+
+	got, err := memoryStorage.GetEventsInRange(context.Background(), multiDay.UserID,
+		time.Date(2010, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	require.Equal(t, []models.Event{multiDay, inside}, got)
+}
+
+func TestEnqueueNotifications(t *testing.T) {
+	memoryStorage := New()
+
+	newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 3)
+
+	notificationTime := time.Hour
+	newEvents[0].NotificationTime = &notificationTime
+	newEvents[1].NotificationTime = &notificationTime
+
+	for i := range newEvents {
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[i])
+		require.NoError(t, err)
+	}
+
+	from := time.Date(2010, 1, 1, 11, 0, 0, 0, time.UTC)
+	to := time.Date(2010, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	enqueued, err := memoryStorage.EnqueueNotifications(context.Background(), from, to)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), enqueued)
+
+	enqueued, err = memoryStorage.EnqueueNotifications(context.Background(), from, to)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), enqueued)
+
+	want := models.Notification{
+		EventID: newEvents[0].ID,
+		Title:   newEvents[0].Title,
+		Date:    newEvents[0].StartDate,
+		UserID:  newEvents[0].UserID,
+	}
+	require.Equal(t, []models.Notification{want}, drainOutbox(t, memoryStorage))
+
+	t.Run("sent notifications out of the window are pruned", func(t *testing.T) {
+		require.Len(t, memoryStorage.outbox, 1)
+
+		_, err := memoryStorage.EnqueueNotifications(context.Background(), to, to.Add(time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, memoryStorage.outbox)
+	})
+}
+
+func TestProcessOutbox(t *testing.T) {
+	t.Run("failed notification is kept", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 2)
+
+		notificationTime := time.Hour
+		for i := range newEvents {
+			newEvents[i].NotificationTime = &notificationTime
+			err := memoryStorage.CreateEvent(context.Background(), &newEvents[i])
+			require.NoError(t, err)
+		}
+
+		_, err := memoryStorage.EnqueueNotifications(context.Background(),
+			time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 3, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+
+		errPublish := errors.New("unexpected error")
+		processed, err := memoryStorage.ProcessOutbox(context.Background(), 10,
+			func(_ context.Context, n models.Notification) error {
+				if n.EventID == newEvents[1].ID {
+					return errPublish
+				}
+				return nil
+			})
+		require.ErrorIs(t, err, errPublish)
+		require.Equal(t, 1, processed)
+
+		got := drainOutbox(t, memoryStorage)
+		require.Len(t, got, 1)
+		require.Equal(t, newEvents[1].ID, got[0].EventID)
+	})
+
+	t.Run("deleted event is removed from outbox", func(t *testing.T) {
+		memoryStorage := New()
+
+		newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 1)
+
+		notificationTime := time.Hour
+		newEvents[0].NotificationTime = &notificationTime
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[0])
+		require.NoError(t, err)
+
+		_, err = memoryStorage.EnqueueNotifications(context.Background(),
+			time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 2, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+
+		err = memoryStorage.DeleteEvent(context.Background(), 1, newEvents[0].ID, 0)
+		require.NoError(t, err)
+		require.Empty(t, drainOutbox(t, memoryStorage))
+	})
+}
+
+func drainOutbox(t *testing.T, memoryStorage *Storage) []models.Notification {
+	t.Helper()
+
+	var notifications []models.Notification
+	_, err := memoryStorage.ProcessOutbox(context.Background(), 100,
+		func(_ context.Context, n models.Notification) error {
+			notifications = append(notifications, n)
+			return nil
+		})
+	require.NoError(t, err)
+	return notifications
+}
+
+func TestDeleteEventsBefore(t *testing.T) {
+	memoryStorage := New()
+
+	newEvents := generateEvents(time.Date(2010, 1, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2010, 1, 1, 15, 0, 0, 0, time.UTC), 2)
+
+	for i := range newEvents {
+		err := memoryStorage.CreateEvent(context.Background(), &newEvents[i])
+		require.NoError(t, err)
+	}
+
+	deleted, err := memoryStorage.DeleteEventsBefore(context.Background(),
+		time.Date(2010, 1, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	history, err := memoryStorage.GetEventHistory(context.Background(), newEvents[0].UserID, newEvents[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, models.ActionPurge, history[len(history)-1].Action)
+
+	wantDays, wantWeeks, wantMonths := getDates(newEvents[1:])
+	assert.Equal(t, getEvents(newEvents[1:]), memoryStorage.events)
+	assert.Equal(t, wantDays, memoryStorage.days)
+	assert.Equal(t, wantWeeks, memoryStorage.weeks)
+	assert.Equal(t, wantMonths, memoryStorage.months)
+}
+
+func TestRecurringEvents(t *testing.T) {
+	newRecurringEvent := func(rule string) models.Event {
+		event := generateEvents(time.Date(2010, 1, 4, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 4, 15, 0, 0, 0, time.UTC), 1)[0]
+		event.RecurrenceRule = &rule
+		event.ExceptionDates = models.Dates{time.Date(2010, 1, 6, 13, 0, 0, 0, time.UTC)}
+		return event
+	}
+
+	t.Run("occurrences are expanded", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := newRecurringEvent("FREQ=DAILY;INTERVAL=2")
+		err := memoryStorage.CreateEvent(context.Background(), &event)
+		require.NoError(t, err)
+
+		got, _, err := memoryStorage.GetEventByWeek(context.Background(), event.UserID,
+			time.Date(2010, 1, 4, 0, 0, 0, 0, time.UTC), models.EventFilter{})
+		require.NoError(t, err)
+
+		wantStarts := []time.Time{
+			time.Date(2010, 1, 4, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 8, 13, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 10, 13, 0, 0, 0, time.UTC),
+		}
+		require.Len(t, got, len(wantStarts))
+		for i := range got {
+			assert.Equal(t, event.ID, got[i].ID)
+			assert.Equal(t, wantStarts[i], got[i].StartDate)
+			assert.Equal(t, wantStarts[i].Add(2*time.Hour), got[i].EndDate)
+		}
+
+		got, _, err = memoryStorage.GetEventByDay(context.Background(), event.UserID,
+			time.Date(2010, 1, 6, 0, 0, 0, 0, time.UTC), models.EventFilter{})
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("notification is enqueued for every occurrence", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := newRecurringEvent("FREQ=DAILY")
+		notificationTime := time.Hour
+		event.NotificationTime = &notificationTime
+		err := memoryStorage.CreateEvent(context.Background(), &event)
+		require.NoError(t, err)
+
+		enqueued, err := memoryStorage.EnqueueNotifications(context.Background(),
+			time.Date(2010, 1, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 8, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, int64(3), enqueued)
+
+		got := drainOutbox(t, memoryStorage)
+		require.Len(t, got, 3)
+		assert.Equal(t, time.Date(2010, 1, 7, 13, 0, 0, 0, time.UTC), got[2].Date)
+	})
+
+	t.Run("event is deleted after the last occurrence", func(t *testing.T) {
+		memoryStorage := New()
+
+		finite := newRecurringEvent("FREQ=WEEKLY;COUNT=2")
+		infinite := newRecurringEvent("FREQ=WEEKLY")
+		infinite.AllowOverlap = true
+		for _, event := range []*models.Event{&finite, &infinite} {
+			err := memoryStorage.CreateEvent(context.Background(), event)
+			require.NoError(t, err)
+		}
+
+		deleted, err := memoryStorage.DeleteEventsBefore(context.Background(),
+			time.Date(2010, 1, 11, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, int64(0), deleted)
+
+		deleted, err = memoryStorage.DeleteEventsBefore(context.Background(),
+			time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+		require.Contains(t, memoryStorage.events, infinite.ID)
+		require.NotContains(t, memoryStorage.recurring, finite.ID)
+	})
+}
+
+func TestMultiDayEvents(t *testing.T) {
+	ctx := context.Background()
+
+	dayIDs := func(t *testing.T, memoryStorage *Storage, day time.Time) []string {
+		t.Helper()
+
+		got, _, err := memoryStorage.GetEventByDay(ctx, 1, day, models.EventFilter{})
+		require.NoError(t, err)
+
+		ids := make([]string, len(got))
+		for i := range got {
+			ids[i] = got[i].ID
+		}
+		return ids
+	}
+
+	t.Run("event is listed in every day, week and month it touches", func(t *testing.T) {
+		memoryStorage := New()
+
+		// From Sunday to Tuesday across the month and the week boundaries.
+		event := generateEvents(time.Date(2010, 1, 31, 10, 0, 0, 0, time.UTC),
+			time.Date(2010, 2, 2, 12, 0, 0, 0, time.UTC), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		for _, day := range []int{31, 32, 33} {
+			date := time.Date(2010, 1, day, 0, 0, 0, 0, time.UTC)
+			assert.Equal(t, []string{event.ID}, dayIDs(t, memoryStorage, date), date)
+		}
+		assert.Empty(t, dayIDs(t, memoryStorage, time.Date(2010, 1, 30, 0, 0, 0, 0, time.UTC)))
+		assert.Empty(t, dayIDs(t, memoryStorage, time.Date(2010, 2, 3, 0, 0, 0, 0, time.UTC)))
+
+		for _, week := range []time.Time{
+			time.Date(2010, 1, 25, 0, 0, 0, 0, time.UTC),
+			time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC),
+		} {
+			got, _, err := memoryStorage.GetEventByWeek(ctx, 1, week, models.EventFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, []models.Event{event}, got, week)
+		}
+
+		for _, month := range []time.Time{
+			time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2010, 2, 1, 0, 0, 0, 0, time.UTC),
+		} {
+			got, _, err := memoryStorage.GetEventByMonth(ctx, 1, month, models.EventFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, []models.Event{event}, got, month)
+		}
+	})
+
+	t.Run("event that ends at midnight does not touch the next day", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := generateEvents(time.Date(2010, 1, 4, 22, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 5, 0, 0, 0, 0, time.UTC), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		assert.Equal(t, event.Day, event.LastDay)
+		assert.Empty(t, dayIDs(t, memoryStorage, time.Date(2010, 1, 5, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("update and delete move the event out of its days", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := generateEvents(time.Date(2010, 1, 4, 10, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 6, 12, 0, 0, 0, time.UTC), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		err := memoryStorage.UpdateEvent(ctx, 1, &models.Event{
+			ID:      event.ID,
+			EndDate: time.Date(2010, 1, 4, 12, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{event.ID}, dayIDs(t, memoryStorage, time.Date(2010, 1, 4, 0, 0, 0, 0, time.UTC)))
+		assert.Empty(t, dayIDs(t, memoryStorage, time.Date(2010, 1, 5, 0, 0, 0, 0, time.UTC)))
+
+		require.NoError(t, memoryStorage.DeleteEvent(ctx, 1, event.ID, 0))
+		assert.Empty(t, memoryStorage.days)
+		assert.Empty(t, memoryStorage.weeks)
+		assert.Empty(t, memoryStorage.months)
+	})
+
+	t.Run("too long event is rejected", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := generateEvents(time.Date(2010, 1, 4, 10, 0, 0, 0, time.UTC),
+			time.Date(2110, 1, 4, 10, 0, 0, 0, time.UTC), 1)[0]
+		require.ErrorIs(t, memoryStorage.CreateEvent(ctx, &event), models.ErrEventTooLong)
+
+		event.EndDate = time.Date(2010, 1, 4, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		err := memoryStorage.UpdateEvent(ctx, 1, &models.Event{
+			ID:      event.ID,
+			EndDate: time.Date(2110, 1, 4, 12, 0, 0, 0, time.UTC),
+		})
+		require.ErrorIs(t, err, models.ErrEventTooLong)
+		assert.Len(t, memoryStorage.days, 1)
+	})
+
+	t.Run("occurrences are listed in every day they touch", func(t *testing.T) {
+		memoryStorage := New()
+
+		rule := "FREQ=WEEKLY"
+		event := generateEvents(time.Date(2010, 1, 8, 20, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 10, 10, 0, 0, 0, time.UTC), 1)[0]
+		event.RecurrenceRule = &rule
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		got, _, err := memoryStorage.GetEventByDay(ctx, 1, time.Date(2010, 1, 16, 0, 0, 0, 0, time.UTC),
+			models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, time.Date(2010, 1, 15, 20, 0, 0, 0, time.UTC), got[0].StartDate)
+	})
+
+	t.Run("all-day event", func(t *testing.T) {
+		memoryStorage := New()
+
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		// The dates are aligned to midnights: the end is moved to the next one.
+		allDay := true
+		event := generateEvents(time.Date(2010, 1, 4, 15, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 5, 18, 0, 0, 0, berlin).UTC(), 1)[0]
+		event.AllDay = &allDay
+		event.Timezone = "Europe/Berlin"
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+		assert.Equal(t, time.Date(2010, 1, 4, 0, 0, 0, 0, berlin).UTC(), event.StartDate)
+		assert.Equal(t, time.Date(2010, 1, 6, 0, 0, 0, 0, berlin).UTC(), event.EndDate)
+
+		for _, day := range []int{4, 5} {
+			date := time.Date(2010, 1, day, 0, 0, 0, 0, berlin)
+			assert.Equal(t, []string{event.ID}, dayIDs(t, memoryStorage, date), date)
+		}
+		assert.Empty(t, dayIDs(t, memoryStorage, time.Date(2010, 1, 6, 0, 0, 0, 0, berlin)))
+
+		// Clocks go forward on 2010-03-28, the occurrence still lasts one day.
+		rule := "FREQ=WEEKLY"
+		weekly := generateEvents(time.Date(2010, 1, 3, 0, 0, 0, 0, berlin).UTC(),
+			time.Date(2010, 1, 4, 0, 0, 0, 0, berlin).UTC(), 1)[0]
+		weekly.AllDay = &allDay
+		weekly.Timezone = "Europe/Berlin"
+		weekly.RecurrenceRule = &rule
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &weekly))
+		assert.Equal(t, []string{weekly.ID}, dayIDs(t, memoryStorage, time.Date(2010, 3, 28, 0, 0, 0, 0, berlin)))
+		assert.Empty(t, dayIDs(t, memoryStorage, time.Date(2010, 3, 29, 0, 0, 0, 0, berlin)))
+
+		// All-day events do not make the user busy.
+		timed := generateEvents(time.Date(2010, 1, 5, 10, 0, 0, 0, time.UTC),
+			time.Date(2010, 1, 5, 11, 0, 0, 0, time.UTC), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &timed))
+	})
+}
+
+func TestTimezones(t *testing.T) {
+	ctx := context.Background()
+
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("event is listed under its local day", func(t *testing.T) {
+		memoryStorage := New()
+
+		// 2023-08-16 08:00 in Sydney is 2023-08-15 22:00 UTC.
+		event := generateEvents(time.Date(2023, 8, 15, 22, 0, 0, 0, time.UTC),
+			time.Date(2023, 8, 15, 23, 0, 0, 0, time.UTC), 1)[0]
+		event.Timezone = "Australia/Sydney"
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+		require.Equal(t, time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC), event.Day)
+
+		got, _, err := memoryStorage.GetEventByDay(ctx, 1, time.Date(2023, 8, 16, 0, 0, 0, 0, sydney),
+			models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, event.ID, got[0].ID)
+
+		got, _, err = memoryStorage.GetEventByDay(ctx, 1, time.Date(2023, 8, 15, 0, 0, 0, 0, sydney),
+			models.EventFilter{})
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		got, _, err = memoryStorage.GetEventByDay(ctx, 1, time.Date(2023, 8, 15, 0, 0, 0, 0, time.UTC),
+			models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+	})
+
+	t.Run("recurring event keeps local time across DST", func(t *testing.T) {
+		memoryStorage := New()
+
+		rule := "FREQ=DAILY"
+		start := time.Date(2023, 3, 24, 9, 0, 0, 0, berlin)
+		event := generateEvents(start.UTC(), start.Add(time.Hour).UTC(), 1)[0]
+		event.Timezone = "Europe/Berlin"
+		event.RecurrenceRule = &rule
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		got, _, err := memoryStorage.GetEventByWeek(ctx, 1, time.Date(2023, 3, 20, 0, 0, 0, 0, berlin),
+			models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		for i, day := range []int{24, 25, 26} {
+			assert.Equal(t, time.Date(2023, 3, day, 9, 0, 0, 0, berlin).UTC(), got[i].StartDate)
+		}
+		// Clocks go forward on 2023-03-26, so the occurrence moves an hour in UTC.
+		assert.Equal(t, 8, got[1].StartDate.Hour())
+		assert.Equal(t, 7, got[2].StartDate.Hour())
+	})
+
+	t.Run("changing the time zone moves the event", func(t *testing.T) {
+		memoryStorage := New()
+
+		event := generateEvents(time.Date(2023, 8, 15, 22, 0, 0, 0, time.UTC),
+			time.Date(2023, 8, 15, 23, 0, 0, 0, time.UTC), 1)[0]
+		require.NoError(t, memoryStorage.CreateEvent(ctx, &event))
+
+		err := memoryStorage.UpdateEvent(ctx, 1, &models.Event{ID: event.ID, Timezone: "Australia/Sydney"})
+		require.NoError(t, err)
+
+		updated := memoryStorage.events[event.ID]
+		require.Equal(t, "Australia/Sydney", updated.Timezone)
+		require.Equal(t, time.Date(2023, 8, 16, 0, 0, 0, 0, time.UTC), updated.Day)
+		require.Equal(t, event.StartDate, updated.StartDate)
+
+		got, _, err := memoryStorage.GetEventByDay(ctx, 1, time.Date(2023, 8, 16, 0, 0, 0, 0, sydney),
+			models.EventFilter{})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+	})
+}
+
 func getDates(events []models.Event) (dates, dates, dates) {
 	days := make(dates)
 	weeks := make(dates)
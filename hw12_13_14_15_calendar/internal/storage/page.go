@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageKey is the position of an event in lists ordered by start date and ID.
+type PageKey struct {
+	StartDate time.Time
+	ID        string
+}
+
+func (k PageKey) Before(other PageKey) bool {
+	if !k.StartDate.Equal(other.StartDate) {
+		return k.StartDate.Before(other.StartDate)
+	}
+	return k.ID < other.ID
+}
+
+func keyOf(event *models.Event) PageKey {
+	return PageKey{StartDate: event.StartDate, ID: event.ID}
+}
+
+func EncodePageToken(key PageKey) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key.StartDate.UTC().Format(time.RFC3339Nano) + "|" + key.ID))
+}
+
+// DecodePageToken returns the key of the last event of the previous page.
+// An empty token returns nil.
+func DecodePageToken(token string) (*PageKey, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	start, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidPageToken
+	}
+
+	startDate, err := time.Parse(time.RFC3339Nano, start)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &PageKey{StartDate: startDate, ID: id}, nil
+}
+
+// MatchesFilter reports whether the event passes the filters of the filter.
+func MatchesFilter(event *models.Event, filter models.EventFilter) bool {
+	if filter.WithNotification && event.NotificationTime == nil {
+		return false
+	}
+	if filter.Title != "" && !strings.Contains(strings.ToLower(event.Title), strings.ToLower(filter.Title)) {
+		return false
+	}
+	return true
+}
+
+// Paginate filters the events, orders them by start date and ID and returns
+// the page that follows the page token together with the token of the next
+// page. The token is empty on the last page. The backing array of the events
+// is reused for the page.
+func Paginate(events []models.Event, filter models.EventFilter) ([]models.Event, string, error) {
+	after, err := DecodePageToken(filter.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := events[:0]
+	for i := range events {
+		if !MatchesFilter(&events[i], filter) {
+			continue
+		}
+		if after != nil && !after.Before(keyOf(&events[i])) {
+			continue
+		}
+		page = append(page, events[i])
+	}
+
+	SortByStartDate(page)
+
+	if filter.PageSize <= 0 || len(page) <= filter.PageSize {
+		return page, "", nil
+	}
+
+	page = page[:filter.PageSize]
+	return page, EncodePageToken(keyOf(&page[len(page)-1])), nil
+}
+
+// SortByStartDate orders the events by start date and ID.
+func SortByStartDate(events []models.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return keyOf(&events[i]).Before(keyOf(&events[j]))
+	})
+}
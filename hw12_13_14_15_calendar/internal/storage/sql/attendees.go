@@ -0,0 +1,93 @@
+package sqlstorage
+
+import (
+	"context"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+// InviteAttendees invites the users to the event on behalf of the user, who
+// must be able to change the event. Users that are already invited keep their
+// response; the owner of the event is not invited.
+func (s *Storage) InviteAttendees(ctx context.Context, userID int64, eventID string, attendeeIDs []int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	event, err := lockWritableEvent(ctx, tx, userID, eventID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO event_attendees(event_id, user_id, status)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (event_id, user_id) DO NOTHING`
+
+	for _, attendeeID := range attendeeIDs {
+		if attendeeID == event.UserID {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, query, eventID, attendeeID, models.StatusNeedsAction); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RespondToInvitation saves the response of the user to the invitation.
+func (s *Storage) RespondToInvitation(ctx context.Context, userID int64, eventID string, status models.AttendeeStatus) error { //nolint:lll
+	query := `
+	UPDATE event_attendees
+	SET status = $3
+	WHERE event_id = $1 AND user_id = $2
+		AND event_id IN (SELECT id FROM events WHERE id = $1 AND ` + notDeleted + `)`
+
+	res, err := s.db.ExecContext(ctx, query, eventID, userID, status)
+	if err != nil {
+		return err
+	}
+
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return storage.ErrInvitationNotExist
+	}
+	return nil
+}
+
+// GetInvitations returns the events the user is invited to ordered by start
+// date. If status is not empty, only invitations with that response are
+// returned. Recurring events are not expanded.
+func (s *Storage) GetInvitations(ctx context.Context, userID int64, status models.AttendeeStatus) ([]models.Invitation, error) { //nolint:lll
+	query := `
+	SELECT ` + eventColumns + `, a.status
+	FROM events
+	JOIN (
+		SELECT event_id, status
+		FROM event_attendees
+		WHERE user_id = $1 AND ($2 = '' OR status = $2)
+	) AS a ON a.event_id = events.id
+	WHERE ` + notDeleted + `
+	ORDER BY start_date, id`
+
+	var invitations []models.Invitation
+	return invitations, s.db.SelectContext(ctx, &invitations, query, userID, string(status))
+}
+
+// acceptedAttendees returns the users that accepted the event ordered by ID.
+func acceptedAttendees(ctx context.Context, db selector, eventID string) ([]int64, error) {
+	query := `
+	SELECT user_id
+	FROM event_attendees
+	WHERE event_id = $1 AND status = 'accepted'
+	ORDER BY user_id`
+
+	var userIDs []int64
+	return userIDs, db.SelectContext(ctx, &userIDs, query, eventID)
+}
@@ -0,0 +1,201 @@
+package sqlstorage
+
+import (
+	"context"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+const calendarColumns = `c.id, c.owner_id, c.name, c.timezone`
+
+type selector interface {
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// CreateCalendar saves the calendar and makes its owner the owner in the ACL.
+func (s *Storage) CreateCalendar(ctx context.Context, calendar *models.Calendar) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `
+	INSERT INTO calendars(id, owner_id, name, timezone)
+	VALUES (:id, :owner_id, :name, :timezone)`
+
+	if _, err := tx.NamedExecContext(ctx, query, calendar); err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO calendar_acl(calendar_id, user_id, role)
+	VALUES ($1, $2, $3)`
+
+	if _, err := tx.ExecContext(ctx, query, calendar.ID, calendar.OwnerID, models.RoleOwner); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetCalendar returns the calendar if the user can read it.
+func (s *Storage) GetCalendar(ctx context.Context, userID int64, calendarID string) (*models.Calendar, error) {
+	calendar, err := userCalendar(ctx, s.db, userID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.CheckRole(calendar.Role, models.RoleReader); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// GetCalendars returns the calendars the user has access to, ordered by name.
+func (s *Storage) GetCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	query := `
+	SELECT ` + calendarColumns + `, a.role
+	FROM calendars c
+	JOIN calendar_acl a ON a.calendar_id = c.id
+	WHERE a.user_id = $1
+	ORDER BY c.name, c.id`
+
+	var calendars []models.Calendar
+	return calendars, s.db.SelectContext(ctx, &calendars, query, userID)
+}
+
+// UpdateCalendar changes the non-empty fields of the calendar. Only owners may
+// change a calendar.
+func (s *Storage) UpdateCalendar(ctx context.Context, userID int64, calendar *models.Calendar) error {
+	stored, err := userCalendar(ctx, s.db, userID, calendar.ID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckRole(stored.Role, models.RoleOwner); err != nil {
+		return err
+	}
+
+	qb := NewUpdateQueryBuilder("calendars")
+	qb.SetIf(calendar.Name != "", "name = :name")
+	qb.SetIf(calendar.Timezone != "", "timezone = :timezone")
+	qb.Where("id = :id")
+
+	query := qb.Build()
+	if query == "" {
+		return nil
+	}
+
+	_, err = s.db.NamedExecContext(ctx, query, calendar)
+	return err
+}
+
+// DeleteCalendar deletes the calendar together with its events and ACL. Only
+// owners may delete a calendar. The purges of the events are recorded in the
+// history and published as deletes.
+func (s *Storage) DeleteCalendar(ctx context.Context, userID int64, calendarID string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	calendar, err := userCalendar(ctx, tx, userID, calendarID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckRole(calendar.Role, models.RoleOwner); err != nil {
+		return err
+	}
+
+	_, changes, err := s.purgeEvents(ctx, tx, userID, "calendar_id = $1", calendarID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	DELETE FROM calendars
+	WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, query, calendarID); err != nil {
+		return err
+	}
+	return s.commitChanges(tx, changes)
+}
+
+// GetACL returns the ACL of the calendar ordered by user ID. Only owners may
+// see the ACL.
+func (s *Storage) GetACL(ctx context.Context, userID int64, calendarID string) ([]models.ACLEntry, error) {
+	calendar, err := userCalendar(ctx, s.db, userID, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.CheckRole(calendar.Role, models.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	query := `
+	SELECT calendar_id, user_id, role
+	FROM calendar_acl
+	WHERE calendar_id = $1
+	ORDER BY user_id`
+
+	var entries []models.ACLEntry
+	return entries, s.db.SelectContext(ctx, &entries, query, calendarID)
+}
+
+// SetACLEntry grants the member the role on the calendar, replacing the
+// previous one.
+func (s *Storage) SetACLEntry(ctx context.Context, userID int64, entry models.ACLEntry) error {
+	calendar, err := userCalendar(ctx, s.db, userID, entry.CalendarID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckACLChange(calendar, entry.UserID); err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO calendar_acl(calendar_id, user_id, role)
+	VALUES (:calendar_id, :user_id, :role)
+	ON CONFLICT (calendar_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+
+	_, err = s.db.NamedExecContext(ctx, query, entry)
+	return err
+}
+
+// DeleteACLEntry revokes the access of the member to the calendar.
+func (s *Storage) DeleteACLEntry(ctx context.Context, userID int64, calendarID string, memberID int64) error {
+	calendar, err := userCalendar(ctx, s.db, userID, calendarID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckACLChange(calendar, memberID); err != nil {
+		return err
+	}
+
+	query := `
+	DELETE FROM calendar_acl
+	WHERE calendar_id = $1 AND user_id = $2`
+
+	_, err = s.db.ExecContext(ctx, query, calendarID, memberID)
+	return err
+}
+
+// userCalendar returns the calendar with the role of the user. The role is
+// empty if the user has no access to the calendar.
+func userCalendar(ctx context.Context, db selector, userID int64, calendarID string) (*models.Calendar, error) {
+	query := `
+	SELECT ` + calendarColumns + `, COALESCE(a.role, '') AS role
+	FROM calendars c
+	LEFT JOIN calendar_acl a ON a.calendar_id = c.id AND a.user_id = $2
+	WHERE c.id = $1`
+
+	var calendars []models.Calendar
+	if err := db.SelectContext(ctx, &calendars, query, calendarID, userID); err != nil {
+		return nil, err
+	}
+	if len(calendars) == 0 {
+		return nil, storage.ErrCalendarNotExist
+	}
+	return &calendars[0], nil
+}
@@ -0,0 +1,108 @@
+package sqlstorage
+
+import (
+	"context"
+	"sort"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+// SetChangePublisher publishes the changes of events to the publisher. It must
+// be called before the storage is used.
+func (s *Storage) SetChangePublisher(publisher storage.ChangePublisher) {
+	s.publisher = publisher
+}
+
+// commit commits the transaction and publishes the changes of the entries to
+// the users who could read the events before or after them. The users are
+// selected in the transaction, so the changes are published only if they are
+// saved.
+func (s *Storage) commit(ctx context.Context, tx *sqlx.Tx, entries ...models.HistoryEntry) error {
+	changes, err := s.eventChanges(ctx, tx, entries...)
+	if err != nil {
+		return err
+	}
+	return s.commitChanges(tx, changes)
+}
+
+// eventChanges returns the changes of the entries for the users who could read
+// the events before or after them. There are no changes without a publisher.
+func (s *Storage) eventChanges(ctx context.Context, tx *sqlx.Tx, entries ...models.HistoryEntry) ([]models.EventChange, error) { //nolint:lll
+	if s.publisher == nil {
+		return nil, nil
+	}
+
+	changes := make([]models.EventChange, 0, len(entries))
+	for _, entry := range entries {
+		userIDs, err := readers(ctx, tx, entry)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, storage.NewEventChange(entry, userIDs))
+	}
+	return changes, nil
+}
+
+// commitChanges commits the transaction and then publishes the changes.
+func (s *Storage) commitChanges(tx *sqlx.Tx, changes []models.EventChange) error {
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		s.publisher.Publish(change)
+	}
+	return nil
+}
+
+// readers returns the owner of the event, the members of its calendar and the
+// users that accepted it, ordered by ID.
+func readers(ctx context.Context, db selector, entry models.HistoryEntry) ([]int64, error) {
+	users := make(map[int64]struct{})
+	add := func(userIDs []int64) {
+		for _, userID := range userIDs {
+			users[userID] = struct{}{}
+		}
+	}
+
+	calendars := make(map[string]struct{})
+	for _, event := range []*models.Event{entry.Before, entry.After} {
+		if event == nil {
+			continue
+		}
+		users[event.UserID] = struct{}{}
+		if event.CalendarID != nil {
+			calendars[*event.CalendarID] = struct{}{}
+		}
+	}
+
+	for calendarID := range calendars {
+		query := `
+		SELECT user_id
+		FROM calendar_acl
+		WHERE calendar_id = $1`
+
+		var members []int64
+		if err := db.SelectContext(ctx, &members, query, calendarID); err != nil {
+			return nil, err
+		}
+		add(members)
+	}
+
+	attendees, err := acceptedAttendees(ctx, db, entry.EventID)
+	if err != nil {
+		return nil, err
+	}
+	add(attendees)
+
+	userIDs := make([]int64, 0, len(users))
+	for userID := range users {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		return userIDs[i] < userIDs[j]
+	})
+	return userIDs, nil
+}
@@ -0,0 +1,158 @@
+package sqlstorage
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+)
+
+// historyRecord is a row of event_history. The snapshots are stored as JSON.
+type historyRecord struct {
+	models.HistoryEntry
+	BeforeJSON *string `db:"before"`
+	AfterJSON  *string `db:"after"`
+}
+
+// saveHistory appends the entry to the history of its event.
+func saveHistory(ctx context.Context, db sqlx.ExecerContext, entry models.HistoryEntry) error {
+	before, err := marshalSnapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalSnapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO event_history(event_id, action, actor_id, request_id, before, after, changed_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = db.ExecContext(ctx, query, entry.EventID, entry.Action, entry.ActorID, entry.RequestID,
+		before, after, entry.ChangedAt)
+	return err
+}
+
+// purgeEvents permanently deletes the events matching the condition and
+// records the purges in the history on behalf of the actor. The changes are
+// built before the deletes, while the calendar members and the attendees of
+// the events still exist.
+func (s *Storage) purgeEvents(ctx context.Context, tx *sqlx.Tx, actorID int64, where string, args ...interface{}) ([]models.HistoryEntry, []models.EventChange, error) { //nolint:lll
+	query := `
+	SELECT ` + eventColumns + `
+	FROM events
+	WHERE ` + where + `
+	FOR UPDATE`
+
+	var events []models.Event
+	if err := tx.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]models.HistoryEntry, 0, len(events))
+	for i := range events {
+		entries = append(entries, storage.NewHistoryEntry(ctx, models.ActionPurge, actorID, &events[i], nil))
+	}
+
+	changes, err := s.eventChanges(ctx, tx, entries...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query = `
+	DELETE FROM events
+	WHERE id = $1`
+
+	for _, entry := range entries {
+		if _, err := tx.ExecContext(ctx, query, entry.EventID); err != nil {
+			return nil, nil, err
+		}
+		if err := saveHistory(ctx, tx, entry); err != nil {
+			return nil, nil, err
+		}
+	}
+	return entries, changes, nil
+}
+
+func marshalSnapshot(event *models.Event) (*string, error) {
+	if event == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+func unmarshalSnapshot(data *string) (*models.Event, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var event models.Event
+	if err := json.Unmarshal([]byte(*data), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetEventHistory returns the changes of the event from the oldest one. The
+// history of an event is available to the users who can read it, or restore
+// it while it is in the trash. The history of a purged event is kept for its
+// owner.
+func (s *Storage) GetEventHistory(ctx context.Context, userID int64, eventID string) ([]models.HistoryEntry, error) {
+	query := `
+	SELECT ` + readableByUser + ` OR ` + restorableByUser + ` AS allowed
+	FROM events
+	WHERE id = $2`
+
+	var allowed []bool
+	if err := s.db.SelectContext(ctx, &allowed, query, userID, eventID); err != nil {
+		return nil, err
+	}
+	if len(allowed) != 0 && !allowed[0] {
+		return nil, storage.ErrForbidden
+	}
+
+	entries, err := eventHistory(ctx, s.db, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowed) == 0 && !storage.CanReadPurgedHistory(entries, userID) {
+		return nil, storage.ErrEventNotExist
+	}
+	return entries, nil
+}
+
+func eventHistory(ctx context.Context, db selector, eventID string) ([]models.HistoryEntry, error) {
+	query := `
+	SELECT id, event_id, action, actor_id, request_id, before, after, changed_at
+	FROM event_history
+	WHERE event_id = $1
+	ORDER BY id`
+
+	var records []historyRecord
+	if err := db.SelectContext(ctx, &records, query, eventID); err != nil {
+		return nil, err
+	}
+
+	entries := make([]models.HistoryEntry, len(records))
+	for i := range records {
+		entries[i] = records[i].HistoryEntry
+
+		var err error
+		if entries[i].Before, err = unmarshalSnapshot(records[i].BeforeJSON); err != nil {
+			return nil, err
+		}
+		if entries[i].After, err = unmarshalSnapshot(records[i].AfterJSON); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
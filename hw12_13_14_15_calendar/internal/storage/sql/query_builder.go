@@ -31,9 +31,13 @@ func (q *UpdateQueryBuilder) Build() string {
 	return sb.String()
 }
 
+func (q *UpdateQueryBuilder) Set(setClause string) {
+	q.setClause = append(q.setClause, setClause)
+}
+
 func (q *UpdateQueryBuilder) SetIf(condition bool, setClause string) {
 	if condition {
-		q.setClause = append(q.setClause, setClause)
+		q.Set(setClause)
 	}
 }
 
@@ -3,81 +3,744 @@ package sqlstorage
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
 )
 
 type DB interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
 	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	PingContext(ctx context.Context) error
 }
 
+const eventColumns = `id, title, description, user_id, calendar_id, start_date, end_date, day, week, month,
+	last_day, last_week, last_month, notification_time, recurrence_rule, exception_dates, timezone, all_day, version,
+	deleted_at`
+
+// notDeleted excludes the events in the trash.
+const notDeleted = `deleted_at IS NULL`
+
+// readableByUser selects the events of the user $1, the events of the
+// calendars the user has access to and the events the user accepted. Trashed
+// events are excluded.
+const readableByUser = notDeleted + ` AND (user_id = $1
+	OR calendar_id IN (SELECT calendar_id FROM calendar_acl WHERE user_id = $1)
+	OR id IN (SELECT event_id FROM event_attendees WHERE user_id = $1 AND status = 'accepted'))`
+
+// restorableByUser selects the trashed events the user $1 may restore: the
+// personal events of the user and the events of the calendars the user can
+// write to.
+const restorableByUser = `deleted_at IS NOT NULL AND ((calendar_id IS NULL AND user_id = $1)
+	OR calendar_id IN (SELECT calendar_id FROM calendar_acl WHERE user_id = $1 AND role IN ('writer', 'owner')))`
+
 type Storage struct {
-	db DB
+	db        DB
+	publisher storage.ChangePublisher
 }
 
 func New(db DB) *Storage {
 	return &Storage{db: db}
 }
 
+// Ping checks the connection to the database.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// CreateEvent saves the event unless it overlaps another event of the user.
+// Events of the user are checked and saved under a transaction-level advisory
+// lock, so concurrent requests can not both take the same time.
 func (s *Storage) CreateEvent(ctx context.Context, event *models.Event) error {
 	storage.FillDates(event)
+	event.Version = 1
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	entry, err := createEvent(ctx, tx, event)
+	if err != nil {
+		return err
+	}
+	return s.commit(ctx, tx, entry)
+}
+
+// CreateEvents saves the events that do not exist yet in one transaction, so
+// none of them is saved if one fails. It returns the IDs of the saved events.
+func (s *Storage) CreateEvents(ctx context.Context, events []*models.Event) ([]string, error) {
+	for _, event := range events {
+		storage.FillDates(event)
+		event.Version = 1
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	ids := make([]string, 0, len(events))
+	entries := make([]models.HistoryEntry, 0, len(events))
+	for _, event := range events {
+		exists, err := eventExists(ctx, tx, event.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			continue
+		}
+
+		entry, err := createEvent(ctx, tx, event)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %w", event.Title, err)
+		}
+		ids = append(ids, event.ID)
+		entries = append(entries, entry)
+	}
+
+	if err := s.commit(ctx, tx, entries...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// eventExists reports whether the event is saved, in the trash or not.
+func eventExists(ctx context.Context, db selector, eventID string) (bool, error) {
+	var ids []string
+	if err := db.SelectContext(ctx, &ids, `SELECT id FROM events WHERE id = $1`, eventID); err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+// createEvent saves the event in the transaction if the user can write to its
+// calendar and it does not overlap another event of the user.
+func createEvent(ctx context.Context, tx *sqlx.Tx, event *models.Event) (models.HistoryEntry, error) {
+	if err := models.CheckEventDuration(event.StartDate, event.EndDate); err != nil {
+		return models.HistoryEntry{}, err
+	}
+	if event.CalendarID != nil {
+		calendar, err := userCalendar(ctx, tx, event.UserID, *event.CalendarID)
+		if err != nil {
+			return models.HistoryEntry{}, err
+		}
+		if err := storage.CheckRole(calendar.Role, models.RoleWriter); err != nil {
+			return models.HistoryEntry{}, err
+		}
+	}
+
+	if !event.AllowOverlap {
+		if err := checkConflicts(ctx, tx, event); err != nil {
+			return models.HistoryEntry{}, err
+		}
+	}
+
+	query := `
+	INSERT INTO events(` + eventColumns + `)
+	VALUES (:id, :title, :description, :user_id, :calendar_id, :start_date, :end_date, :day, :week, :month,
+		:last_day, :last_week, :last_month, :notification_time, NULLIF(:recurrence_rule, ''), :exception_dates,
+		COALESCE(NULLIF(:timezone, ''), 'UTC'), COALESCE(:all_day, false), :version,
+		:deleted_at)`
+
+	if _, err := tx.NamedExecContext(ctx, query, event); err != nil {
+		return models.HistoryEntry{}, err
+	}
+
+	entry := storage.NewHistoryEntry(ctx, models.ActionCreate, event.UserID, nil, event)
+	if err := saveHistory(ctx, tx, entry); err != nil {
+		return models.HistoryEntry{}, err
+	}
+	return entry, nil
+}
+
+// checkConflicts locks the events of the user and returns a
+// storage.ConflictError if the event overlaps one of them.
+func checkConflicts(ctx context.Context, tx *sqlx.Tx, event *models.Event) error {
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", event.UserID); err != nil {
+		return err
+	}
+
+	from, to := storage.ConflictWindow(event)
 
 	query := `
-	INSERT INTO events(id, title, description, user_id, start_date, end_date, day, week, month, notification_time)
-	VALUES (:id, :title, :description, :user_id, :start_date, :end_date, :day, :week, :month, :notification_time)`
+	SELECT ` + eventColumns + `
+	FROM events
+	WHERE user_id = $1 AND id <> $2 AND start_date < $4
+		AND (end_date > $3 OR recurrence_rule IS NOT NULL) AND ` + notDeleted
 
-	_, err := s.db.NamedExecContext(ctx, query, event)
-	return err
+	var events []models.Event
+	if err := tx.SelectContext(ctx, &events, query, event.UserID, event.ID, from, to); err != nil {
+		return err
+	}
+	return storage.FindConflict(event, events)
 }
 
-func (s *Storage) DeleteEvent(ctx context.Context, eventID string) error {
+// DeleteEvent moves the event to the trash. A non-zero version must match the
+// version of the event. The attendees of the event are kept for a restore, its
+// unsent notifications are dropped.
+func (s *Storage) DeleteEvent(ctx context.Context, userID int64, eventID string, version int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stored, err := lockWritableEvent(ctx, tx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckVersion(stored, version); err != nil {
+		return err
+	}
+
+	deleted := *stored
+	deletedAt := time.Now().UTC()
+	deleted.DeletedAt = &deletedAt
+	deleted.Version++
+
 	query := `
-	DELETE FROM events
+	UPDATE events
+	SET deleted_at = $2, version = $3
 	WHERE id = $1`
 
-	_, err := s.db.ExecContext(ctx, query, eventID)
-	return err
+	if _, err := tx.ExecContext(ctx, query, eventID, deletedAt, deleted.Version); err != nil {
+		return err
+	}
+
+	entry := storage.NewHistoryEntry(ctx, models.ActionDelete, userID, stored, &deleted)
+	if err := saveHistory(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	query = `
+	DELETE FROM notification_outbox
+	WHERE event_id = $1 AND sent_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, query, eventID); err != nil {
+		return err
+	}
+	return s.commit(ctx, tx, entry)
 }
 
-func (s *Storage) GetEventByDay(ctx context.Context, userID int64, day time.Time) ([]models.Event, error) {
+// GetDeletedEvents returns the trashed events the user may restore, the most
+// recently deleted first.
+func (s *Storage) GetDeletedEvents(ctx context.Context, userID int64) ([]models.Event, error) {
 	query := `
-	SELECT id, title, description, user_id, start_date, end_date, day, week, month, notification_time 
+	SELECT ` + eventColumns + `
 	FROM events
-	WHERE user_id = $1 AND day = $2`
+	WHERE ` + restorableByUser + `
+	ORDER BY deleted_at DESC, id`
 
 	var events []models.Event
-	return events, s.db.SelectContext(ctx, &events, query, userID, day)
+	return events, s.db.SelectContext(ctx, &events, query, userID)
 }
 
-func (s *Storage) GetEventByWeek(ctx context.Context, userID int64, week time.Time) ([]models.Event, error) {
+// RestoreEvent moves the event back from the trash unless it overlaps another
+// event of its owner.
+func (s *Storage) RestoreEvent(ctx context.Context, userID int64, eventID string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	event, err := lockEvent(ctx, tx, userID, eventID, "deleted_at IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	if err := checkConflicts(ctx, tx, event); err != nil {
+		return err
+	}
+
+	restored := *event
+	restored.DeletedAt = nil
+	restored.Version++
+
 	query := `
-	SELECT id, title, description, user_id, start_date, end_date, day, week, month, notification_time 
+	UPDATE events
+	SET deleted_at = NULL, version = $2
+	WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, query, eventID, restored.Version); err != nil {
+		return err
+	}
+
+	entry := storage.NewHistoryEntry(ctx, models.ActionRestore, userID, event, &restored)
+	if err := saveHistory(ctx, tx, entry); err != nil {
+		return err
+	}
+	return s.commit(ctx, tx, entry)
+}
+
+// PurgeDeletedEvents permanently deletes the events that were moved to the
+// trash before the date. The purges are recorded in the history and published
+// as deletes.
+func (s *Storage) PurgeDeletedEvents(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	entries, changes, err := s.purgeEvents(ctx, tx, storage.SchedulerActorID, "deleted_at < $1", before)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.commitChanges(tx, changes); err != nil {
+		return 0, err
+	}
+	return int64(len(entries)), nil
+}
+
+// lockWritableEvent locks the event row until the end of the transaction and
+// checks that the user may change it. Trashed events are not found.
+func lockWritableEvent(ctx context.Context, tx *sqlx.Tx, userID int64, eventID string) (*models.Event, error) {
+	return lockEvent(ctx, tx, userID, eventID, notDeleted)
+}
+
+// lockEvent locks the event row if it matches the condition and checks that
+// the user may change it.
+func lockEvent(ctx context.Context, tx *sqlx.Tx, userID int64, eventID, condition string) (*models.Event, error) {
+	query := `
+	SELECT ` + eventColumns + `
 	FROM events
-	WHERE user_id = $1 AND week = $2`
+	WHERE id = $1 AND ` + condition + `
+	FOR UPDATE`
+
+	var stored []models.Event
+	if err := tx.SelectContext(ctx, &stored, query, eventID); err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return nil, storage.ErrEventNotExist
+	}
+
+	event := &stored[0]
+	var role models.Role
+	if event.CalendarID != nil {
+		calendar, err := userCalendar(ctx, tx, userID, *event.CalendarID)
+		if err != nil {
+			return nil, err
+		}
+		role = calendar.Role
+	}
+
+	if !storage.CanWriteEvent(event, userID, role) {
+		return nil, storage.ErrForbidden
+	}
+	return event, nil
+}
+
+type readableEvent struct {
+	models.Event
+	Readable bool `db:"readable"`
+}
+
+// GetEvent returns the event if the user can read it. Recurring events are
+// not expanded.
+func (s *Storage) GetEvent(ctx context.Context, userID int64, eventID string) (*models.Event, error) {
+	query := `
+	SELECT ` + eventColumns + `, ` + readableByUser + ` AS readable
+	FROM events
+	WHERE id = $2 AND ` + notDeleted
+
+	var events []readableEvent
+	if err := s.db.SelectContext(ctx, &events, query, userID, eventID); err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, storage.ErrEventNotExist
+	}
+	if !events[0].Readable {
+		return nil, storage.ErrForbidden
+	}
+	return &events[0].Event, nil
+}
+
+func (s *Storage) GetEventByDay(ctx context.Context, userID int64, day time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	from, to := storage.DayRange(day)
+	first, last := storage.DayBuckets(from, to)
+	return s.getEvents(ctx, "day", userID, first, last, from, to, filter)
+}
+
+func (s *Storage) GetEventByWeek(ctx context.Context, userID int64, week time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	from, to := storage.WeekRange(week)
+	first, last := storage.WeekBuckets(from, to)
+	return s.getEvents(ctx, "week", userID, first, last, from, to, filter)
+}
+
+func (s *Storage) GetEventByMonth(ctx context.Context, userID int64, month time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	from, to := storage.MonthRange(month)
+	first, last := storage.MonthBuckets(from, to)
+	return s.getEvents(ctx, "month", userID, first, last, from, to, filter)
+}
+
+// GetEventsByUser returns the stored events of the user. Recurring events are
+// not expanded.
+func (s *Storage) GetEventsByUser(ctx context.Context, userID int64) ([]models.Event, error) {
+	query := `
+	SELECT ` + eventColumns + `
+	FROM events
+	WHERE user_id = $1 AND ` + notDeleted + `
+	ORDER BY start_date`
 
 	var events []models.Event
-	return events, s.db.SelectContext(ctx, &events, query, userID, week)
+	return events, s.db.SelectContext(ctx, &events, query, userID)
 }
 
-func (s *Storage) GetEventByMonth(ctx context.Context, userID int64, month time.Time) ([]models.Event, error) {
+// GetEventsInRange returns the events readable by the user and occurrences of
+// recurring events that overlap the [from, to) interval.
+func (s *Storage) GetEventsInRange(ctx context.Context, userID int64, from, to time.Time) ([]models.Event, error) {
 	query := `
-	SELECT id, title, description, user_id, start_date, end_date, day, week, month, notification_time 
+	SELECT ` + eventColumns + `
 	FROM events
-	WHERE user_id = $1 AND month = $2`
+	WHERE ` + readableByUser + ` AND start_date < $3 AND (end_date > $2 OR start_date >= $2)
+		AND recurrence_rule IS NULL`
 
 	var events []models.Event
-	return events, s.db.SelectContext(ctx, &events, query, userID, month)
+	if err := s.db.SelectContext(ctx, &events, query, userID, from, to); err != nil {
+		return nil, err
+	}
+
+	recurring, err := getRecurringEvents(ctx, s.db, readableByUser+" AND start_date < $2", userID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range recurring {
+		occurrences, err := storage.OverlappingEvents(&recurring[i], from, to)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, occurrences...)
+	}
+
+	storage.SortByStartDate(events)
+	return events, nil
 }
 
-func (s *Storage) UpdateEvent(ctx context.Context, event *models.Event) error {
-	storage.FillDates(event)
+// getEvents returns a page of the single events that occur in the [from, to)
+// interval together with the occurrences of the recurring events in it. Single
+// events are looked up by the overlap of their buckets with the buckets from
+// first to last and paginated by the (start_date, id) key, so only the rows of
+// the requested page are read.
+func (s *Storage) getEvents(ctx context.Context, bucket string, userID int64, first, last, from, to time.Time, filter models.EventFilter) ([]models.Event, string, error) { //nolint:lll
+	after, err := storage.DecodePageToken(filter.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	from, to = from.UTC(), to.UTC()
+
+	conditions, args := filterConditions(filter, userID, first, last, from, to)
+	conditions = append(conditions, readableByUser, bucket+" <= $3 AND last_"+bucket+" >= $2",
+		"start_date < $5 AND (end_date > $4 OR start_date >= $4)", "recurrence_rule IS NULL")
+	if after != nil {
+		args = append(args, after.StartDate, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(start_date, id) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `
+	SELECT ` + eventColumns + `
+	FROM events
+	WHERE ` + strings.Join(conditions, " AND ") + `
+	ORDER BY start_date, id`
+	if filter.PageSize > 0 {
+		query += fmt.Sprintf("\n\tLIMIT %d", filter.PageSize+1)
+	}
+
+	var events []models.Event
+	if err := s.db.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, "", err
+	}
+
+	conditions, args = filterConditions(filter, userID, to)
+	conditions = append(conditions, readableByUser, "start_date < $2")
+
+	recurring, err := getRecurringEvents(ctx, s.db, strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range recurring {
+		occurrences, err := storage.OverlappingEvents(&recurring[i], from, to)
+		if err != nil {
+			return nil, "", err
+		}
+		events = append(events, occurrences...)
+	}
+
+	return storage.Paginate(events, filter)
+}
+
+// filterConditions returns the SQL conditions of the filter. The arguments of
+// the conditions follow the given arguments.
+func filterConditions(filter models.EventFilter, args ...interface{}) ([]string, []interface{}) {
+	var conditions []string
+	if filter.Title != "" {
+		args = append(args, filter.Title)
+		conditions = append(conditions, fmt.Sprintf("strpos(lower(title), lower($%d)) > 0", len(args)))
+	}
+	if filter.WithNotification {
+		conditions = append(conditions, "notification_time IS NOT NULL")
+	}
+	return conditions, args
+}
+
+func getRecurringEvents(ctx context.Context, db selector, where string, args ...interface{}) ([]models.Event, error) {
+	query := `
+	SELECT ` + eventColumns + `
+	FROM events
+	WHERE recurrence_rule IS NOT NULL AND ` + where
+
+	var events []models.Event
+	return events, db.SelectContext(ctx, &events, query, args...)
+}
+
+// EnqueueNotifications adds to the outbox a notification for every event or
+// occurrence whose notification time is in the (from, to] interval. The
+// notifications are sent to the owner and to every accepted attendee of the
+// event. Single events are read and enqueued by one statement, occurrences of
+// recurring events are expanded in the same transaction. The unique
+// (event_id, user_id, notify_at) key guarantees that a notification is
+// enqueued only once.
+func (s *Storage) EnqueueNotifications(ctx context.Context, from, to time.Time) (int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `
+	INSERT INTO notification_outbox(event_id, title, date, user_id, notify_at)
+	SELECT e.id, e.title, e.start_date, r.user_id, e.notify_at
+	FROM (
+		SELECT id, title, start_date, user_id,
+			start_date - notification_time / 1000 * interval '1 microsecond' AS notify_at
+		FROM events
+		WHERE notification_time IS NOT NULL AND recurrence_rule IS NULL AND ` + notDeleted + `
+	) AS e
+	CROSS JOIN LATERAL (
+		SELECT e.user_id
+		UNION
+		SELECT user_id FROM event_attendees WHERE event_id = e.id AND status = 'accepted'
+	) AS r (user_id)
+	WHERE e.notify_at > $1 AND e.notify_at <= $2
+	ON CONFLICT (event_id, user_id, notify_at) DO NOTHING`
+
+	res, err := tx.ExecContext(ctx, query, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	query = `
+	SELECT ` + eventColumns + `
+	FROM events
+	WHERE recurrence_rule IS NOT NULL AND notification_time IS NOT NULL AND ` + notDeleted + `
+		AND start_date - notification_time / 1000 * interval '1 microsecond' <= $1`
+
+	var recurring []models.Event
+	if err := tx.SelectContext(ctx, &recurring, query, to); err != nil {
+		return 0, err
+	}
+
+	for i := range recurring {
+		notifications, err := storage.DueNotifications(&recurring[i], from, to)
+		if err != nil {
+			return 0, err
+		}
+		if len(notifications) == 0 {
+			continue
+		}
+
+		attendees, err := acceptedAttendees(ctx, tx, recurring[i].ID)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, notification := range storage.WithRecipients(notifications, attendees) {
+			query := `
+			INSERT INTO notification_outbox(event_id, title, date, user_id, notify_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (event_id, user_id, notify_at) DO NOTHING`
+
+			res, err := tx.ExecContext(ctx, query, notification.EventID, notification.Title,
+				notification.Date, notification.UserID, notification.NotifyAt)
+			if err != nil {
+				return 0, err
+			}
+
+			inserted, err := res.RowsAffected()
+			if err != nil {
+				return 0, err
+			}
+			enqueued += inserted
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return enqueued, nil
+}
+
+type outboxRecord struct {
+	ID int64 `db:"id"`
+	models.Notification
+}
+
+// ProcessOutbox locks up to limit unsent notifications, passes them to fn in
+// the order they were enqueued and marks each one as sent when fn succeeds.
+// Locked rows are skipped by concurrent callers.
+func (s *Storage) ProcessOutbox(ctx context.Context, limit int, fn func(context.Context, models.Notification) error) (int, error) { //nolint:lll
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `
+	SELECT id, event_id, title, date, user_id
+	FROM notification_outbox
+	WHERE sent_at IS NULL
+	ORDER BY id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED`
+
+	var records []outboxRecord
+	if err := tx.SelectContext(ctx, &records, query, limit); err != nil {
+		return 0, err
+	}
+
+	var processed int
+	var fnErr error
+	for i := range records {
+		if fnErr = fn(ctx, records[i].Notification); fnErr != nil {
+			break
+		}
+
+		query := `
+		UPDATE notification_outbox
+		SET sent_at = now()
+		WHERE id = $1`
+
+		if _, err := tx.ExecContext(ctx, query, records[i].ID); err != nil {
+			return 0, err
+		}
+		processed++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return processed, fnErr
+}
+
+// DeleteEventsBefore deletes the events that ended before the date. Recurring
+// events are deleted once their last occurrence has ended. Trashed events are
+// left to PurgeDeletedEvents. The purges are recorded in the history and
+// published as deletes.
+func (s *Storage) DeleteEventsBefore(ctx context.Context, date time.Time) (int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	entries, changes, err := s.purgeEvents(ctx, tx, storage.SchedulerActorID,
+		"end_date < $1 AND recurrence_rule IS NULL AND "+notDeleted, date)
+	if err != nil {
+		return 0, err
+	}
+
+	recurring, err := getRecurringEvents(ctx, tx, notDeleted+" AND end_date < $1", date)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range recurring {
+		ended, err := storage.EndedBefore(&recurring[i], date)
+		if err != nil {
+			return 0, err
+		}
+		if !ended {
+			continue
+		}
+
+		purged, purgedChanges, err := s.purgeEvents(ctx, tx, storage.SchedulerActorID, "id = $1", recurring[i].ID)
+		if err != nil {
+			return 0, err
+		}
+		entries = append(entries, purged...)
+		changes = append(changes, purgedChanges...)
+	}
+
+	if err := s.commitChanges(tx, changes); err != nil {
+		return 0, err
+	}
+	return int64(len(entries)), nil
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, userID int64, event *models.Event) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stored, err := lockWritableEvent(ctx, tx, userID, event.ID)
+	if err != nil {
+		return err
+	}
+	if err := storage.CheckVersion(stored, event.Version); err != nil {
+		return err
+	}
+
+	merged := *stored
+	storage.MergeEvent(&merged, event)
+	storage.FillDates(&merged)
+	if err := models.CheckEventDuration(merged.StartDate, merged.EndDate); err != nil {
+		return err
+	}
+
+	if !event.AllowOverlap && storage.ChangesSchedule(event) {
+		if err := checkConflicts(ctx, tx, &merged); err != nil {
+			return err
+		}
+	}
+
+	// The buckets depend on the dates, the time zone and the all-day flag, so
+	// they are always recomputed from the merged event. The dates of an
+	// all-day event are aligned to midnights as well.
+	event.StartDate, event.EndDate = merged.StartDate, merged.EndDate
+	event.Day, event.Week, event.Month = merged.Day, merged.Week, merged.Month
+	event.LastDay, event.LastWeek, event.LastMonth = merged.LastDay, merged.LastWeek, merged.LastMonth
+	event.Version = stored.Version + 1
+
+	if _, err := tx.NamedExecContext(ctx, buildUpdateQuery(event), event); err != nil {
+		return err
+	}
 
-	_, err := s.db.NamedExecContext(ctx, buildUpdateQuery(event), event)
-	return err
+	merged.Version = event.Version
+	entry := storage.NewHistoryEntry(ctx, models.ActionUpdate, userID, stored, &merged)
+	if err := saveHistory(ctx, tx, entry); err != nil {
+		return err
+	}
+	return s.commit(ctx, tx, entry)
 }
 
 func buildUpdateQuery(event *models.Event) string {
@@ -90,7 +753,15 @@ func buildUpdateQuery(event *models.Event) string {
 	qb.SetIf(!event.Day.IsZero(), "day = :day")
 	qb.SetIf(!event.Week.IsZero(), "week = :week")
 	qb.SetIf(!event.Month.IsZero(), "month = :month")
+	qb.SetIf(!event.LastDay.IsZero(), "last_day = :last_day")
+	qb.SetIf(!event.LastWeek.IsZero(), "last_week = :last_week")
+	qb.SetIf(!event.LastMonth.IsZero(), "last_month = :last_month")
 	qb.SetIf(event.NotificationTime != nil, "notification_time = :notification_time")
+	qb.SetIf(event.RecurrenceRule != nil, "recurrence_rule = NULLIF(:recurrence_rule, '')")
+	qb.SetIf(event.ExceptionDates != nil, "exception_dates = :exception_dates")
+	qb.SetIf(event.Timezone != "", "timezone = :timezone")
+	qb.SetIf(event.AllDay != nil, "all_day = :all_day")
+	qb.Set("version = :version")
 
 	qb.Where("id = :id")
 	return qb.Build()
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/rrule"
 	"github.com/snabb/isoweek"
 )
 
@@ -16,14 +19,56 @@ const (
 var (
 	ErrNoEventsFound = errors.New("no events found")
 	ErrEventNotExist = errors.New("event does not exist")
+	ErrForbidden     = errors.New("access denied")
 )
 
+// FillDates sets the day, week and month buckets of the event start and end.
+// The buckets are the local dates in the time zone of the event. All-day
+// events are first aligned to midnights in that time zone.
 func FillDates(event *models.Event) {
-	if !event.StartDate.IsZero() {
-		event.Day = getDay(event.StartDate)
-		event.Week = getWeek(event.StartDate)
-		event.Month = getMonth(event.StartDate)
+	if event.StartDate.IsZero() {
+		return
 	}
+	if event.IsAllDay() {
+		alignAllDay(event)
+	}
+
+	loc := Location(event)
+	start := event.StartDate.In(loc)
+	event.Day = getDay(start)
+	event.Week = getWeek(start)
+	event.Month = getMonth(start)
+
+	// The end is exclusive, so an event that ends at midnight does not touch
+	// the next day.
+	end := start
+	if event.EndDate.After(event.StartDate) {
+		end = event.EndDate.Add(-time.Nanosecond).In(loc)
+	}
+	event.LastDay = getDay(end)
+	event.LastWeek = getWeek(end)
+	event.LastMonth = getMonth(end)
+}
+
+// alignAllDay moves the start of the all-day event to the midnight of its day
+// and the end to the next midnight, so the event lasts at least one day.
+func alignAllDay(event *models.Event) {
+	loc := Location(event)
+
+	start := event.StartDate.In(loc)
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+
+	end := event.EndDate.In(loc)
+	midnight := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+	if midnight.Before(end) {
+		midnight = midnight.AddDate(0, 0, 1)
+	}
+	if !midnight.After(start) {
+		midnight = start.AddDate(0, 0, 1)
+	}
+
+	event.StartDate = start.In(event.StartDate.Location())
+	event.EndDate = midnight.In(event.StartDate.Location())
 }
 
 func getDay(date time.Time) time.Time {
@@ -38,3 +83,145 @@ func getWeek(date time.Time) time.Time {
 func getMonth(date time.Time) time.Time {
 	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
 }
+
+// DayRange returns the interval of the day. Days, weeks and months start at
+// midnight in the location of the given date, so their length follows DST
+// transitions.
+func DayRange(day time.Time) (time.Time, time.Time) {
+	return day, day.AddDate(0, 0, 1)
+}
+
+func WeekRange(week time.Time) (time.Time, time.Time) {
+	return week, week.AddDate(0, 0, 7)
+}
+
+func MonthRange(month time.Time) (time.Time, time.Time) {
+	return month, month.AddDate(0, 1, 0)
+}
+
+// ExpandOccurrences returns an event for every occurrence of the recurring
+// event that starts in the [from, to) interval. Occurrences keep the local
+// start time of the event in its time zone across DST transitions.
+func ExpandOccurrences(event *models.Event, from, to time.Time) ([]models.Event, error) {
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", event.ID, err)
+	}
+
+	duration := event.EndDate.Sub(event.StartDate)
+	starts := rule.Between(event.StartDate.In(Location(event)), from, to, event.ExceptionDates)
+
+	occurrences := make([]models.Event, 0, len(starts))
+	for _, start := range starts {
+		occurrence := *event
+		occurrence.StartDate = start.In(event.StartDate.Location())
+		occurrence.EndDate = occurrence.StartDate.Add(duration)
+		if event.IsAllDay() {
+			// Days around DST transitions are not 24 hours long, so all-day
+			// occurrences last the same number of days instead.
+			days := int(math.Round(duration.Hours() / 24))
+			occurrence.EndDate = start.AddDate(0, 0, days).In(event.StartDate.Location())
+		}
+		FillDates(&occurrence)
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+// Overlaps reports whether the event overlaps the [from, to) interval.
+func Overlaps(event *models.Event, from, to time.Time) bool {
+	return event.StartDate.Before(to) && event.EndDate.After(from)
+}
+
+// OccursIn reports whether the event overlaps the [from, to) interval or, if
+// it has no duration, starts in it.
+func OccursIn(event *models.Event, from, to time.Time) bool {
+	return Overlaps(event, from, to) || StartsIn(event, from, to)
+}
+
+// OverlappingEvents returns the event, or every occurrence of a recurring
+// event, that occurs in the [from, to) interval.
+func OverlappingEvents(event *models.Event, from, to time.Time) ([]models.Event, error) {
+	if !event.IsRecurring() {
+		if OccursIn(event, from, to) {
+			return []models.Event{*event}, nil
+		}
+		return nil, nil
+	}
+
+	duration := event.EndDate.Sub(event.StartDate)
+	occurrences, err := ExpandOccurrences(event, from.Add(-duration), to)
+	if err != nil {
+		return nil, err
+	}
+
+	overlapping := occurrences[:0]
+	for i := range occurrences {
+		if OccursIn(&occurrences[i], from, to) {
+			overlapping = append(overlapping, occurrences[i])
+		}
+	}
+	return overlapping, nil
+}
+
+type DueNotification struct {
+	NotifyAt time.Time
+	models.Notification
+}
+
+// DueNotifications returns the notifications of the event, or of every
+// occurrence of a recurring event, that are due in the (from, to] interval.
+func DueNotifications(event *models.Event, from, to time.Time) ([]DueNotification, error) {
+	if event.NotificationTime == nil {
+		return nil, nil
+	}
+	notificationTime := *event.NotificationTime
+
+	occurrences := []models.Event{*event}
+	if event.IsRecurring() {
+		var err error
+		occurrences, err = ExpandOccurrences(event, from.Add(notificationTime),
+			to.Add(notificationTime).Add(time.Nanosecond))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var notifications []DueNotification
+	for i := range occurrences {
+		notifyAt := occurrences[i].StartDate.Add(-notificationTime)
+		if !notifyAt.After(from) || notifyAt.After(to) {
+			continue
+		}
+
+		notifications = append(notifications, DueNotification{
+			NotifyAt: notifyAt,
+			Notification: models.Notification{
+				EventID: event.ID,
+				Title:   event.Title,
+				Date:    occurrences[i].StartDate,
+				UserID:  event.UserID,
+			},
+		})
+	}
+	return notifications, nil
+}
+
+// EndedBefore reports whether the event, or every occurrence of a recurring
+// event, ended before the date.
+func EndedBefore(event *models.Event, date time.Time) (bool, error) {
+	if !event.IsRecurring() {
+		return event.EndDate.Before(date), nil
+	}
+
+	rule, err := rrule.Parse(*event.RecurrenceRule)
+	if err != nil {
+		return false, fmt.Errorf("event %s: %w", event.ID, err)
+	}
+
+	last, ok := rule.Last(event.StartDate.In(Location(event)))
+	if !ok {
+		return false, nil
+	}
+	return last.Add(event.EndDate.Sub(event.StartDate)).Before(date), nil
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// maxZoneOffset is the largest offset of a time zone from UTC. Events are put
+// into the buckets of their own time zone, so an event that starts inside a
+// period of another time zone can be in a bucket up to this offset away.
+const maxZoneOffset = 14 * time.Hour
+
+var locations sync.Map
+
+// Location returns the time zone of the event. Events without a time zone or
+// with an unknown one are in UTC.
+func Location(event *models.Event) *time.Location {
+	if event.Timezone == "" {
+		return time.UTC
+	}
+	if loc, ok := locations.Load(event.Timezone); ok {
+		return loc.(*time.Location)
+	}
+
+	loc, err := time.LoadLocation(event.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	locations.Store(event.Timezone, loc)
+	return loc
+}
+
+// DayBuckets returns the first and the last day buckets that can hold events
+// occurring in the [from, to) interval.
+func DayBuckets(from, to time.Time) (time.Time, time.Time) {
+	return getDay(from.UTC().Add(-maxZoneOffset)), getDay(to.UTC().Add(maxZoneOffset))
+}
+
+// WeekBuckets returns the first and the last week buckets that can hold events
+// occurring in the [from, to) interval.
+func WeekBuckets(from, to time.Time) (time.Time, time.Time) {
+	return getWeek(from.UTC().Add(-maxZoneOffset)), getWeek(to.UTC().Add(maxZoneOffset))
+}
+
+// MonthBuckets returns the first and the last month buckets that can hold
+// events occurring in the [from, to) interval.
+func MonthBuckets(from, to time.Time) (time.Time, time.Time) {
+	return getMonth(from.UTC().Add(-maxZoneOffset)), getMonth(to.UTC().Add(maxZoneOffset))
+}
+
+// StartsIn reports whether the event starts in the [from, to) interval.
+func StartsIn(event *models.Event, from, to time.Time) bool {
+	return !event.StartDate.Before(from) && event.StartDate.Before(to)
+}
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// SortByDeletedAt orders trashed events from the most recently deleted one.
+// Events deleted at the same time are ordered by ID.
+func SortByDeletedAt(events []models.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].DeletedAt.Equal(*events[j].DeletedAt) {
+			return events[i].DeletedAt.After(*events[j].DeletedAt)
+		}
+		return events[i].ID < events[j].ID
+	})
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+)
+
+// ErrVersionMismatch is returned when the event has been changed since the
+// version the client expects.
+var ErrVersionMismatch = errors.New("event version does not match")
+
+// CheckVersion returns ErrVersionMismatch if the expected version is set and
+// differs from the version of the stored event. Zero expects any version.
+func CheckVersion(stored *models.Event, expected int64) error {
+	if expected != 0 && expected != stored.Version {
+		return fmt.Errorf("%w: expected %d, current %d", ErrVersionMismatch, expected, stored.Version)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/models"
+	"github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/natalya-revtova/go-practice/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStorage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	s := NewStorage(memorystorage.New())
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	require.NotEmpty(t, TraceID(ctx))
+
+	require.NoError(t, s.CreateEvent(ctx, &models.Event{ID: "1", UserID: 1}))
+	_, err := s.GetEvent(ctx, 1, "2")
+	require.ErrorIs(t, err, storage.ErrEventNotExist)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	cases := []struct {
+		name   string
+		status codes.Code
+	}{
+		{name: "Storage.CreateEvent", status: codes.Unset},
+		{name: "Storage.GetEvent", status: codes.Error},
+	}
+
+	for i, tc := range cases {
+		span := spans[i]
+		require.Equal(t, tc.name, span.Name())
+		require.Equal(t, tc.status, span.Status().Code)
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		require.Equal(t, TraceID(ctx), span.SpanContext().TraceID().String())
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	require.Empty(t, TraceID(context.Background()))
+}
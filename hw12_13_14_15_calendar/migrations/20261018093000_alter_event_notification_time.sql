@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events ALTER COLUMN notification_time TYPE bigint;
+
+CREATE INDEX events_end_date_index ON events (end_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_end_date_index;
+
+ALTER TABLE events ALTER COLUMN notification_time TYPE int;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE notification_outbox
+(
+    id          bigserial NOT NULL primary key,
+    event_id    varchar   NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    title       varchar   NOT NULL,
+    date        timestamp NOT NULL,
+    user_id     int       NOT NULL,
+    notify_at   timestamp NOT NULL,
+    created_at  timestamp NOT NULL DEFAULT now(),
+    sent_at     timestamp,
+    UNIQUE (event_id, notify_at)
+);
+
+CREATE INDEX notification_outbox_pending_index ON notification_outbox (id) WHERE sent_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE notification_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events ADD COLUMN recurrence_rule varchar;
+ALTER TABLE events ADD COLUMN exception_dates text;
+
+CREATE INDEX events_recurring_index ON events (user_id) WHERE recurrence_rule IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_recurring_index;
+
+ALTER TABLE events DROP COLUMN exception_dates;
+ALTER TABLE events DROP COLUMN recurrence_rule;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX events_user_day_page_index ON events (user_id, day, start_date, id);
+CREATE INDEX events_user_week_page_index ON events (user_id, week, start_date, id);
+CREATE INDEX events_user_month_page_index ON events (user_id, month, start_date, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_user_month_page_index;
+DROP INDEX events_user_week_page_index;
+DROP INDEX events_user_day_page_index;
+-- +goose StatementEnd
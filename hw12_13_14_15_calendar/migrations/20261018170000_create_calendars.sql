@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE calendars
+(
+    id          varchar NOT NULL primary key,
+    owner_id    int     NOT NULL,
+    name        varchar NOT NULL,
+    timezone    varchar NOT NULL DEFAULT 'UTC'
+);
+
+CREATE TABLE calendar_acl
+(
+    calendar_id varchar NOT NULL REFERENCES calendars (id) ON DELETE CASCADE,
+    user_id     int     NOT NULL,
+    role        varchar NOT NULL CHECK (role IN ('reader', 'writer', 'owner')),
+    PRIMARY KEY (calendar_id, user_id)
+);
+
+CREATE INDEX calendar_acl_user_index ON calendar_acl (user_id);
+
+ALTER TABLE events ADD COLUMN calendar_id varchar REFERENCES calendars (id) ON DELETE CASCADE;
+
+CREATE INDEX events_calendar_index ON events (calendar_id, start_date) WHERE calendar_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_calendar_index;
+
+ALTER TABLE events DROP COLUMN calendar_id;
+
+DROP TABLE calendar_acl;
+DROP TABLE calendars;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE event_attendees
+(
+    event_id    varchar NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    user_id     int     NOT NULL,
+    status      varchar NOT NULL DEFAULT 'needs-action'
+        CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative')),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX event_attendees_user_index ON event_attendees (user_id, status);
+
+ALTER TABLE notification_outbox
+    DROP CONSTRAINT notification_outbox_event_id_notify_at_key,
+    ADD CONSTRAINT notification_outbox_event_id_user_id_notify_at_key UNIQUE (event_id, user_id, notify_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM notification_outbox o
+WHERE EXISTS (
+    SELECT 1 FROM events e WHERE e.id = o.event_id AND e.user_id <> o.user_id
+);
+
+ALTER TABLE notification_outbox
+    DROP CONSTRAINT notification_outbox_event_id_user_id_notify_at_key,
+    ADD CONSTRAINT notification_outbox_event_id_notify_at_key UNIQUE (event_id, notify_at);
+
+DROP TABLE event_attendees;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events ADD COLUMN timezone varchar NOT NULL DEFAULT 'UTC';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE events
+SET day = date_trunc('day', start_date),
+    week = date_trunc('week', start_date),
+    month = date_trunc('month', start_date)
+WHERE timezone <> 'UTC';
+
+ALTER TABLE events DROP COLUMN timezone;
+-- +goose StatementEnd
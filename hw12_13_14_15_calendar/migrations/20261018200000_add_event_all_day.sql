@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events ADD COLUMN all_day boolean NOT NULL DEFAULT false;
+ALTER TABLE events ADD COLUMN last_day timestamp;
+ALTER TABLE events ADD COLUMN last_week timestamp;
+ALTER TABLE events ADD COLUMN last_month timestamp;
+
+UPDATE events
+SET last_day = GREATEST(day, date_trunc('day', e.local_end)),
+    last_week = GREATEST(week, date_trunc('week', e.local_end)),
+    last_month = GREATEST(month, date_trunc('month', e.local_end))
+FROM (
+    SELECT id, (end_date - interval '1 microsecond') AT TIME ZONE 'UTC' AT TIME ZONE timezone AS local_end
+    FROM events
+) AS e
+WHERE e.id = events.id;
+
+ALTER TABLE events ALTER COLUMN last_day SET NOT NULL;
+ALTER TABLE events ALTER COLUMN last_week SET NOT NULL;
+ALTER TABLE events ALTER COLUMN last_month SET NOT NULL;
+
+CREATE INDEX events_user_last_day_index ON events (user_id, last_day);
+CREATE INDEX events_user_last_week_index ON events (user_id, last_week);
+CREATE INDEX events_user_last_month_index ON events (user_id, last_month);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_user_last_month_index;
+DROP INDEX events_user_last_week_index;
+DROP INDEX events_user_last_day_index;
+
+ALTER TABLE events DROP COLUMN last_month;
+ALTER TABLE events DROP COLUMN last_week;
+ALTER TABLE events DROP COLUMN last_day;
+ALTER TABLE events DROP COLUMN all_day;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events ADD COLUMN deleted_at timestamp;
+
+CREATE INDEX events_deleted_at_index ON events (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_deleted_at_index;
+
+ALTER TABLE events DROP COLUMN deleted_at;
+-- +goose StatementEnd
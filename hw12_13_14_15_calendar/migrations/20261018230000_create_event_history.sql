@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE event_history
+(
+    id          bigserial PRIMARY KEY,
+    event_id    varchar   NOT NULL,
+    action      varchar   NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'purge')),
+    actor_id    bigint    NOT NULL,
+    request_id  varchar   NOT NULL DEFAULT '',
+    before      jsonb,
+    after       jsonb,
+    changed_at  timestamp NOT NULL
+);
+
+CREATE INDEX event_history_event_index ON event_history (event_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_history;
+-- +goose StatementEnd
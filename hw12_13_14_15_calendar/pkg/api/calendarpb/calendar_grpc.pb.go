@@ -20,12 +20,32 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	Calendar_CreateEvent_FullMethodName      = "/calendar.Calendar/CreateEvent"
-	Calendar_UpdateEvent_FullMethodName      = "/calendar.Calendar/UpdateEvent"
-	Calendar_DeleteEvent_FullMethodName      = "/calendar.Calendar/DeleteEvent"
-	Calendar_GetEventsByDay_FullMethodName   = "/calendar.Calendar/GetEventsByDay"
-	Calendar_GetEventsByWeek_FullMethodName  = "/calendar.Calendar/GetEventsByWeek"
-	Calendar_GetEventsByMonth_FullMethodName = "/calendar.Calendar/GetEventsByMonth"
+	Calendar_CreateEvent_FullMethodName         = "/calendar.Calendar/CreateEvent"
+	Calendar_GetEvent_FullMethodName            = "/calendar.Calendar/GetEvent"
+	Calendar_UpdateEvent_FullMethodName         = "/calendar.Calendar/UpdateEvent"
+	Calendar_DeleteEvent_FullMethodName         = "/calendar.Calendar/DeleteEvent"
+	Calendar_ListDeletedEvents_FullMethodName   = "/calendar.Calendar/ListDeletedEvents"
+	Calendar_RestoreEvent_FullMethodName        = "/calendar.Calendar/RestoreEvent"
+	Calendar_GetEventHistory_FullMethodName     = "/calendar.Calendar/GetEventHistory"
+	Calendar_GetEventsByDay_FullMethodName      = "/calendar.Calendar/GetEventsByDay"
+	Calendar_GetEventsByWeek_FullMethodName     = "/calendar.Calendar/GetEventsByWeek"
+	Calendar_GetEventsByMonth_FullMethodName    = "/calendar.Calendar/GetEventsByMonth"
+	Calendar_GetEventsInRange_FullMethodName    = "/calendar.Calendar/GetEventsInRange"
+	Calendar_FreeBusy_FullMethodName            = "/calendar.Calendar/FreeBusy"
+	Calendar_ExportCalendar_FullMethodName      = "/calendar.Calendar/ExportCalendar"
+	Calendar_ImportCalendar_FullMethodName      = "/calendar.Calendar/ImportCalendar"
+	Calendar_CreateCalendar_FullMethodName      = "/calendar.Calendar/CreateCalendar"
+	Calendar_GetCalendar_FullMethodName         = "/calendar.Calendar/GetCalendar"
+	Calendar_ListCalendars_FullMethodName       = "/calendar.Calendar/ListCalendars"
+	Calendar_UpdateCalendar_FullMethodName      = "/calendar.Calendar/UpdateCalendar"
+	Calendar_DeleteCalendar_FullMethodName      = "/calendar.Calendar/DeleteCalendar"
+	Calendar_ListACL_FullMethodName             = "/calendar.Calendar/ListACL"
+	Calendar_SetACLEntry_FullMethodName         = "/calendar.Calendar/SetACLEntry"
+	Calendar_DeleteACLEntry_FullMethodName      = "/calendar.Calendar/DeleteACLEntry"
+	Calendar_InviteAttendees_FullMethodName     = "/calendar.Calendar/InviteAttendees"
+	Calendar_RespondToInvitation_FullMethodName = "/calendar.Calendar/RespondToInvitation"
+	Calendar_ListInvitations_FullMethodName     = "/calendar.Calendar/ListInvitations"
+	Calendar_WatchEvents_FullMethodName         = "/calendar.Calendar/WatchEvents"
 )
 
 // CalendarClient is the client API for Calendar service.
@@ -33,11 +53,34 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CalendarClient interface {
 	CreateEvent(ctx context.Context, in *CreateEventRequest, opts ...grpc.CallOption) (*CreateEventResponse, error)
+	GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Event, error)
 	UpdateEvent(ctx context.Context, in *Event, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeleteEvent(ctx context.Context, in *DeleteEventRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListDeletedEvents(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EventsResponse, error)
+	RestoreEvent(ctx context.Context, in *RestoreEventRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetEventHistory(ctx context.Context, in *GetEventHistoryRequest, opts ...grpc.CallOption) (*EventHistoryResponse, error)
 	GetEventsByDay(ctx context.Context, in *EventsRequestByDate, opts ...grpc.CallOption) (*EventsResponse, error)
 	GetEventsByWeek(ctx context.Context, in *EventsRequestByDate, opts ...grpc.CallOption) (*EventsResponse, error)
 	GetEventsByMonth(ctx context.Context, in *EventsRequestByDate, opts ...grpc.CallOption) (*EventsResponse, error)
+	GetEventsInRange(ctx context.Context, in *EventsRequestByRange, opts ...grpc.CallOption) (*EventsResponse, error)
+	FreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error)
+	ExportCalendar(ctx context.Context, in *ExportCalendarRequest, opts ...grpc.CallOption) (*CalendarData, error)
+	ImportCalendar(ctx context.Context, in *ImportCalendarRequest, opts ...grpc.CallOption) (*ImportCalendarResponse, error)
+	CreateCalendar(ctx context.Context, in *CreateCalendarRequest, opts ...grpc.CallOption) (*CreateCalendarResponse, error)
+	GetCalendar(ctx context.Context, in *GetCalendarRequest, opts ...grpc.CallOption) (*CalendarInfo, error)
+	ListCalendars(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CalendarsResponse, error)
+	UpdateCalendar(ctx context.Context, in *CalendarInfo, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteCalendar(ctx context.Context, in *DeleteCalendarRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListACL(ctx context.Context, in *ListACLRequest, opts ...grpc.CallOption) (*ACLResponse, error)
+	SetACLEntry(ctx context.Context, in *ACLEntry, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DeleteACLEntry(ctx context.Context, in *DeleteACLEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	InviteAttendees(ctx context.Context, in *InviteAttendeesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RespondToInvitation(ctx context.Context, in *RespondToInvitationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListInvitations(ctx context.Context, in *ListInvitationsRequest, opts ...grpc.CallOption) (*InvitationsResponse, error)
+	// WatchEvents streams the changes of the events the user can read. It is
+	// not served by the gateway, HTTP clients use the server-sent events of
+	// /v1/calendar/events/watch instead.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (Calendar_WatchEventsClient, error)
 }
 
 type calendarClient struct {
@@ -57,6 +100,15 @@ func (c *calendarClient) CreateEvent(ctx context.Context, in *CreateEventRequest
 	return out, nil
 }
 
+func (c *calendarClient) GetEvent(ctx context.Context, in *GetEventRequest, opts ...grpc.CallOption) (*Event, error) {
+	out := new(Event)
+	err := c.cc.Invoke(ctx, Calendar_GetEvent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calendarClient) UpdateEvent(ctx context.Context, in *Event, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, Calendar_UpdateEvent_FullMethodName, in, out, opts...)
@@ -75,6 +127,33 @@ func (c *calendarClient) DeleteEvent(ctx context.Context, in *DeleteEventRequest
 	return out, nil
 }
 
+func (c *calendarClient) ListDeletedEvents(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EventsResponse, error) {
+	out := new(EventsResponse)
+	err := c.cc.Invoke(ctx, Calendar_ListDeletedEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) RestoreEvent(ctx context.Context, in *RestoreEventRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_RestoreEvent_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) GetEventHistory(ctx context.Context, in *GetEventHistoryRequest, opts ...grpc.CallOption) (*EventHistoryResponse, error) {
+	out := new(EventHistoryResponse)
+	err := c.cc.Invoke(ctx, Calendar_GetEventHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *calendarClient) GetEventsByDay(ctx context.Context, in *EventsRequestByDate, opts ...grpc.CallOption) (*EventsResponse, error) {
 	out := new(EventsResponse)
 	err := c.cc.Invoke(ctx, Calendar_GetEventsByDay_FullMethodName, in, out, opts...)
@@ -102,16 +181,206 @@ func (c *calendarClient) GetEventsByMonth(ctx context.Context, in *EventsRequest
 	return out, nil
 }
 
+func (c *calendarClient) GetEventsInRange(ctx context.Context, in *EventsRequestByRange, opts ...grpc.CallOption) (*EventsResponse, error) {
+	out := new(EventsResponse)
+	err := c.cc.Invoke(ctx, Calendar_GetEventsInRange_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) FreeBusy(ctx context.Context, in *FreeBusyRequest, opts ...grpc.CallOption) (*FreeBusyResponse, error) {
+	out := new(FreeBusyResponse)
+	err := c.cc.Invoke(ctx, Calendar_FreeBusy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) ExportCalendar(ctx context.Context, in *ExportCalendarRequest, opts ...grpc.CallOption) (*CalendarData, error) {
+	out := new(CalendarData)
+	err := c.cc.Invoke(ctx, Calendar_ExportCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) ImportCalendar(ctx context.Context, in *ImportCalendarRequest, opts ...grpc.CallOption) (*ImportCalendarResponse, error) {
+	out := new(ImportCalendarResponse)
+	err := c.cc.Invoke(ctx, Calendar_ImportCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) CreateCalendar(ctx context.Context, in *CreateCalendarRequest, opts ...grpc.CallOption) (*CreateCalendarResponse, error) {
+	out := new(CreateCalendarResponse)
+	err := c.cc.Invoke(ctx, Calendar_CreateCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) GetCalendar(ctx context.Context, in *GetCalendarRequest, opts ...grpc.CallOption) (*CalendarInfo, error) {
+	out := new(CalendarInfo)
+	err := c.cc.Invoke(ctx, Calendar_GetCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) ListCalendars(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CalendarsResponse, error) {
+	out := new(CalendarsResponse)
+	err := c.cc.Invoke(ctx, Calendar_ListCalendars_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) UpdateCalendar(ctx context.Context, in *CalendarInfo, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_UpdateCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) DeleteCalendar(ctx context.Context, in *DeleteCalendarRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_DeleteCalendar_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) ListACL(ctx context.Context, in *ListACLRequest, opts ...grpc.CallOption) (*ACLResponse, error) {
+	out := new(ACLResponse)
+	err := c.cc.Invoke(ctx, Calendar_ListACL_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) SetACLEntry(ctx context.Context, in *ACLEntry, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_SetACLEntry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) DeleteACLEntry(ctx context.Context, in *DeleteACLEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_DeleteACLEntry_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) InviteAttendees(ctx context.Context, in *InviteAttendeesRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_InviteAttendees_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) RespondToInvitation(ctx context.Context, in *RespondToInvitationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Calendar_RespondToInvitation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) ListInvitations(ctx context.Context, in *ListInvitationsRequest, opts ...grpc.CallOption) (*InvitationsResponse, error) {
+	out := new(InvitationsResponse)
+	err := c.cc.Invoke(ctx, Calendar_ListInvitations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calendarClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (Calendar_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Calendar_ServiceDesc.Streams[0], Calendar_WatchEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &calendarWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Calendar_WatchEventsClient interface {
+	Recv() (*EventChange, error)
+	grpc.ClientStream
+}
+
+type calendarWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *calendarWatchEventsClient) Recv() (*EventChange, error) {
+	m := new(EventChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // CalendarServer is the server API for Calendar service.
 // All implementations must embed UnimplementedCalendarServer
 // for forward compatibility
 type CalendarServer interface {
 	CreateEvent(context.Context, *CreateEventRequest) (*CreateEventResponse, error)
+	GetEvent(context.Context, *GetEventRequest) (*Event, error)
 	UpdateEvent(context.Context, *Event) (*emptypb.Empty, error)
 	DeleteEvent(context.Context, *DeleteEventRequest) (*emptypb.Empty, error)
+	ListDeletedEvents(context.Context, *emptypb.Empty) (*EventsResponse, error)
+	RestoreEvent(context.Context, *RestoreEventRequest) (*emptypb.Empty, error)
+	GetEventHistory(context.Context, *GetEventHistoryRequest) (*EventHistoryResponse, error)
 	GetEventsByDay(context.Context, *EventsRequestByDate) (*EventsResponse, error)
 	GetEventsByWeek(context.Context, *EventsRequestByDate) (*EventsResponse, error)
 	GetEventsByMonth(context.Context, *EventsRequestByDate) (*EventsResponse, error)
+	GetEventsInRange(context.Context, *EventsRequestByRange) (*EventsResponse, error)
+	FreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error)
+	ExportCalendar(context.Context, *ExportCalendarRequest) (*CalendarData, error)
+	ImportCalendar(context.Context, *ImportCalendarRequest) (*ImportCalendarResponse, error)
+	CreateCalendar(context.Context, *CreateCalendarRequest) (*CreateCalendarResponse, error)
+	GetCalendar(context.Context, *GetCalendarRequest) (*CalendarInfo, error)
+	ListCalendars(context.Context, *emptypb.Empty) (*CalendarsResponse, error)
+	UpdateCalendar(context.Context, *CalendarInfo) (*emptypb.Empty, error)
+	DeleteCalendar(context.Context, *DeleteCalendarRequest) (*emptypb.Empty, error)
+	ListACL(context.Context, *ListACLRequest) (*ACLResponse, error)
+	SetACLEntry(context.Context, *ACLEntry) (*emptypb.Empty, error)
+	DeleteACLEntry(context.Context, *DeleteACLEntryRequest) (*emptypb.Empty, error)
+	InviteAttendees(context.Context, *InviteAttendeesRequest) (*emptypb.Empty, error)
+	RespondToInvitation(context.Context, *RespondToInvitationRequest) (*emptypb.Empty, error)
+	ListInvitations(context.Context, *ListInvitationsRequest) (*InvitationsResponse, error)
+	// WatchEvents streams the changes of the events the user can read. It is
+	// not served by the gateway, HTTP clients use the server-sent events of
+	// /v1/calendar/events/watch instead.
+	WatchEvents(*WatchEventsRequest, Calendar_WatchEventsServer) error
 	mustEmbedUnimplementedCalendarServer()
 }
 
@@ -122,12 +391,24 @@ type UnimplementedCalendarServer struct {
 func (UnimplementedCalendarServer) CreateEvent(context.Context, *CreateEventRequest) (*CreateEventResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateEvent not implemented")
 }
+func (UnimplementedCalendarServer) GetEvent(context.Context, *GetEventRequest) (*Event, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEvent not implemented")
+}
 func (UnimplementedCalendarServer) UpdateEvent(context.Context, *Event) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateEvent not implemented")
 }
 func (UnimplementedCalendarServer) DeleteEvent(context.Context, *DeleteEventRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEvent not implemented")
 }
+func (UnimplementedCalendarServer) ListDeletedEvents(context.Context, *emptypb.Empty) (*EventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedEvents not implemented")
+}
+func (UnimplementedCalendarServer) RestoreEvent(context.Context, *RestoreEventRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEvent not implemented")
+}
+func (UnimplementedCalendarServer) GetEventHistory(context.Context, *GetEventHistoryRequest) (*EventHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventHistory not implemented")
+}
 func (UnimplementedCalendarServer) GetEventsByDay(context.Context, *EventsRequestByDate) (*EventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventsByDay not implemented")
 }
@@ -137,6 +418,54 @@ func (UnimplementedCalendarServer) GetEventsByWeek(context.Context, *EventsReque
 func (UnimplementedCalendarServer) GetEventsByMonth(context.Context, *EventsRequestByDate) (*EventsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventsByMonth not implemented")
 }
+func (UnimplementedCalendarServer) GetEventsInRange(context.Context, *EventsRequestByRange) (*EventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventsInRange not implemented")
+}
+func (UnimplementedCalendarServer) FreeBusy(context.Context, *FreeBusyRequest) (*FreeBusyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreeBusy not implemented")
+}
+func (UnimplementedCalendarServer) ExportCalendar(context.Context, *ExportCalendarRequest) (*CalendarData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportCalendar not implemented")
+}
+func (UnimplementedCalendarServer) ImportCalendar(context.Context, *ImportCalendarRequest) (*ImportCalendarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportCalendar not implemented")
+}
+func (UnimplementedCalendarServer) CreateCalendar(context.Context, *CreateCalendarRequest) (*CreateCalendarResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCalendar not implemented")
+}
+func (UnimplementedCalendarServer) GetCalendar(context.Context, *GetCalendarRequest) (*CalendarInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCalendar not implemented")
+}
+func (UnimplementedCalendarServer) ListCalendars(context.Context, *emptypb.Empty) (*CalendarsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCalendars not implemented")
+}
+func (UnimplementedCalendarServer) UpdateCalendar(context.Context, *CalendarInfo) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCalendar not implemented")
+}
+func (UnimplementedCalendarServer) DeleteCalendar(context.Context, *DeleteCalendarRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCalendar not implemented")
+}
+func (UnimplementedCalendarServer) ListACL(context.Context, *ListACLRequest) (*ACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListACL not implemented")
+}
+func (UnimplementedCalendarServer) SetACLEntry(context.Context, *ACLEntry) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetACLEntry not implemented")
+}
+func (UnimplementedCalendarServer) DeleteACLEntry(context.Context, *DeleteACLEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteACLEntry not implemented")
+}
+func (UnimplementedCalendarServer) InviteAttendees(context.Context, *InviteAttendeesRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteAttendees not implemented")
+}
+func (UnimplementedCalendarServer) RespondToInvitation(context.Context, *RespondToInvitationRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToInvitation not implemented")
+}
+func (UnimplementedCalendarServer) ListInvitations(context.Context, *ListInvitationsRequest) (*InvitationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvitations not implemented")
+}
+func (UnimplementedCalendarServer) WatchEvents(*WatchEventsRequest, Calendar_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
 func (UnimplementedCalendarServer) mustEmbedUnimplementedCalendarServer() {}
 
 // UnsafeCalendarServer may be embedded to opt out of forward compatibility for this service.
@@ -168,6 +497,24 @@ func _Calendar_CreateEvent_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Calendar_GetEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).GetEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_GetEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).GetEvent(ctx, req.(*GetEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Calendar_UpdateEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Event)
 	if err := dec(in); err != nil {
@@ -204,6 +551,60 @@ func _Calendar_DeleteEvent_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Calendar_ListDeletedEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).ListDeletedEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_ListDeletedEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).ListDeletedEvents(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_RestoreEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).RestoreEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_RestoreEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).RestoreEvent(ctx, req.(*RestoreEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_GetEventHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).GetEventHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_GetEventHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).GetEventHistory(ctx, req.(*GetEventHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Calendar_GetEventsByDay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(EventsRequestByDate)
 	if err := dec(in); err != nil {
@@ -258,6 +659,297 @@ func _Calendar_GetEventsByMonth_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Calendar_GetEventsInRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EventsRequestByRange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).GetEventsInRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_GetEventsInRange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).GetEventsInRange(ctx, req.(*EventsRequestByRange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_FreeBusy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreeBusyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).FreeBusy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_FreeBusy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).FreeBusy(ctx, req.(*FreeBusyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_ExportCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).ExportCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_ExportCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).ExportCalendar(ctx, req.(*ExportCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_ImportCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).ImportCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_ImportCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).ImportCalendar(ctx, req.(*ImportCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_CreateCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).CreateCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_CreateCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).CreateCalendar(ctx, req.(*CreateCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_GetCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).GetCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_GetCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).GetCalendar(ctx, req.(*GetCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_ListCalendars_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).ListCalendars(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_ListCalendars_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).ListCalendars(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_UpdateCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CalendarInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).UpdateCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_UpdateCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).UpdateCalendar(ctx, req.(*CalendarInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_DeleteCalendar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCalendarRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).DeleteCalendar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_DeleteCalendar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).DeleteCalendar(ctx, req.(*DeleteCalendarRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_ListACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListACLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).ListACL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_ListACL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).ListACL(ctx, req.(*ListACLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_SetACLEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ACLEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).SetACLEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_SetACLEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).SetACLEntry(ctx, req.(*ACLEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_DeleteACLEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteACLEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).DeleteACLEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_DeleteACLEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).DeleteACLEntry(ctx, req.(*DeleteACLEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_InviteAttendees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteAttendeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).InviteAttendees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_InviteAttendees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).InviteAttendees(ctx, req.(*InviteAttendeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_RespondToInvitation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToInvitationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).RespondToInvitation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_RespondToInvitation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).RespondToInvitation(ctx, req.(*RespondToInvitationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_ListInvitations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvitationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalendarServer).ListInvitations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Calendar_ListInvitations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalendarServer).ListInvitations(ctx, req.(*ListInvitationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Calendar_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalendarServer).WatchEvents(m, &calendarWatchEventsServer{stream})
+}
+
+type Calendar_WatchEventsServer interface {
+	Send(*EventChange) error
+	grpc.ServerStream
+}
+
+type calendarWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *calendarWatchEventsServer) Send(m *EventChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // Calendar_ServiceDesc is the grpc.ServiceDesc for Calendar service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -269,6 +961,10 @@ var Calendar_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateEvent",
 			Handler:    _Calendar_CreateEvent_Handler,
 		},
+		{
+			MethodName: "GetEvent",
+			Handler:    _Calendar_GetEvent_Handler,
+		},
 		{
 			MethodName: "UpdateEvent",
 			Handler:    _Calendar_UpdateEvent_Handler,
@@ -277,6 +973,18 @@ var Calendar_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteEvent",
 			Handler:    _Calendar_DeleteEvent_Handler,
 		},
+		{
+			MethodName: "ListDeletedEvents",
+			Handler:    _Calendar_ListDeletedEvents_Handler,
+		},
+		{
+			MethodName: "RestoreEvent",
+			Handler:    _Calendar_RestoreEvent_Handler,
+		},
+		{
+			MethodName: "GetEventHistory",
+			Handler:    _Calendar_GetEventHistory_Handler,
+		},
 		{
 			MethodName: "GetEventsByDay",
 			Handler:    _Calendar_GetEventsByDay_Handler,
@@ -289,7 +997,73 @@ var Calendar_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEventsByMonth",
 			Handler:    _Calendar_GetEventsByMonth_Handler,
 		},
+		{
+			MethodName: "GetEventsInRange",
+			Handler:    _Calendar_GetEventsInRange_Handler,
+		},
+		{
+			MethodName: "FreeBusy",
+			Handler:    _Calendar_FreeBusy_Handler,
+		},
+		{
+			MethodName: "ExportCalendar",
+			Handler:    _Calendar_ExportCalendar_Handler,
+		},
+		{
+			MethodName: "ImportCalendar",
+			Handler:    _Calendar_ImportCalendar_Handler,
+		},
+		{
+			MethodName: "CreateCalendar",
+			Handler:    _Calendar_CreateCalendar_Handler,
+		},
+		{
+			MethodName: "GetCalendar",
+			Handler:    _Calendar_GetCalendar_Handler,
+		},
+		{
+			MethodName: "ListCalendars",
+			Handler:    _Calendar_ListCalendars_Handler,
+		},
+		{
+			MethodName: "UpdateCalendar",
+			Handler:    _Calendar_UpdateCalendar_Handler,
+		},
+		{
+			MethodName: "DeleteCalendar",
+			Handler:    _Calendar_DeleteCalendar_Handler,
+		},
+		{
+			MethodName: "ListACL",
+			Handler:    _Calendar_ListACL_Handler,
+		},
+		{
+			MethodName: "SetACLEntry",
+			Handler:    _Calendar_SetACLEntry_Handler,
+		},
+		{
+			MethodName: "DeleteACLEntry",
+			Handler:    _Calendar_DeleteACLEntry_Handler,
+		},
+		{
+			MethodName: "InviteAttendees",
+			Handler:    _Calendar_InviteAttendees_Handler,
+		},
+		{
+			MethodName: "RespondToInvitation",
+			Handler:    _Calendar_RespondToInvitation_Handler,
+		},
+		{
+			MethodName: "ListInvitations",
+			Handler:    _Calendar_ListInvitations_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Calendar_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "calendar.proto",
 }
@@ -0,0 +1,9 @@
+package calendarpb
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v2 specification of the REST API generated from
+// calendar.proto.
+//
+//go:embed calendar.swagger.json
+var OpenAPI []byte